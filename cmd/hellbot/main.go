@@ -2,8 +2,10 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"log/slog"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
@@ -20,6 +22,7 @@ import (
 	valkeystore "github.com/ametis70/hellbot/internal/adapter/store/valkey"
 	"github.com/ametis70/hellbot/internal/app"
 	"github.com/ametis70/hellbot/internal/config"
+	"github.com/ametis70/hellbot/internal/domain"
 	"github.com/ametis70/hellbot/internal/port"
 )
 
@@ -35,6 +38,9 @@ func main() {
 		logger.Error("failed to load config", "path", configPath, "error", err)
 		os.Exit(1)
 	}
+	for _, w := range cfg.Warnings {
+		logger.Warn("config warning", "warning", w)
+	}
 
 	globalTZ, err := time.LoadLocation(cfg.Timezone)
 	if err != nil {
@@ -42,8 +48,58 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Build notifiers from config
 	var closers []func() error
+
+	// Build store
+	var store interface {
+		port.CampaignStore
+		port.EventStore
+		port.MilestoneStore
+		port.MessageRefStore
+		port.SubscriptionStore
+	}
+
+	switch cfg.Store.Type {
+	case config.StoreTypeValkey:
+		opts, err := config.ResolveValkeyStoreOptions(cfg.Store.Options)
+		if err != nil {
+			logger.Error("invalid valkey store options", "error", err)
+			os.Exit(1)
+		}
+		vs, err := valkeystore.New(valkeystore.Options{
+			Addr:     opts.Addr,
+			Password: opts.Password,
+			DB:       opts.DB,
+		})
+		if err != nil {
+			logger.Error("failed to connect to valkey", "error", err)
+			os.Exit(1)
+		}
+		closers = append(closers, vs.Close)
+		store = vs
+		logger.Info("store initialized", "type", "valkey", "addr", opts.Addr)
+	case config.StoreTypeSQLite:
+		opts, err := config.ResolveSQLiteStoreOptions(cfg.Store.Options)
+		if err != nil {
+			logger.Error("invalid sqlite store options", "error", err)
+			os.Exit(1)
+		}
+		ss, err := sqlitestore.New(sqlitestore.Options{
+			Path: opts.Path,
+		})
+		if err != nil {
+			logger.Error("failed to open sqlite store", "error", err)
+			os.Exit(1)
+		}
+		closers = append(closers, ss.Close)
+		store = ss
+		logger.Info("store initialized", "type", "sqlite", "path", opts.Path)
+	default:
+		store = memory.New()
+		logger.Info("store initialized", "type", "memory")
+	}
+
+	// Build notifiers from config
 	notifiers := make([]port.Notifier, 0, len(cfg.Notifiers))
 
 	for _, n := range cfg.Notifiers {
@@ -62,10 +118,12 @@ func main() {
 					os.Exit(1)
 				}
 			}
-			notifiers = append(notifiers, stdout.New(stdout.Options{
+			notifiers = append(notifiers, app.FilterKinds(stdout.New(stdout.Options{
 				Timezone:  tz,
+				Locale:    localeOr(opts.Locale, cfg.Locale),
+				Style:     opts.Style,
 				Templates: opts.Templates,
-			}))
+			}), n.Events))
 			logger.Info("registered notifier", "id", n.ID, "type", n.Type)
 
 		case config.NotifierTypeDiscord:
@@ -74,18 +132,40 @@ func main() {
 				logger.Error("invalid discord notifier options", "id", n.ID, "error", err)
 				os.Exit(1)
 			}
+			var subs port.SubscriptionStore
+			if opts.Subscriptions {
+				subs = store
+			}
 			dn, err := discordnotifier.New(discordnotifier.Options{
+				ID:        n.ID,
 				Token:     opts.Token,
 				ChannelID: opts.ChannelID,
 				GuildID:   opts.GuildID,
+				Locale:    localeOr(opts.Locale, cfg.Locale),
+				Style:     opts.Style,
+				Embeds:    opts.Embeds,
 				Templates: opts.Templates,
+
+				BoardChannelID:  opts.BoardChannelID,
+				Refs:            store,
+				Mentions:        discordMentions(opts.Mentions),
+				Routes:          discordRoutes(opts.Routes),
+				Subscriptions:   subs,
+				ScheduledEvents: opts.ScheduledEvents,
+				Threads:         opts.Threads,
+				Presence:        discordPresence(opts.Presence),
+				AdminRoles:      opts.AdminRoles,
+
+				WebhookURL:      opts.WebhookURL,
+				WebhookProfile:  discordnotifier.WebhookProfile{Username: opts.Username, AvatarURL: opts.AvatarURL},
+				FactionProfiles: discordProfiles(opts.FactionProfiles),
 			}, logger)
 			if err != nil {
 				logger.Error("failed to create discord notifier", "id", n.ID, "error", err)
 				os.Exit(1)
 			}
 			closers = append(closers, dn.Close)
-			notifiers = append(notifiers, dn)
+			notifiers = append(notifiers, app.FilterKinds(dn, n.Events))
 			logger.Info("registered notifier", "id", n.ID, "type", n.Type)
 
 		case config.NotifierTypeTelegram:
@@ -102,17 +182,30 @@ func main() {
 					os.Exit(1)
 				}
 			}
+			var subs port.SubscriptionStore
+			if opts.Subscriptions {
+				subs = store
+			}
 			tn, err := telegramnotifier.New(telegramnotifier.Options{
-				Token:     opts.Token,
-				ChatID:    opts.ChatID,
-				Timezone:  tz,
-				Templates: opts.Templates,
+				ID:              n.ID,
+				Token:           opts.Token,
+				ChatID:          opts.ChatID,
+				Timezone:        tz,
+				Locale:          localeOr(opts.Locale, cfg.Locale),
+				Style:           opts.Style,
+				Templates:       opts.Templates,
+				MessageThreadID: opts.MessageThreadID,
+				FactionThreads:  telegramThreads(opts.FactionThreads),
+				AllowedChats:    opts.AllowedChats,
+				AllowedUsers:    opts.AllowedUsers,
+				Subscriptions:   subs,
+				Webhook:         telegramWebhook(opts.Webhook),
 			}, logger)
 			if err != nil {
 				logger.Error("failed to create telegram notifier", "id", n.ID, "error", err)
 				os.Exit(1)
 			}
-			notifiers = append(notifiers, tn)
+			notifiers = append(notifiers, app.FilterKinds(tn, n.Events))
 			closers = append(closers, tn.Close)
 			logger.Info("registered notifier", "id", n.ID, "type", n.Type)
 		case config.NotifierTypeWebhook:
@@ -130,7 +223,7 @@ func main() {
 				logger.Error("failed to create webhook notifier", "id", n.ID, "error", err)
 				os.Exit(1)
 			}
-			notifiers = append(notifiers, wn)
+			notifiers = append(notifiers, app.FilterKinds(wn, n.Events))
 			logger.Info("registered notifier", "id", n.ID, "type", n.Type)
 		}
 	}
@@ -156,60 +249,74 @@ func main() {
 		fetcher = helldivers1api.New(apiOpts, logger)
 	}
 
-	// Build store
-	var store interface {
-		port.CampaignStore
-		port.EventStore
+	// Register interactive commands on notifiers that support them.
+	for _, n := range notifiers {
+		if c, ok := app.Unwrap(n).(port.Commander); ok {
+			c.RegisterCommands(store)
+		}
 	}
 
-	switch cfg.Store.Type {
-	case config.StoreTypeValkey:
-		opts, err := config.ResolveValkeyStoreOptions(cfg.Store.Options)
-		if err != nil {
-			logger.Error("invalid valkey store options", "error", err)
-			os.Exit(1)
-		}
-		vs, err := valkeystore.New(valkeystore.Options{
-			Addr:     opts.Addr,
-			Password: opts.Password,
-			DB:       opts.DB,
-		})
-		if err != nil {
-			logger.Error("failed to connect to valkey", "error", err)
-			os.Exit(1)
-		}
-		closers = append(closers, vs.Close)
-		store = vs
-		logger.Info("store initialized", "type", "valkey", "addr", opts.Addr)
-	case config.StoreTypeSQLite:
-		opts, err := config.ResolveSQLiteStoreOptions(cfg.Store.Options)
-		if err != nil {
-			logger.Error("invalid sqlite store options", "error", err)
-			os.Exit(1)
-		}
-		ss, err := sqlitestore.New(sqlitestore.Options{
-			Path: opts.Path,
-		})
-		if err != nil {
-			logger.Error("failed to open sqlite store", "error", err)
-			os.Exit(1)
+	poller := app.New(fetcher, store, store, notifiers, cfg.PollInterval, logger)
+
+	// Feed every poll to notifiers that keep live messages.
+	for _, n := range notifiers {
+		if o, ok := app.Unwrap(n).(port.CampaignObserver); ok {
+			poller.Observe(o)
 		}
-		closers = append(closers, ss.Close)
-		store = ss
-		logger.Info("store initialized", "type", "sqlite", "path", opts.Path)
-	default:
-		store = memory.New()
-		logger.Info("store initialized", "type", "memory")
 	}
 
-	// Register interactive commands on notifiers that support them.
+	// Let admin commands request an immediate poll.
 	for _, n := range notifiers {
-		if c, ok := n.(port.Commander); ok {
-			c.RegisterCommands(store)
+		if u, ok := app.Unwrap(n).(port.PollTriggerUser); ok {
+			u.UsePollTrigger(poller)
 		}
 	}
 
-	poller := app.New(fetcher, store, store, notifiers, cfg.PollInterval, logger)
+	playerRules := domain.PlayerAlertRules{
+		Thresholds:    cfg.Players.Thresholds,
+		ChangePercent: cfg.Players.ChangePercent,
+		Window:        cfg.Players.ChangeWindow,
+		Hysteresis:    cfg.Players.ThresholdHysteresis,
+	}
+	if playerRules.Enabled() {
+		poller.WatchPlayers(playerRules)
+		logger.Info("player alerts enabled", "thresholds", playerRules.Thresholds, "change_percent", playerRules.ChangePercent, "change_window", playerRules.Window)
+	}
+
+	milestoneRules := domain.MilestoneRules{
+		domain.MilestoneKills:            cfg.Milestones.Kills,
+		domain.MilestoneDeaths:           cfg.Milestones.Deaths,
+		domain.MilestoneAccidentals:      cfg.Milestones.Accidentals,
+		domain.MilestoneMissions:         cfg.Milestones.Missions,
+		domain.MilestonePlanetsLiberated: cfg.Milestones.PlanetsLiberated,
+	}
+	if milestoneRules.Enabled() {
+		poller.WatchMilestones(milestoneRules, store)
+		logger.Info("milestones enabled")
+	}
+
+	// Serve notifiers that receive requests, e.g. Telegram webhooks.
+	mux := http.NewServeMux()
+	routes := 0
+	for _, n := range notifiers {
+		if r, ok := app.Unwrap(n).(port.HTTPRouter); ok {
+			if pattern, handler := r.HTTPRoute(); pattern != "" {
+				mux.Handle(pattern, handler)
+				routes++
+			}
+		}
+	}
+	var server *http.Server
+	if routes > 0 {
+		server = &http.Server{Addr: cfg.HTTP.Listen, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+		go func() {
+			if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				logger.Error("http server failed", "listen", cfg.HTTP.Listen, "error", err)
+				cancel()
+			}
+		}()
+		logger.Info("http server listening", "listen", cfg.HTTP.Listen)
+	}
 
 	logger.Info("hellbot starting", "config", configPath, "poll_interval", cfg.PollInterval)
 	if err := poller.Run(ctx); err != nil {
@@ -217,6 +324,14 @@ func main() {
 		os.Exit(1)
 	}
 
+	if server != nil {
+		shutdownCtx, stop := context.WithTimeout(context.Background(), 5*time.Second)
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			logger.Error("error stopping http server", "error", err)
+		}
+		stop()
+	}
+
 	for _, close := range closers {
 		if err := close(); err != nil {
 			logger.Error("error closing notifier", "error", err)
@@ -225,3 +340,93 @@ func main() {
 
 	logger.Info("hellbot stopped")
 }
+
+// localeOr returns the notifier's locale, or the global one when unset.
+func localeOr(notifier, global domain.Locale) domain.Locale {
+	if notifier != "" {
+		return notifier
+	}
+	return global
+}
+
+// discordRoutes converts validated routes from the config.
+func discordRoutes(routes []config.DiscordRoute) []discordnotifier.Route {
+	out := make([]discordnotifier.Route, 0, len(routes))
+	for _, r := range routes {
+		out = append(out, discordnotifier.Route{
+			ChannelID: r.ChannelID,
+			Factions:  parseEnemies(r.Factions),
+			Kinds:     r.Events,
+			Templates: r.Templates,
+		})
+	}
+	return out
+}
+
+// discordProfiles converts validated per-faction webhook profiles.
+func discordProfiles(profiles map[string]config.DiscordWebhookProfile) map[domain.Enemy]discordnotifier.WebhookProfile {
+	out := make(map[domain.Enemy]discordnotifier.WebhookProfile, len(profiles))
+	for name, p := range profiles {
+		if e, ok := domain.ParseEnemy(name); ok {
+			out[e] = discordnotifier.WebhookProfile{Username: p.Username, AvatarURL: p.AvatarURL}
+		}
+	}
+	return out
+}
+
+// telegramThreads converts the faction_threads keys from the config to
+// factions.
+func telegramThreads(threads map[string]int) map[domain.Enemy]int {
+	out := make(map[domain.Enemy]int, len(threads))
+	for name, id := range threads {
+		if e, ok := domain.ParseEnemy(name); ok {
+			out[e] = id
+		}
+	}
+	return out
+}
+
+// telegramWebhook converts the webhook from the config, or returns nil in
+// polling mode.
+func telegramWebhook(w *config.TelegramWebhook) *telegramnotifier.Webhook {
+	if w == nil {
+		return nil
+	}
+	return &telegramnotifier.Webhook{URL: w.URL, SecretToken: w.SecretToken}
+}
+
+// discordPresence converts the presence formats from the config, or returns
+// nil when the presence is disabled.
+func discordPresence(p *config.DiscordPresence) *discordnotifier.Presence {
+	if p == nil {
+		return nil
+	}
+	return &discordnotifier.Presence{Defend: p.Defend, Attack: p.Attack, War: p.War, Interval: p.Interval}
+}
+
+// discordMentions converts validated mention rules from the config.
+func discordMentions(rules []config.DiscordMention) []discordnotifier.Mention {
+	out := make([]discordnotifier.Mention, 0, len(rules))
+	for _, r := range rules {
+		out = append(out, discordnotifier.Mention{
+			Factions:   parseEnemies(r.Factions),
+			Kinds:      r.Events,
+			SuperEarth: r.SuperEarth,
+			Roles:      r.Roles,
+			Users:      r.Users,
+			Everyone:   r.Everyone,
+		})
+	}
+	return out
+}
+
+// parseEnemies converts validated faction names.
+func parseEnemies(names []string) []domain.Enemy {
+	var out []domain.Enemy
+	for _, n := range names {
+		if e, ok := domain.ParseEnemy(n); ok {
+			out = append(out, e)
+		}
+	}
+	return out
+}
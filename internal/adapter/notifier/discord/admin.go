@@ -0,0 +1,254 @@
+package discord
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/ametis70/hellbot/internal/domain"
+	"github.com/ametis70/hellbot/internal/port"
+)
+
+// muteRefKey is the MessageRefStore key holding the Unix time the
+// notifier with ID notifierID is muted until.
+func muteRefKey(notifierID string) string {
+	return "discord:muted_until:" + notifierID
+}
+
+// adminPermissions is the default permission for admin commands. Server
+// admins can grant the command to other roles in the server's integration
+// settings; AdminRoles must then list those roles too.
+var adminPermissions int64 = discordgo.PermissionManageGuild
+
+// adminCommand groups the admin subcommands. It is hidden from members
+// without Manage Server and unavailable in DMs.
+func adminCommand() *discordgo.ApplicationCommand {
+	duration := textOption(
+		localized{"duration", "duración"},
+		localized{"How long to mute, e.g. 30m or 2h", "Cuánto tiempo silenciar, p. ej. 30m o 2h"},
+		true)
+	sub := func(name, description localized, options ...*discordgo.ApplicationCommandOption) *discordgo.ApplicationCommandOption {
+		return &discordgo.ApplicationCommandOption{
+			Type:                     discordgo.ApplicationCommandOptionSubCommand,
+			Name:                     name.en,
+			NameLocalizations:        name.localizations(),
+			Description:              description.en,
+			DescriptionLocalizations: description.localizations(),
+			Options:                  options,
+		}
+	}
+	cmd := command(
+		localized{"admin", "admin"},
+		localized{"Manage hellbot on this server", "Administra hellbot en este servidor"},
+		sub(localized{"mute", "silenciar"}, localized{"Stop posting event notifications for a while", "Deja de publicar notificaciones de eventos por un tiempo"}, duration),
+		sub(localized{"unmute", "reactivar"}, localized{"Resume event notifications", "Reanuda las notificaciones de eventos"}),
+		sub(localized{"poll", "consultar"}, localized{"Fetch the war status now", "Consulta el estado de la guerra ahora"}),
+		sub(localized{"board", "tablero"}, localized{"Re-post the war board at the bottom of its channel", "Vuelve a publicar el tablero de guerra al final de su canal"}),
+		sub(localized{"health", "salud"}, localized{"Show the bot's health", "Muestra el estado del bot"}),
+	)
+	cmd.DefaultMemberPermissions = &adminPermissions
+	cmd.Contexts = &[]discordgo.InteractionContextType{discordgo.InteractionContextGuild}
+	return cmd
+}
+
+// UsePollTrigger implements port.PollTriggerUser for /admin poll.
+func (n *DiscordNotifier) UsePollTrigger(t port.PollTrigger) {
+	n.pollTrigger = t
+}
+
+// isAdmin reports whether m may run admin commands: it has Manage Server or
+// Administrator, or one of opts.AdminRoles.
+func (n *DiscordNotifier) isAdmin(m *discordgo.Member) bool {
+	if m == nil {
+		return false
+	}
+	if m.Permissions&(discordgo.PermissionManageGuild|discordgo.PermissionAdministrator) != 0 {
+		return true
+	}
+	return slices.ContainsFunc(m.Roles, func(r string) bool { return slices.Contains(n.opts.AdminRoles, r) })
+}
+
+func (n *DiscordNotifier) handleAdminCommand(s *discordgo.Session, i *discordgo.InteractionCreate, data discordgo.ApplicationCommandInteractionData) {
+	if len(data.Options) == 0 {
+		return
+	}
+	sub := data.Options[0]
+	allowed := n.isAdmin(i.Member)
+	n.logger.Info("discord: admin command",
+		"command", "admin "+sub.Name,
+		"user", interactionUserID(i),
+		"guild", i.GuildID,
+		"channel", i.ChannelID,
+		"duration", optionValue(sub.Options, "duration"),
+		"allowed", allowed,
+	)
+	if !allowed {
+		n.respondEphemeral(s, i, n.catalog.AdminRequired)
+		return
+	}
+	n.respondEphemeral(s, i, n.runAdmin(sub.Name, sub.Options, s.HeartbeatLatency()))
+}
+
+// runAdmin runs an admin subcommand and returns the reply. Failures are
+// logged; the reply only says what could not be done.
+func (n *DiscordNotifier) runAdmin(name string, options []*discordgo.ApplicationCommandInteractionDataOption, latency time.Duration) string {
+	switch name {
+	case "mute":
+		duration := optionValue(options, "duration")
+		until, err := n.mute(duration, time.Now())
+		if errors.Is(err, errInvalidDuration) {
+			return fmt.Sprintf(n.catalog.MuteInvalidDuration, duration)
+		}
+		if err != nil {
+			n.logger.Error("discord: muting failed", "error", err)
+			return n.catalog.MuteFailed
+		}
+		return fmt.Sprintf(n.catalog.Muted, fmt.Sprintf("<t:%d:f>", until.Unix()))
+	case "unmute":
+		if err := n.setMutedUntil(time.Time{}); err != nil {
+			n.logger.Error("discord: unmuting failed", "error", err)
+			return n.catalog.UnmuteFailed
+		}
+		return n.catalog.Unmuted
+	case "poll":
+		if n.pollTrigger == nil {
+			return n.catalog.PollUnavailable
+		}
+		n.pollTrigger.TriggerPoll()
+		return n.catalog.PollRequested
+	case "board":
+		err := n.repostBoard()
+		if errors.Is(err, errNoBoard) {
+			return n.catalog.BoardUnavailable
+		}
+		if err != nil {
+			n.logger.Error("discord: re-posting the war board failed", "error", err)
+			return n.catalog.RepostFailed
+		}
+		return n.catalog.Reposted
+	case "health":
+		return n.health(latency, time.Now())
+	}
+	return n.catalog.UnknownAdminCommand
+}
+
+// errInvalidDuration and errNoBoard are the admin failures the user can fix,
+// so their replies say why.
+var (
+	errInvalidDuration = errors.New("invalid duration")
+	errNoBoard         = errors.New("no war board is configured")
+)
+
+// mute parses a positive duration and mutes notifications until now plus it.
+func (n *DiscordNotifier) mute(duration string, now time.Time) (time.Time, error) {
+	d, err := time.ParseDuration(strings.TrimSpace(duration))
+	if err != nil || d <= 0 {
+		return time.Time{}, fmt.Errorf("%w %q", errInvalidDuration, duration)
+	}
+	until := now.Add(d)
+	return until, n.setMutedUntil(until)
+}
+
+// setMutedUntil mutes notifications until t, or unmutes them when t is zero,
+// and stores it so the mute survives restarts.
+func (n *DiscordNotifier) setMutedUntil(t time.Time) error {
+	n.mu.Lock()
+	n.mutedUntil = t
+	n.mu.Unlock()
+	if n.opts.Refs == nil {
+		return nil
+	}
+	value := ""
+	if !t.IsZero() {
+		value = strconv.FormatInt(t.Unix(), 10)
+	}
+	if err := n.opts.Refs.SaveMessageRef(muteRefKey(n.opts.ID), value); err != nil {
+		return fmt.Errorf("saving mute: %w", err)
+	}
+	return nil
+}
+
+// loadMute restores a mute saved before a restart.
+func (n *DiscordNotifier) loadMute() error {
+	if n.opts.Refs == nil {
+		return nil
+	}
+	value, err := n.opts.Refs.GetMessageRef(muteRefKey(n.opts.ID))
+	if err != nil || value == "" {
+		return err
+	}
+	unix, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return fmt.Errorf("parsing mute %q: %w", value, err)
+	}
+	n.mu.Lock()
+	n.mutedUntil = time.Unix(unix, 0)
+	n.mu.Unlock()
+	return nil
+}
+
+// muted reports whether notifications are muted at now.
+func (n *DiscordNotifier) muted(now time.Time) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return now.Before(n.mutedUntil)
+}
+
+// repostBoard sends the war board again with the latest status.
+func (n *DiscordNotifier) repostBoard() error {
+	if n.board == nil {
+		return errNoBoard
+	}
+	c, err := n.latestCampaign()
+	if err != nil {
+		return err
+	}
+	return n.board.repost(embed(n.catalog.StatusMessage(c, nil)))
+}
+
+// health summarizes the bot's state for /admin health.
+func (n *DiscordNotifier) health(latency time.Duration, now time.Time) string {
+	var sb strings.Builder
+	sb.WriteString("🩺 **hellbot health**\n")
+	fmt.Fprintf(&sb, "Uptime: %s\n", now.Sub(n.started).Round(time.Second))
+	fmt.Fprintf(&sb, "Gateway latency: %s\n", latency.Round(time.Millisecond))
+
+	switch c, err := n.latestCampaign(); {
+	case err != nil:
+		fmt.Fprintf(&sb, "Last poll: ⚠️ %s\n", err)
+	case c == nil || c.Time.IsZero():
+		sb.WriteString("Last poll: none yet\n")
+	default:
+		fmt.Fprintf(&sb, "Last poll: <t:%d:R>\n", c.Time.Unix())
+	}
+
+	n.mu.Lock()
+	until := n.mutedUntil
+	n.mu.Unlock()
+	if now.Before(until) {
+		fmt.Fprintf(&sb, "Notifications: 🔇 muted until <t:%d:f>\n", until.Unix())
+	} else {
+		sb.WriteString("Notifications: 🔊 on\n")
+	}
+
+	if n.board != nil {
+		fmt.Fprintf(&sb, "War board: <#%s>\n", n.board.channelID)
+	} else {
+		sb.WriteString("War board: off\n")
+	}
+	fmt.Fprintf(&sb, "Channels: %d", len(n.routes))
+	return sb.String()
+}
+
+// latestCampaign returns the latest campaign from the status provider.
+func (n *DiscordNotifier) latestCampaign() (*domain.CampaignStatus, error) {
+	if n.provider == nil {
+		return nil, errors.New("no status provider registered")
+	}
+	return n.provider.LatestCampaign()
+}
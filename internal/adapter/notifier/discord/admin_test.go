@@ -0,0 +1,229 @@
+package discord
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/ametis70/hellbot/internal/adapter/store/memory"
+	"github.com/ametis70/hellbot/internal/domain"
+	"github.com/ametis70/hellbot/internal/testutil"
+)
+
+// countingTrigger counts poll requests.
+type countingTrigger int
+
+func (c *countingTrigger) TriggerPoll() { *c++ }
+
+// asAdmin stores the mute in refs and lets the "mods" role run /admin.
+func asAdmin(refs *memory.MemoryStore) func(*Options) {
+	return func(o *Options) {
+		o.Refs = refs
+		o.AdminRoles = []string{"mods"}
+	}
+}
+
+func TestAdminCommand_Registration(t *testing.T) {
+	cmd := adminCommand()
+	if cmd.DefaultMemberPermissions == nil || *cmd.DefaultMemberPermissions != discordgo.PermissionManageGuild {
+		t.Errorf("expected Manage Server as default permission, got %v", cmd.DefaultMemberPermissions)
+	}
+	if cmd.Contexts == nil || len(*cmd.Contexts) != 1 || (*cmd.Contexts)[0] != discordgo.InteractionContextGuild {
+		t.Errorf("expected a guild-only command, got %v", cmd.Contexts)
+	}
+	var subs []string
+	for _, o := range cmd.Options {
+		subs = append(subs, o.Name)
+	}
+	if got := strings.Join(subs, ","); got != "mute,unmute,poll,board,health" {
+		t.Errorf("unexpected subcommands %s", got)
+	}
+}
+
+func TestIsAdmin(t *testing.T) {
+	n := newTestNotifier(t, asAdmin(memory.New()))
+	for _, tc := range []struct {
+		name   string
+		member *discordgo.Member
+		want   bool
+	}{
+		{"no member", nil, false},
+		{"manage server", &discordgo.Member{Permissions: discordgo.PermissionManageGuild}, true},
+		{"administrator", &discordgo.Member{Permissions: discordgo.PermissionAdministrator}, true},
+		{"admin role", &discordgo.Member{Roles: []string{"everyone", "mods"}}, true},
+		{"other roles", &discordgo.Member{Roles: []string{"everyone"}, Permissions: discordgo.PermissionSendMessages}, false},
+	} {
+		if got := n.isAdmin(tc.member); got != tc.want {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestAdmin_MuteSurvivesRestart(t *testing.T) {
+	refs := memory.New()
+	n := newTestNotifier(t, asAdmin(refs))
+	if reply := n.runAdmin("mute", []*discordgo.ApplicationCommandInteractionDataOption{stringOption("duration", "2h")}, 0); !strings.HasPrefix(reply, "🔇") {
+		t.Fatalf("unexpected reply %q", reply)
+	}
+	if !n.muted(time.Now()) || n.muted(time.Now().Add(3*time.Hour)) {
+		t.Error("expected notifications to be muted for two hours")
+	}
+
+	restarted := newTestNotifier(t, asAdmin(refs))
+	if !restarted.muted(time.Now()) {
+		t.Error("expected the mute to be restored")
+	}
+
+	if reply := restarted.runAdmin("unmute", nil, 0); !strings.HasPrefix(reply, "🔊") {
+		t.Errorf("unexpected reply %q", reply)
+	}
+	if restarted.muted(time.Now()) {
+		t.Error("expected notifications to be resumed")
+	}
+	if v, _ := refs.GetMessageRef(muteRefKey("main")); v != "" {
+		t.Errorf("expected the stored mute to be cleared, got %q", v)
+	}
+}
+
+func TestAdmin_MuteOnlyThisNotifier(t *testing.T) {
+	refs := memory.New()
+	n := newTestNotifier(t, asAdmin(refs))
+	other := newTestNotifier(t, asAdmin(refs), func(o *Options) { o.ID = "other" })
+	n.runAdmin("mute", []*discordgo.ApplicationCommandInteractionDataOption{stringOption("duration", "2h")}, 0)
+
+	if err := other.loadMute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if other.muted(time.Now()) {
+		t.Error("expected another notifier to stay unmuted")
+	}
+}
+
+func TestAdmin_MuteRejectsBadDuration(t *testing.T) {
+	n := newTestNotifier(t, asAdmin(memory.New()))
+	for _, d := range []string{"", "soon", "-1h"} {
+		if reply := n.runAdmin("mute", []*discordgo.ApplicationCommandInteractionDataOption{stringOption("duration", d)}, 0); !strings.HasPrefix(reply, "⚠️") {
+			t.Errorf("duration %q: expected an error reply, got %q", d, reply)
+		}
+	}
+	if n.muted(time.Now()) {
+		t.Error("expected notifications to stay on")
+	}
+}
+
+func TestAdmin_Poll(t *testing.T) {
+	n := newTestNotifier(t, asAdmin(memory.New()))
+	if reply := n.runAdmin("poll", nil, 0); !strings.HasPrefix(reply, "⚠️") {
+		t.Errorf("expected an error without a trigger, got %q", reply)
+	}
+	var trigger countingTrigger
+	n.UsePollTrigger(&trigger)
+	n.runAdmin("poll", nil, 0)
+	if trigger != 1 {
+		t.Errorf("expected one poll request, got %d", trigger)
+	}
+}
+
+func TestAdmin_RepostBoard(t *testing.T) {
+	refs := memory.New()
+	_ = refs.SaveMessageRef(boardRefKey("board"), "old")
+	api := &fakeBoardAPI{}
+	n := newTestNotifier(t, asAdmin(refs))
+	n.board = &board{api: api, channelID: "board", refs: refs}
+	n.provider = campaignProvider{testutil.CampaignWithNoDefend()}
+
+	if reply := n.runAdmin("board", nil, 0); reply != "📌 War board re-posted." {
+		t.Fatalf("unexpected reply %q", reply)
+	}
+	if api.sent != 1 || len(api.pinned) != 1 || len(api.unpinned) != 1 || api.unpinned[0] != "old" {
+		t.Errorf("expected a new pinned board and the old one unpinned, got %+v", api)
+	}
+	if id, _ := refs.GetMessageRef(boardRefKey("board")); id != "msg-1" {
+		t.Errorf("expected the new board to be stored, got %q", id)
+	}
+
+	n.board = nil
+	if reply := n.runAdmin("board", nil, 0); !strings.HasPrefix(reply, "⚠️") {
+		t.Errorf("expected an error without a board, got %q", reply)
+	}
+}
+
+func TestAdmin_Health(t *testing.T) {
+	n := newTestNotifier(t, asAdmin(memory.New()))
+	n.started = time.Now().Add(-time.Hour)
+	c := testutil.CampaignWithNoDefend()
+	c.Time = time.Unix(1784501941, 0)
+	n.provider = campaignProvider{c}
+	_, _ = n.mute("1h", time.Now())
+
+	reply := n.runAdmin("health", nil, 42*time.Millisecond)
+	for _, want := range []string{"Uptime: 1h0m", "Gateway latency: 42ms", "Last poll: <t:1784501941:R>", "muted until", "War board: off"} {
+		if !strings.Contains(reply, want) {
+			t.Errorf("expected %q in health reply:\n%s", want, reply)
+		}
+	}
+}
+
+// fakeSessionAPI records channel messages, threads and scheduled events.
+type fakeSessionAPI struct {
+	fakeChannelAPI
+	fakeScheduleAPI
+	dms int
+}
+
+func (f *fakeSessionAPI) UserChannelCreate(recipientID string, _ ...discordgo.RequestOption) (*discordgo.Channel, error) {
+	f.dms++
+	return &discordgo.Channel{ID: "dm-" + recipientID}, nil
+}
+
+func TestAdmin_MuteKeepsThreadsAndScheduledEventsInSync(t *testing.T) {
+	refs := memory.New()
+	n := newTestNotifier(t, withThreads(refs), scheduledIn("guild", refs), withSubscriptions(refs))
+	_ = refs.SaveSubscription(n.subscriptionScope(), domain.Subscription{ID: "42"})
+	api := &fakeSessionAPI{}
+
+	e := testutil.DefendEventActive()
+	e.EndTime = time.Now().Add(time.Hour)
+	started := domain.EventMessage{Kind: domain.EventKindDefend, Transition: domain.EventTransitionStarted, DefendEvent: e}
+	if err := n.notify(api, started, time.Now()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := n.mute("2h", time.Now()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	done := *e
+	done.Status = domain.EventStatusSuccess
+	succeeded := domain.EventMessage{Kind: domain.EventKindDefend, Transition: domain.EventTransitionSucceeded, DefendEvent: &done}
+	if err := n.notify(api, succeeded, time.Now()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := len(api.sent["events"]); got != 1 {
+		t.Errorf("expected only the start message in the channel, got %d", got)
+	}
+	if api.dms != 1 {
+		t.Errorf("expected only the start DM, got %d", api.dms)
+	}
+	if len(api.archived) != 1 || len(api.sent["thread-events"]) != 1 {
+		t.Errorf("expected the thread to get the outcome and be archived, got %v", api.archived)
+	}
+	if edits := api.edits["sched-1"]; len(edits) != 2 || edits[1].Status != discordgo.GuildScheduledEventStatusCompleted {
+		t.Errorf("expected the scheduled event to be completed, got %+v", edits)
+	}
+}
+
+func TestAdmin_FailureRepliesAreLocalized(t *testing.T) {
+	n := newTestNotifier(t, asAdmin(memory.New()), func(o *Options) { o.Locale = domain.LocaleSpanish })
+	n.board = &board{api: &fakeBoardAPI{}, channelID: "board", refs: memory.New()}
+	n.provider = &testutil.ErrorStore{}
+
+	if reply := n.runAdmin("board", nil, 0); reply != domain.CatalogFor(domain.LocaleSpanish).RepostFailed {
+		t.Errorf("expected the localized failure without the error, got %q", reply)
+	}
+	if reply := n.runAdmin("mute", []*discordgo.ApplicationCommandInteractionDataOption{stringOption("duration", "soon")}, 0); !strings.Contains(reply, `"soon"`) || !strings.HasPrefix(reply, "⚠️ Duración") {
+		t.Errorf("expected a Spanish reply naming the duration, got %q", reply)
+	}
+}
@@ -0,0 +1,138 @@
+package discord
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/ametis70/hellbot/internal/domain"
+	"github.com/ametis70/hellbot/internal/port"
+)
+
+// boardAPI is the part of *discordgo.Session the war board uses.
+type boardAPI interface {
+	ChannelMessageSendEmbed(channelID string, embed *discordgo.MessageEmbed, options ...discordgo.RequestOption) (*discordgo.Message, error)
+	ChannelMessageEditEmbed(channelID, messageID string, embed *discordgo.MessageEmbed, options ...discordgo.RequestOption) (*discordgo.Message, error)
+	ChannelMessagePin(channelID, messageID string, options ...discordgo.RequestOption) error
+	ChannelMessageUnpin(channelID, messageID string, options ...discordgo.RequestOption) error
+}
+
+// board keeps a single pinned status message in a channel up to date. The
+// message ID is kept in refs so the same message is edited across restarts.
+// mu guards against a repost from a command during an update.
+type board struct {
+	mu        sync.Mutex
+	api       boardAPI
+	channelID string
+	refs      port.MessageRefStore
+	messageID string
+}
+
+// boardRefKey is the MessageRefStore key for the board in channelID.
+func boardRefKey(channelID string) string {
+	return "discord:board:" + channelID
+}
+
+// load reads the board message ID from refs unless it is already known.
+func (b *board) load() error {
+	if b.messageID != "" || b.refs == nil {
+		return nil
+	}
+	id, err := b.refs.GetMessageRef(boardRefKey(b.channelID))
+	if err != nil {
+		return fmt.Errorf("loading board message ID: %w", err)
+	}
+	b.messageID = id
+	return nil
+}
+
+// update edits the board message with e. If no message exists yet, or it was
+// deleted, a new one is sent, pinned and remembered.
+func (b *board) update(e *discordgo.MessageEmbed) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.load(); err != nil {
+		return err
+	}
+
+	if b.messageID != "" {
+		_, err := b.api.ChannelMessageEditEmbed(b.channelID, b.messageID, e)
+		if err == nil {
+			return nil
+		}
+		if !isUnknownMessage(err) {
+			return fmt.Errorf("editing board message: %w", err)
+		}
+	}
+
+	return b.create(e)
+}
+
+// repost sends e as a new board message, pins it and unpins the old one, so
+// the board moves to the bottom of the channel.
+func (b *board) repost(e *discordgo.MessageEmbed) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.load(); err != nil {
+		return err
+	}
+	old := b.messageID
+	if err := b.create(e); err != nil {
+		return err
+	}
+	if old != "" {
+		if err := b.api.ChannelMessageUnpin(b.channelID, old); err != nil && !isUnknownMessage(err) {
+			return fmt.Errorf("unpinning old board message: %w", err)
+		}
+	}
+	return nil
+}
+
+func (b *board) create(e *discordgo.MessageEmbed) error {
+	m, err := b.api.ChannelMessageSendEmbed(b.channelID, e)
+	if err != nil {
+		return fmt.Errorf("sending board message: %w", err)
+	}
+	b.messageID = m.ID
+
+	if b.refs != nil {
+		if err := b.refs.SaveMessageRef(boardRefKey(b.channelID), m.ID); err != nil {
+			return fmt.Errorf("saving board message ID: %w", err)
+		}
+	}
+	if err := b.api.ChannelMessagePin(b.channelID, m.ID); err != nil {
+		return fmt.Errorf("pinning board message: %w", err)
+	}
+	return nil
+}
+
+// isUnknownMessage reports whether err is Discord's "Unknown Message" error,
+// returned when the message was deleted.
+func isUnknownMessage(err error) bool {
+	var restErr *discordgo.RESTError
+	return errors.As(err, &restErr) && restErr.Message != nil && restErr.Message.Code == discordgo.ErrCodeUnknownMessage
+}
+
+// ObserveCampaign implements port.CampaignObserver. When a board channel is
+// configured it edits the pinned war board with the latest status, and with
+// threads enabled it posts event progress to their threads. With a presence
+// configured it also updates the bot's status.
+func (n *DiscordNotifier) ObserveCampaign(c *domain.CampaignStatus) {
+	if n.opts.Threads && n.session != nil {
+		n.postProgress(n.session, c)
+	}
+	if n.presence != nil {
+		if err := n.presence.update(n.presence.render(n.catalog, c), time.Now()); err != nil {
+			n.logger.Error("discord: failed to update presence", "error", err)
+		}
+	}
+	if n.board == nil {
+		return
+	}
+	if err := n.board.update(embed(n.catalog.StatusMessage(c, nil))); err != nil {
+		n.logger.Error("discord: failed to update war board", "channel", n.board.channelID, "error", err)
+	}
+}
@@ -0,0 +1,114 @@
+package discord
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/ametis70/hellbot/internal/adapter/store/memory"
+)
+
+// fakeBoardAPI records board calls. Messages in deleted fail to edit with
+// Discord's Unknown Message error.
+type fakeBoardAPI struct {
+	sent     int
+	edited   []string
+	pinned   []string
+	unpinned []string
+	deleted  map[string]bool
+	editErr  error
+}
+
+func (f *fakeBoardAPI) ChannelMessageSendEmbed(channelID string, e *discordgo.MessageEmbed, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+	f.sent++
+	return &discordgo.Message{ID: fmt.Sprintf("msg-%d", f.sent), ChannelID: channelID}, nil
+}
+
+func (f *fakeBoardAPI) ChannelMessageEditEmbed(channelID, messageID string, e *discordgo.MessageEmbed, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+	if f.deleted[messageID] {
+		return nil, &discordgo.RESTError{Message: &discordgo.APIErrorMessage{Code: discordgo.ErrCodeUnknownMessage}}
+	}
+	if f.editErr != nil {
+		return nil, f.editErr
+	}
+	f.edited = append(f.edited, messageID)
+	return &discordgo.Message{ID: messageID, ChannelID: channelID}, nil
+}
+
+func (f *fakeBoardAPI) ChannelMessagePin(channelID, messageID string, _ ...discordgo.RequestOption) error {
+	f.pinned = append(f.pinned, messageID)
+	return nil
+}
+
+func (f *fakeBoardAPI) ChannelMessageUnpin(channelID, messageID string, _ ...discordgo.RequestOption) error {
+	f.unpinned = append(f.unpinned, messageID)
+	return nil
+}
+
+func TestBoard_CreatesAndPins(t *testing.T) {
+	api := &fakeBoardAPI{}
+	refs := memory.New()
+	b := &board{api: api, channelID: "chan", refs: refs}
+
+	if err := b.update(&discordgo.MessageEmbed{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if api.sent != 1 || len(api.pinned) != 1 || api.pinned[0] != "msg-1" {
+		t.Errorf("expected one sent and pinned message, got sent=%d pinned=%v", api.sent, api.pinned)
+	}
+	if id, _ := refs.GetMessageRef(boardRefKey("chan")); id != "msg-1" {
+		t.Errorf("expected stored ref msg-1, got %q", id)
+	}
+
+	if err := b.update(&discordgo.MessageEmbed{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if api.sent != 1 || len(api.edited) != 1 {
+		t.Errorf("expected second update to edit, got sent=%d edited=%v", api.sent, api.edited)
+	}
+}
+
+func TestBoard_EditsStoredMessage(t *testing.T) {
+	api := &fakeBoardAPI{}
+	refs := memory.New()
+	_ = refs.SaveMessageRef(boardRefKey("chan"), "old")
+	b := &board{api: api, channelID: "chan", refs: refs}
+
+	if err := b.update(&discordgo.MessageEmbed{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if api.sent != 0 || len(api.edited) != 1 || api.edited[0] != "old" {
+		t.Errorf("expected stored message to be edited, got sent=%d edited=%v", api.sent, api.edited)
+	}
+}
+
+func TestBoard_RecreatesDeletedMessage(t *testing.T) {
+	api := &fakeBoardAPI{deleted: map[string]bool{"old": true}}
+	refs := memory.New()
+	_ = refs.SaveMessageRef(boardRefKey("chan"), "old")
+	b := &board{api: api, channelID: "chan", refs: refs}
+
+	if err := b.update(&discordgo.MessageEmbed{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if api.sent != 1 || len(api.pinned) != 1 {
+		t.Errorf("expected a new pinned message, got sent=%d pinned=%v", api.sent, api.pinned)
+	}
+	if id, _ := refs.GetMessageRef(boardRefKey("chan")); id != "msg-1" {
+		t.Errorf("expected stored ref to be replaced, got %q", id)
+	}
+}
+
+func TestBoard_OtherEditErrorDoesNotRecreate(t *testing.T) {
+	api := &fakeBoardAPI{editErr: errors.New("rate limited")}
+	b := &board{api: api, channelID: "chan", messageID: "old"}
+
+	if err := b.update(&discordgo.MessageEmbed{}); err == nil {
+		t.Error("expected error")
+	}
+	if api.sent != 0 {
+		t.Errorf("expected no new message, got %d", api.sent)
+	}
+}
@@ -0,0 +1,189 @@
+package discord
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/ametis70/hellbot/internal/domain"
+)
+
+// localized is a command, option or choice string in English, the default
+// shown by Discord, and its Spanish translation.
+type localized struct {
+	en, es string
+}
+
+// localizations returns the translations of t for Discord's Spanish locales.
+func (t localized) localizations() map[discordgo.Locale]string {
+	return map[discordgo.Locale]string{discordgo.SpanishES: t.es, discordgo.SpanishLATAM: t.es}
+}
+
+// command returns a slash command with localized name and description.
+func command(name, description localized, options ...*discordgo.ApplicationCommandOption) *discordgo.ApplicationCommand {
+	names, descriptions := name.localizations(), description.localizations()
+	return &discordgo.ApplicationCommand{
+		Name:                     name.en,
+		NameLocalizations:        &names,
+		Description:              description.en,
+		DescriptionLocalizations: &descriptions,
+		Options:                  options,
+	}
+}
+
+// textOption returns a string option with localized name and description.
+func textOption(name, description localized, required bool, choices ...*discordgo.ApplicationCommandOptionChoice) *discordgo.ApplicationCommandOption {
+	return &discordgo.ApplicationCommandOption{
+		Type:                     discordgo.ApplicationCommandOptionString,
+		Name:                     name.en,
+		NameLocalizations:        name.localizations(),
+		Description:              description.en,
+		DescriptionLocalizations: description.localizations(),
+		Required:                 required,
+		Choices:                  choices,
+	}
+}
+
+// choice returns an option choice with a localized name.
+func choice(name localized, value string) *discordgo.ApplicationCommandOptionChoice {
+	return &discordgo.ApplicationCommandOptionChoice{Name: name.en, NameLocalizations: name.localizations(), Value: value}
+}
+
+// factionValues are the faction choice values, as accepted by domain.ParseEnemy.
+var factionValues = map[domain.Enemy]string{
+	domain.EnemyBug:        "bugs",
+	domain.EnemyCyborg:     "cyborgs",
+	domain.EnemyIlluminate: "illuminate",
+}
+
+// factionOption is the faction filter shared by several commands. Choice
+// names use the catalogs' faction names.
+func factionOption(required bool) *discordgo.ApplicationCommandOption {
+	en, es := domain.CatalogFor(domain.LocaleEnglish), domain.CatalogFor(domain.LocaleSpanish)
+	var choices []*discordgo.ApplicationCommandOptionChoice
+	for _, e := range []domain.Enemy{domain.EnemyBug, domain.EnemyCyborg, domain.EnemyIlluminate} {
+		choices = append(choices, choice(localized{en.FactionName(e), es.FactionName(e)}, factionValues[e]))
+	}
+	return textOption(
+		localized{"faction", "facción"},
+		localized{"Filter by faction: bugs, cyborgs, illuminate", "Filtrar por facción"},
+		required, choices...)
+}
+
+// statusCommands are always registered.
+func statusCommands() []*discordgo.ApplicationCommand {
+	return []*discordgo.ApplicationCommand{
+		command(
+			localized{"status", "estado"},
+			localized{"Show current war progress and active events per faction", "Muestra el progreso de la guerra y los eventos activos por facción"},
+			factionOption(false)),
+		command(
+			localized{"statistics", "estadísticas"},
+			localized{"Show cumulative war statistics (all factions summed)", "Muestra las estadísticas acumuladas de la guerra (todas las facciones)"}),
+		regionCommand(),
+	}
+}
+
+// regionCommand shows one region of a faction. The region option is
+// autocompleted from the faction's region names.
+func regionCommand() *discordgo.ApplicationCommand {
+	region := textOption(
+		localized{"region", "región"},
+		localized{"Region name or number", "Nombre o número de la región"},
+		true)
+	region.Autocomplete = true
+	return command(
+		localized{"region", "región"},
+		localized{"Show the state of one of a faction's regions", "Muestra el estado de una región de una facción"},
+		factionOption(true), region)
+}
+
+// optionValue returns the string value of the named option, or "".
+func optionValue(options []*discordgo.ApplicationCommandInteractionDataOption, name string) string {
+	for _, opt := range options {
+		if opt.Name == name {
+			return opt.StringValue()
+		}
+	}
+	return ""
+}
+
+// regionChoices returns the faction's regions whose number, name or capital
+// contains query, as autocomplete choices valued by region number.
+func regionChoices(faction, query string) []*discordgo.ApplicationCommandOptionChoice {
+	enemy, ok := domain.ParseEnemy(faction)
+	if !ok {
+		return nil
+	}
+	query = strings.ToLower(strings.TrimSpace(query))
+	var choices []*discordgo.ApplicationCommandOptionChoice
+	for n := 1; n <= domain.HomeWorldRegion; n++ {
+		r := domain.GetRegion(enemy, n)
+		name := fmt.Sprintf("%d · %s (%s)", n, r.Name, r.Capital)
+		if query != "" && !strings.Contains(strings.ToLower(name), query) {
+			continue
+		}
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{Name: name, Value: fmt.Sprint(n)})
+	}
+	return choices
+}
+
+func (n *DiscordNotifier) handleRegionAutocomplete(s *discordgo.Session, i *discordgo.InteractionCreate, data discordgo.ApplicationCommandInteractionData) {
+	var query string
+	for _, opt := range data.Options {
+		if opt.Focused {
+			query = opt.StringValue()
+		}
+	}
+	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionApplicationCommandAutocompleteResult,
+		Data: &discordgo.InteractionResponseData{
+			Choices: regionChoices(optionValue(data.Options, "faction"), query),
+		},
+	})
+}
+
+func (n *DiscordNotifier) handleRegionCommand(s *discordgo.Session, i *discordgo.InteractionCreate, data discordgo.ApplicationCommandInteractionData) {
+	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: n.regionResponse(optionValue(data.Options, "faction"), optionValue(data.Options, "region")),
+	})
+}
+
+// regionResponse is the /region reply: the region's embed, or why it could
+// not be shown. Failures other than an unknown region are logged.
+func (n *DiscordNotifier) regionResponse(faction, region string) *discordgo.InteractionResponseData {
+	m, err := n.fetchRegion(faction, region)
+	if errors.Is(err, errUnknownRegion) {
+		return &discordgo.InteractionResponseData{Content: fmt.Sprintf(n.catalog.RegionUnknown, region)}
+	}
+	if err != nil {
+		n.logger.Error("discord: /region failed", "error", err)
+		return &discordgo.InteractionResponseData{Content: n.catalog.RegionFailed}
+	}
+	return &discordgo.InteractionResponseData{Embeds: []*discordgo.MessageEmbed{embed(m)}}
+}
+
+// errUnknownRegion is returned for a faction or region that does not exist.
+var errUnknownRegion = errors.New("unknown region")
+
+func (n *DiscordNotifier) fetchRegion(faction, region string) (domain.Message, error) {
+	enemy, ok := domain.ParseEnemy(faction)
+	if !ok {
+		return domain.Message{}, fmt.Errorf("%w: unknown faction %q", errUnknownRegion, faction)
+	}
+	number, ok := domain.FindRegion(enemy, region)
+	if !ok {
+		return domain.Message{}, fmt.Errorf("%w %q", errUnknownRegion, region)
+	}
+	if n.provider == nil {
+		return domain.Message{}, fmt.Errorf("no status provider registered")
+	}
+	c, err := n.provider.LatestCampaign()
+	if err != nil {
+		return domain.Message{}, err
+	}
+	return n.catalog.RegionMessage(c, enemy, number), nil
+}
@@ -0,0 +1,122 @@
+package discord
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/ametis70/hellbot/internal/domain"
+	"github.com/ametis70/hellbot/internal/testutil"
+)
+
+// commandName is Discord's rule for command and option names.
+var commandName = regexp.MustCompile(`^[-_\p{Ll}\p{N}]{1,32}$`)
+
+// campaignProvider returns a fixed campaign.
+type campaignProvider struct {
+	c *domain.CampaignStatus
+}
+
+func (p campaignProvider) LatestCampaign() (*domain.CampaignStatus, error) {
+	return p.c, nil
+}
+
+func TestCommands_Localized(t *testing.T) {
+	cmds := append(statusCommands(), subscriptionCommands()...)
+	checkName := func(name string) {
+		if !commandName.MatchString(name) {
+			t.Errorf("invalid command or option name %q", name)
+		}
+	}
+	for _, cmd := range cmds {
+		checkName(cmd.Name)
+		if cmd.NameLocalizations == nil || cmd.DescriptionLocalizations == nil {
+			t.Fatalf("command %s is not localized", cmd.Name)
+		}
+		for _, locale := range []discordgo.Locale{discordgo.SpanishES, discordgo.SpanishLATAM} {
+			checkName((*cmd.NameLocalizations)[locale])
+			if (*cmd.DescriptionLocalizations)[locale] == "" {
+				t.Errorf("command %s has no %s description", cmd.Name, locale)
+			}
+		}
+		for _, opt := range cmd.Options {
+			checkName(opt.Name)
+			checkName(opt.NameLocalizations[discordgo.SpanishES])
+			for _, c := range opt.Choices {
+				if c.NameLocalizations[discordgo.SpanishES] == "" {
+					t.Errorf("choice %s of %s/%s is not localized", c.Name, cmd.Name, opt.Name)
+				}
+			}
+		}
+	}
+
+	status := statusCommands()[0]
+	if (*status.NameLocalizations)[discordgo.SpanishES] != "estado" {
+		t.Errorf("unexpected Spanish name %q", (*status.NameLocalizations)[discordgo.SpanishES])
+	}
+	if c := status.Options[0].Choices[0]; c.Value != "bugs" || c.NameLocalizations[discordgo.SpanishES] != "Insectos" {
+		t.Errorf("unexpected faction choice %+v", c)
+	}
+}
+
+func TestRegionChoices(t *testing.T) {
+	all := regionChoices("bugs", "")
+	if len(all) != domain.HomeWorldRegion {
+		t.Fatalf("expected %d regions, got %d", domain.HomeWorldRegion, len(all))
+	}
+	if all[2].Name != "3 · Ross System (Tiberia)" || all[2].Value != "3" {
+		t.Errorf("unexpected choice %+v", all[2])
+	}
+
+	got := regionChoices("bugs", "TIBER")
+	if len(got) != 1 || got[0].Value != "3" {
+		t.Errorf("expected only Ross System, got %+v", got)
+	}
+	if got := regionChoices("", "ross"); got != nil {
+		t.Errorf("expected no choices without a faction, got %+v", got)
+	}
+}
+
+func TestFetchRegion(t *testing.T) {
+	n := &DiscordNotifier{catalog: domain.CatalogFor(domain.LocaleEnglish)}
+	n.provider = campaignProvider{&domain.CampaignStatus{FactionsStatus: []domain.FactionStatus{
+		{Enemy: domain.EnemyBug, Season: 160, Points: 450, PointsMax: 1000, Status: domain.FactionStatusActive},
+	}}}
+
+	for _, region := range []string{"3", "Ross System"} {
+		m, err := n.fetchRegion("bugs", region)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.HasPrefix(m.Title, "Ross System") {
+			t.Errorf("unexpected title %q", m.Title)
+		}
+	}
+	if _, err := n.fetchRegion("bugs", "Atlantis"); err == nil {
+		t.Error("expected error for an unknown region, got nil")
+	}
+	if _, err := n.fetchRegion("robots", "3"); err == nil {
+		t.Error("expected error for an unknown faction, got nil")
+	}
+}
+
+func TestCommandResponses_Spanish(t *testing.T) {
+	n := newTestNotifier(t, func(o *Options) { o.Locale = domain.LocaleSpanish })
+	es := domain.CatalogFor(domain.LocaleSpanish)
+	n.provider = &testutil.ErrorStore{}
+
+	if got := n.statusResponse(nil).Content; got != es.StatusFailed {
+		t.Errorf("expected the localized status failure without the error, got %q", got)
+	}
+	if got := n.statisticsResponse().Content; got != es.StatisticsFailed {
+		t.Errorf("expected the localized statistics failure without the error, got %q", got)
+	}
+	if got := n.regionResponse("bugs", "3").Content; got != es.RegionFailed {
+		t.Errorf("expected the localized region failure without the error, got %q", got)
+	}
+	if got := n.regionResponse("bugs", "Atlantis").Content; got != `⚠️ Región desconocida: "Atlantis".` {
+		t.Errorf("expected the unknown region to be named, got %q", got)
+	}
+}
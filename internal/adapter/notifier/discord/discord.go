@@ -1,8 +1,13 @@
 package discord
 
 import (
+	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 
@@ -12,87 +17,214 @@ import (
 
 // Options holds configuration for a Discord notifier instance.
 type Options struct {
-	Token     string
+	// ID is the notifier's ID from the config. It keeps the state this
+	// notifier stores, such as subscriptions, apart from other notifiers.
+	ID    string
+	Token string
+	// WebhookURL posts through a Discord incoming webhook instead of a bot.
+	// No token or gateway connection is needed, but slash commands, routes,
+	// the war board and subscriptions are unavailable.
+	WebhookURL string
+	// WebhookProfile sets the username and avatar in webhook mode.
+	WebhookProfile WebhookProfile
+	// FactionProfiles override WebhookProfile for defend and attack events.
+	FactionProfiles map[domain.Enemy]WebhookProfile
+	// ChannelID receives every event. Optional when Routes is set.
 	ChannelID string
+	// Routes send matching events to further channels over the same session.
+	Routes []Route
 	// GuildID is optional. When set, slash commands are registered as guild
 	// commands (instant). When empty, they are registered globally (up to 1h delay).
-	GuildID   string
+	GuildID string
+	Locale  domain.Locale
+	// Style selects markdown templates (default) or structured messages,
+	// which are sent as embeds.
+	Style domain.MessageStyle
+	// Embeds lists the event kinds sent as embeds with the template style.
+	// Other kinds fall back to the templates.
+	Embeds    []domain.EventKind
 	Templates *domain.Templates
+	// BoardChannelID is optional. When set, a pinned war status message in
+	// that channel is edited after every poll.
+	BoardChannelID string
+	// Refs stores the board message ID across restarts. Optional.
+	Refs port.MessageRefStore
+	// Mentions lists who to ping for which events. Nothing else in a
+	// message can ping, even if a template contains a mention.
+	Mentions []Mention
+	// Subscriptions enables /subscribe and /unsubscribe, and DMs matching
+	// events to subscribers. Optional.
+	Subscriptions port.SubscriptionStore
+	// ScheduledEvents creates a guild scheduled event for every defend and
+	// attack, completed when it ends. Requires GuildID and Refs.
+	ScheduledEvents bool
+	// Threads opens a thread on every defend and attack start message for
+	// progress updates and the outcome. Requires Refs.
+	Threads bool
+	// Presence shows war progress as the bot's status. Optional.
+	Presence *Presence
+	// AdminRoles may run /admin in addition to members with Manage Server.
+	AdminRoles []string
+
+	// session is used instead of opening one with Token. Tests set it to a
+	// session that is never opened.
+	session *discordgo.Session
 }
 
-// DiscordNotifier implements port.Notifier by sending messages to a Discord channel.
+// Route sends events matching its factions and kinds to a channel, optionally
+// with its own templates merged over the notifier's. Empty filters match any
+// event.
+type Route struct {
+	ChannelID string
+	Factions  []domain.Enemy
+	Kinds     []domain.EventKind
+	Templates *domain.Templates
+}
+
+// route is a Route with its templates resolved.
+type route struct {
+	Route
+	templates domain.Templates
+	titles    domain.Templates
+}
+
+// matches reports whether msg should be sent to r.
+func (r route) matches(msg domain.EventMessage) bool {
+	if len(r.Kinds) > 0 && !slices.Contains(r.Kinds, msg.Kind) {
+		return false
+	}
+	return matchesFaction(r.Factions, msg)
+}
+
+// DiscordNotifier implements port.Notifier by sending messages to one or more
+// Discord channels over a single session.
 type DiscordNotifier struct {
 	opts             Options
 	session          *discordgo.Session
 	logger           *slog.Logger
-	templates        domain.Templates
+	routes           []route
+	dm               route
+	catalog          *domain.Catalog
 	provider         port.StatusProvider
+	board            *board
+	webhook          *webhookClient
+	progress         map[string]int
+	presence         *presence
+	pollTrigger      port.PollTrigger
+	started          time.Time
 	registeredCmdIDs []string
+
+	// mu guards mutedUntil, set by /admin from the gateway goroutine.
+	mu         sync.Mutex
+	mutedUntil time.Time
 }
 
-// New creates a new DiscordNotifier, opens a Discord session, and validates connectivity.
+// New creates a new DiscordNotifier, opens a Discord session, and validates
+// connectivity. With opts.WebhookURL set it posts through the webhook instead
+// and opens no session.
 func New(opts Options, logger *slog.Logger) (*DiscordNotifier, error) {
 	if logger == nil {
 		panic("discord notifier: logger is required")
 	}
+	n := &DiscordNotifier{
+		opts:     opts,
+		logger:   logger,
+		catalog:  domain.CatalogFor(opts.Locale),
+		progress: map[string]int{},
+		started:  time.Now(),
+	}
+	if opts.WebhookURL != "" {
+		return newWebhook(opts, n)
+	}
+
 	if opts.Token == "" {
 		return nil, fmt.Errorf("discord notifier: token is required")
 	}
-	if opts.ChannelID == "" {
-		return nil, fmt.Errorf("discord notifier: channel_id is required")
+	if opts.ChannelID == "" && len(opts.Routes) == 0 {
+		return nil, fmt.Errorf("discord notifier: channel_id or routes is required")
 	}
-
-	session, err := discordgo.New("Bot " + opts.Token)
-	if err != nil {
-		return nil, fmt.Errorf("discord notifier: creating session: %w", err)
+	for _, r := range opts.Routes {
+		if r.ChannelID == "" {
+			return nil, fmt.Errorf("discord notifier: route channel_id is required")
+		}
+	}
+	if opts.ScheduledEvents && (opts.GuildID == "" || opts.Refs == nil) {
+		return nil, fmt.Errorf("discord notifier: scheduled events need a guild_id and a store")
+	}
+	if opts.Threads && opts.Refs == nil {
+		return nil, fmt.Errorf("discord notifier: threads need a store")
 	}
 
-	session.Identify.Intents = discordgo.IntentsGuildMessages
+	session := opts.session
+	if session == nil {
+		var err error
+		session, err = discordgo.New("Bot " + opts.Token)
+		if err != nil {
+			return nil, fmt.Errorf("discord notifier: creating session: %w", err)
+		}
 
-	if err := session.Open(); err != nil {
-		return nil, fmt.Errorf("discord notifier: opening session: %w", err)
+		session.Identify.Intents = discordgo.IntentsGuildMessages
+
+		if err := session.Open(); err != nil {
+			return nil, fmt.Errorf("discord notifier: opening session: %w", err)
+		}
+	}
+
+	n.session = session
+	if opts.ChannelID != "" {
+		n.routes = append(n.routes, n.resolveRoute(Route{ChannelID: opts.ChannelID}))
+	}
+	for _, r := range opts.Routes {
+		n.routes = append(n.routes, n.resolveRoute(r))
+	}
+	n.dm = n.resolveRoute(Route{})
+	if opts.BoardChannelID != "" {
+		n.board = &board{api: session, channelID: opts.BoardChannelID, refs: opts.Refs}
 	}
+	if opts.Presence != nil {
+		n.presence = newPresence(session, opts.Locale, *opts.Presence)
+	}
+	if err := n.loadMute(); err != nil {
+		logger.Warn("discord: failed to load mute", "error", err)
+	}
+	return n, nil
+}
 
-	templates := DefaultTemplates()
-	if opts.Templates != nil {
-		templates = domain.MergeTemplates(templates, *opts.Templates)
+// resolveRoute merges the locale's templates, the notifier's overrides and
+// the route's own overrides, in that order.
+func (n *DiscordNotifier) resolveRoute(r Route) route {
+	templates := LocalizedTemplates(n.opts.Locale)
+	if n.opts.Style == domain.MessageStyleStructured {
+		templates = n.catalog.Titles
+	}
+	for _, t := range []*domain.Templates{n.opts.Templates, r.Templates} {
+		if t != nil {
+			templates = domain.MergeTemplates(templates, *t)
+		}
 	}
 
-	return &DiscordNotifier{
-		opts:      opts,
-		session:   session,
-		logger:    logger,
-		templates: templates,
-	}, nil
+	// Embed titles are plain text. With the template style the user's
+	// templates contain markdown, so embeds keep the locale's titles.
+	titles := n.catalog.Titles
+	if n.opts.Style == domain.MessageStyleStructured {
+		titles = templates
+	}
+	return route{Route: r, templates: templates, titles: titles}
 }
 
-// RegisterCommands implements port.Commander. It registers /status and /statistics
-// slash commands and wires the interaction handler.
+// RegisterCommands implements port.Commander. It registers the /status,
+// /statistics, /region and /admin slash commands, localized for Spanish
+// clients, and wires the interaction handler. Webhook mode has no gateway to receive
+// interactions, so it registers nothing.
 func (n *DiscordNotifier) RegisterCommands(provider port.StatusProvider) {
 	n.provider = provider
-
-	factionChoice := &discordgo.ApplicationCommandOption{
-		Type:        discordgo.ApplicationCommandOptionString,
-		Name:        "faction",
-		Description: "Filter by faction: bugs, cyborgs, illuminate",
-		Required:    false,
-		Choices: []*discordgo.ApplicationCommandOptionChoice{
-			{Name: "Bugs", Value: "bugs"},
-			{Name: "Cyborgs", Value: "cyborgs"},
-			{Name: "Illuminate", Value: "illuminate"},
-		},
+	if n.session == nil {
+		return
 	}
 
-	cmds := []*discordgo.ApplicationCommand{
-		{
-			Name:        "status",
-			Description: "Show current war progress and active events per faction",
-			Options:     []*discordgo.ApplicationCommandOption{factionChoice},
-		},
-		{
-			Name:        "statistics",
-			Description: "Show cumulative war statistics (all factions summed)",
-		},
+	cmds := append(statusCommands(), adminCommand())
+	if n.opts.Subscriptions != nil {
+		cmds = append(cmds, subscriptionCommands()...)
 	}
 
 	appID := n.session.State.User.ID
@@ -110,6 +242,12 @@ func (n *DiscordNotifier) RegisterCommands(provider port.StatusProvider) {
 }
 
 func (n *DiscordNotifier) handleInteraction(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if i.Type == discordgo.InteractionApplicationCommandAutocomplete {
+		if data := i.ApplicationCommandData(); data.Name == "region" {
+			n.handleRegionAutocomplete(s, i, data)
+		}
+		return
+	}
 	if i.Type != discordgo.InteractionApplicationCommand {
 		return
 	}
@@ -121,6 +259,14 @@ func (n *DiscordNotifier) handleInteraction(s *discordgo.Session, i *discordgo.I
 		n.handleStatusCommand(s, i, data)
 	case "statistics":
 		n.handleStatisticsCommand(s, i)
+	case "region":
+		n.handleRegionCommand(s, i, data)
+	case "admin":
+		n.handleAdminCommand(s, i, data)
+	case "subscribe":
+		n.handleSubscribeCommand(s, i, data)
+	case "unsubscribe":
+		n.handleUnsubscribeCommand(s, i)
 	}
 }
 
@@ -135,57 +281,68 @@ func (n *DiscordNotifier) handleStatusCommand(s *discordgo.Session, i *discordgo
 		}
 	}
 
-	text, err := n.fetchAndFormatStatus(filter)
-	if err != nil {
-		text = "⚠️ Could not retrieve war status: " + err.Error()
-	}
-
 	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
-		Data: &discordgo.InteractionResponseData{
-			Content: "```\n" + text + "\n```",
-		},
+		Data: n.statusResponse(filter),
 	})
 }
 
-func (n *DiscordNotifier) handleStatisticsCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	text, err := n.fetchAndFormatStatistics()
+// statusResponse is the /status reply: the status embed, or a failure
+// message after logging the error.
+func (n *DiscordNotifier) statusResponse(filter *domain.Enemy) *discordgo.InteractionResponseData {
+	m, err := n.fetchStatus(filter)
 	if err != nil {
-		text = "⚠️ Could not retrieve statistics: " + err.Error()
+		n.logger.Error("discord: /status failed", "error", err)
+		return &discordgo.InteractionResponseData{Content: n.catalog.StatusFailed}
 	}
+	return &discordgo.InteractionResponseData{Embeds: []*discordgo.MessageEmbed{embed(m)}}
+}
 
+func (n *DiscordNotifier) handleStatisticsCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
-		Data: &discordgo.InteractionResponseData{
-			Content: "```\n" + text + "\n```",
-		},
+		Data: n.statisticsResponse(),
 	})
 }
 
-func (n *DiscordNotifier) fetchAndFormatStatus(filter *domain.Enemy) (string, error) {
+// statisticsResponse is the /statistics reply: the statistics embed, or a
+// failure message after logging the error.
+func (n *DiscordNotifier) statisticsResponse() *discordgo.InteractionResponseData {
+	m, err := n.fetchStatistics()
+	if err != nil {
+		n.logger.Error("discord: /statistics failed", "error", err)
+		return &discordgo.InteractionResponseData{Content: n.catalog.StatisticsFailed}
+	}
+	return &discordgo.InteractionResponseData{Embeds: []*discordgo.MessageEmbed{embed(m)}}
+}
+
+func (n *DiscordNotifier) fetchStatus(filter *domain.Enemy) (domain.Message, error) {
 	if n.provider == nil {
-		return "", fmt.Errorf("no status provider registered")
+		return domain.Message{}, fmt.Errorf("no status provider registered")
 	}
 	c, err := n.provider.LatestCampaign()
 	if err != nil {
-		return "", err
+		return domain.Message{}, err
 	}
-	return domain.FormatStatus(c, filter), nil
+	return n.catalog.StatusMessage(c, filter), nil
 }
 
-func (n *DiscordNotifier) fetchAndFormatStatistics() (string, error) {
+func (n *DiscordNotifier) fetchStatistics() (domain.Message, error) {
 	if n.provider == nil {
-		return "", fmt.Errorf("no status provider registered")
+		return domain.Message{}, fmt.Errorf("no status provider registered")
 	}
 	c, err := n.provider.LatestCampaign()
 	if err != nil {
-		return "", err
+		return domain.Message{}, err
 	}
-	return domain.FormatStatistics(c), nil
+	return n.catalog.StatisticsMessage(c), nil
 }
 
 // Close deregisters slash commands and closes the underlying Discord session.
 func (n *DiscordNotifier) Close() error {
+	if n.session == nil {
+		return nil
+	}
 	appID := n.session.State.User.ID
 	for _, id := range n.registeredCmdIDs {
 		if err := n.session.ApplicationCommandDelete(appID, n.opts.GuildID, id); err != nil {
@@ -195,19 +352,81 @@ func (n *DiscordNotifier) Close() error {
 	return n.session.Close()
 }
 
-// Notify sends a formatted event message to the configured Discord channel.
+// sessionAPI is the part of *discordgo.Session used to deliver events.
+type sessionAPI interface {
+	channelAPI
+	dmAPI
+	scheduleAPI
+}
+
+// Notify sends a formatted event message to every channel whose route
+// matches it, and as a DM to matching subscribers. Event kinds configured for
+// embeds are sent as embeds; the rest use templates. With threads enabled,
+// outcomes go to the thread of their start message.
 func (n *DiscordNotifier) Notify(msg domain.EventMessage) error {
-	text, err := domain.RenderEvent(n.templates, msg, TimeFormatter(nil))
-	if err != nil {
-		return fmt.Errorf("discord notifier: rendering message: %w", err)
+	if n.webhook != nil {
+		return n.executeWebhook(msg)
 	}
+	return n.notify(n.session, msg, time.Now())
+}
 
-	_, err = n.session.ChannelMessageSend(n.opts.ChannelID, text)
-	if err != nil {
-		return fmt.Errorf("discord notifier: sending message: %w", err)
+// notify delivers msg through api. While muted nothing is posted to channels
+// or DMs, but event threads are closed and scheduled events kept in sync.
+func (n *DiscordNotifier) notify(api sessionAPI, msg domain.EventMessage, now time.Time) error {
+	muted := n.muted(now)
+	if muted {
+		n.logger.Info("discord: notification muted", "kind", msg.Kind, "transition", msg.Transition)
+	}
+
+	var errs []error
+	for _, r := range n.routes {
+		if !r.matches(msg) {
+			continue
+		}
+		send := n.send
+		if muted {
+			send = n.closeMutedThread
+		}
+		if err := send(api, r, msg); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if !muted {
+		if err := n.notifySubscribers(api, msg); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := n.syncScheduledEvent(api, msg); err != nil {
+		errs = append(errs, err)
 	}
+	return errors.Join(errs...)
+}
+
+// message builds the Discord message for an event on route r, prefixed with
+// the mentions whose rules match it.
+func (n *DiscordNotifier) message(r route, msg domain.EventMessage, rules []Mention) (*discordgo.MessageSend, error) {
+	mentions, allowed := mentionsFor(rules, msg)
+	m := &discordgo.MessageSend{AllowedMentions: allowed}
 
-	return nil
+	if n.embedFor(msg.Kind) {
+		e, err := n.eventEmbed(r, msg)
+		if err != nil {
+			return nil, err
+		}
+		m.Content = mentions
+		m.Embeds = []*discordgo.MessageEmbed{e}
+		return m, nil
+	}
+
+	text, err := n.catalog.RenderEvent(r.templates, msg, TimeFormatter(nil))
+	if err != nil {
+		return nil, err
+	}
+	m.Content = text
+	if mentions != "" {
+		m.Content = mentions + "\n" + text
+	}
+	return m, nil
 }
 
 // FormatMessage renders an event message using the default Discord templates.
@@ -224,3 +443,89 @@ func formatMessage(msg domain.EventMessage) (string, error) {
 func discordTimestamp(unix int64) string {
 	return fmt.Sprintf("<t:%d:f>", unix)
 }
+
+// embedFor reports whether events of kind are sent as embeds.
+func (n *DiscordNotifier) embedFor(kind domain.EventKind) bool {
+	return n.opts.Style == domain.MessageStyleStructured || slices.Contains(n.opts.Embeds, kind)
+}
+
+// eventEmbed builds the embed for an event on route r. Defend and attack
+// embeds use the faction's colour instead of the severity colour.
+func (n *DiscordNotifier) eventEmbed(r route, msg domain.EventMessage) (*discordgo.MessageEmbed, error) {
+	m, err := n.catalog.BuildMessage(r.titles, msg, TimeFormatter(nil))
+	if err != nil {
+		return nil, err
+	}
+	e := embed(m)
+	if enemy, ok := msg.Enemy(); ok {
+		if c, ok := factionColors[enemy]; ok {
+			e.Color = c
+		}
+	}
+	return e, nil
+}
+
+// factionColors maps factions to embed colours.
+var factionColors = map[domain.Enemy]int{
+	domain.EnemyBug:        0xE8A33D,
+	domain.EnemyCyborg:     0xC0392B,
+	domain.EnemyIlluminate: 0x3C8DDE,
+}
+
+// severityColors maps message severities to embed colours.
+var severityColors = map[domain.Severity]int{
+	domain.SeverityInfo:     0x3498DB,
+	domain.SeveritySuccess:  0x2ECC71,
+	domain.SeverityWarning:  0xE67E22,
+	domain.SeverityCritical: 0xE74C3C,
+}
+
+// embed renders a structured message as a Discord embed. Timestamps use
+// Discord's native <t:UNIX> format so they show in the viewer's timezone.
+func embed(m domain.Message) *discordgo.MessageEmbed {
+	mk := markdown()
+	e := &discordgo.MessageEmbed{
+		Title:       m.Title,
+		Description: domain.FormatLines(m.Body, mk),
+		Color:       severityColors[m.Severity],
+	}
+	for _, f := range m.Fields {
+		e.Fields = append(e.Fields, &discordgo.MessageEmbedField{
+			Name:   f.Name,
+			Value:  domain.FormatLines(f.Value, mk),
+			Inline: f.Inline,
+		})
+	}
+	if m.Footer != "" {
+		e.Footer = &discordgo.MessageEmbedFooter{Text: m.Footer}
+	}
+	if !m.Timestamp.IsZero() {
+		e.Timestamp = m.Timestamp.Format(time.RFC3339)
+	}
+	return e
+}
+
+// markdown renders structured message spans as Discord markdown.
+func markdown() domain.Markup {
+	return domain.Markup{
+		Text:   escapeMarkdown,
+		Bold:   func(s string) string { return "**" + escapeMarkdown(s) + "**" },
+		Italic: func(s string) string { return "*" + escapeMarkdown(s) + "*" },
+		Code:   func(s string) string { return "`" + strings.ReplaceAll(s, "`", "'") + "`" },
+		Time: func(s domain.Span) string {
+			if s.Relative {
+				return fmt.Sprintf("<t:%d:R>", s.Time.Unix())
+			}
+			return discordTimestamp(s.Time.Unix())
+		},
+	}
+}
+
+// markdownEscaper escapes characters Discord treats as markdown.
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "*", `\*`, "_", `\_`, "~", `\~`, "`", "\\`", "|", `\|`,
+)
+
+func escapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
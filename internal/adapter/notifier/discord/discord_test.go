@@ -5,10 +5,32 @@ import (
 	"testing"
 	"time"
 
+	"github.com/bwmarrin/discordgo"
+
 	"github.com/ametis70/hellbot/internal/domain"
 	"github.com/ametis70/hellbot/internal/testutil"
 )
 
+// newTestNotifier creates a bot notifier through New, posting to the
+// "events" channel over a session that is never opened. opts adjust the
+// options first.
+func newTestNotifier(t *testing.T, opts ...func(*Options)) *DiscordNotifier {
+	t.Helper()
+	session, err := discordgo.New("Bot tok")
+	if err != nil {
+		t.Fatalf("creating session: %v", err)
+	}
+	o := Options{ID: "main", Token: "tok", ChannelID: "events", session: session}
+	for _, f := range opts {
+		f(&o)
+	}
+	n, err := New(o, testutil.DiscardLogger())
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return n
+}
+
 func defendMsg(transition domain.EventTransition) domain.EventMessage {
 	return domain.EventMessage{
 		Kind:        domain.EventKindDefend,
@@ -261,3 +283,154 @@ func TestDiscordTimestamp(t *testing.T) {
 		t.Errorf("expected %s, got %s", expected, result)
 	}
 }
+
+func TestDefaultTemplates_Valid(t *testing.T) {
+	if problems := domain.ValidateTemplates(DefaultTemplates()); len(problems) != 0 {
+		t.Errorf("default templates have problems: %v", problems)
+	}
+}
+
+func TestLocalizedTemplates_Spanish(t *testing.T) {
+	es := LocalizedTemplates(domain.LocaleSpanish)
+	if problems := domain.ValidateTemplates(es); len(problems) != 0 {
+		t.Errorf("spanish templates have problems: %v", problems)
+	}
+	if es == DefaultTemplates() {
+		t.Error("expected spanish templates to differ from english")
+	}
+	if LocalizedTemplates("xx") != DefaultTemplates() {
+		t.Error("expected unknown locale to fall back to english")
+	}
+}
+
+func TestEmbed(t *testing.T) {
+	ts := time.Unix(1700000000, 0)
+	e := embed(domain.Message{
+		Severity: domain.SeverityCritical,
+		Title:    "Title",
+		Body:     []domain.Line{{domain.Text("a_b "), domain.Bold("bold")}},
+		Fields: []domain.Field{
+			{Name: "Ends", Value: []domain.Line{{domain.Timestamp(ts), domain.Text(" "), {Text: "1h", Time: ts, Relative: true}}}, Inline: true},
+			{Name: "Progress", Value: []domain.Line{{domain.Code("[██░] 42%")}}},
+		},
+		Footer:    "War 1",
+		Timestamp: ts,
+	})
+	if e.Title != "Title" || e.Color != severityColors[domain.SeverityCritical] {
+		t.Errorf("unexpected title or colour: %+v", e)
+	}
+	if e.Description != `a\_b **bold**` {
+		t.Errorf("unexpected description %q", e.Description)
+	}
+	if len(e.Fields) != 2 || e.Fields[0].Value != "<t:1700000000:f> <t:1700000000:R>" || !e.Fields[0].Inline {
+		t.Errorf("unexpected time field %+v", e.Fields[0])
+	}
+	if e.Fields[1].Value != "`[██░] 42%`" {
+		t.Errorf("unexpected code field %q", e.Fields[1].Value)
+	}
+	if e.Footer == nil || e.Footer.Text != "War 1" || e.Timestamp != ts.Format(time.RFC3339) {
+		t.Errorf("unexpected footer or timestamp: %+v %q", e.Footer, e.Timestamp)
+	}
+}
+
+func TestEmbed_StatusBoard(t *testing.T) {
+	m := domain.CatalogFor(domain.LocaleEnglish).StatusMessage(testutil.CampaignWithActiveDefend(), nil)
+	e := embed(m)
+	if e.Title != "War 159 — Status" || len(e.Fields) != len(m.Fields) {
+		t.Fatalf("unexpected embed %+v", e)
+	}
+	last := e.Fields[len(e.Fields)-1]
+	if last.Name != "Active events" || !strings.Contains(last.Value, ":R>") {
+		t.Errorf("expected native relative timestamp in active events, got %q", last.Value)
+	}
+}
+
+func TestEmbedFor(t *testing.T) {
+	n := &DiscordNotifier{opts: Options{Embeds: []domain.EventKind{domain.EventKindDefend}}}
+	if !n.embedFor(domain.EventKindDefend) || n.embedFor(domain.EventKindWar) {
+		t.Error("expected only defend events to use embeds")
+	}
+	n.opts = Options{Style: domain.MessageStyleStructured}
+	if !n.embedFor(domain.EventKindWar) {
+		t.Error("expected structured style to use embeds for every kind")
+	}
+}
+
+func TestEventEmbed_Defend(t *testing.T) {
+	cat := domain.CatalogFor(domain.LocaleEnglish)
+	n := &DiscordNotifier{catalog: cat}
+	e := testutil.DefendEventActive()
+	e.Enemy = domain.EnemyBug
+	e.Region = 3
+
+	embed, err := n.eventEmbed(route{titles: cat.Titles}, domain.EventMessage{Kind: domain.EventKindDefend, Transition: domain.EventTransitionStarted, DefendEvent: e})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if embed.Color != factionColors[domain.EnemyBug] {
+		t.Errorf("expected bug colour, got %#x", embed.Color)
+	}
+	names := make([]string, 0, len(embed.Fields))
+	for _, f := range embed.Fields {
+		names = append(names, f.Name)
+	}
+	if got := strings.Join(names, ","); got != "Region,Capital,Ends,Progress" {
+		t.Errorf("unexpected fields %s", got)
+	}
+	if !strings.HasPrefix(embed.Fields[2].Value, "<t:1784674741:f>") {
+		t.Errorf("expected native end timestamp, got %q", embed.Fields[2].Value)
+	}
+	if embed.Footer == nil || embed.Footer.Text != "War 159" {
+		t.Errorf("expected season footer, got %+v", embed.Footer)
+	}
+}
+
+func TestEventEmbed_WarUsesSeverityColour(t *testing.T) {
+	cat := domain.CatalogFor(domain.LocaleEnglish)
+	n := &DiscordNotifier{catalog: cat}
+	embed, err := n.eventEmbed(route{titles: cat.Titles}, domain.EventMessage{Kind: domain.EventKindWar, Transition: domain.EventTransitionSucceeded, WarEvent: &domain.WarEvent{Season: 159}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if embed.Color != severityColors[domain.SeveritySuccess] {
+		t.Errorf("expected success colour, got %#x", embed.Color)
+	}
+}
+
+func TestRoute_Matches(t *testing.T) {
+	r := route{Route: Route{Factions: []domain.Enemy{domain.EnemyBug}, Kinds: []domain.EventKind{domain.EventKindAttack}}}
+	bugs := testutil.AttackEventActive()
+	bugs.Enemy = domain.EnemyBug
+	cyborgs := bugs
+	cyborgs.Enemy = domain.EnemyCyborg
+
+	if !r.matches(domain.EventMessage{Kind: domain.EventKindAttack, AttackEvent: &bugs}) {
+		t.Error("expected bug attack to match")
+	}
+	if r.matches(domain.EventMessage{Kind: domain.EventKindAttack, AttackEvent: &cyborgs}) {
+		t.Error("expected cyborg attack not to match")
+	}
+	if r.matches(domain.EventMessage{Kind: domain.EventKindWar, WarEvent: &domain.WarEvent{}}) {
+		t.Error("expected war event not to match")
+	}
+	if !(route{}).matches(domain.EventMessage{Kind: domain.EventKindWar, WarEvent: &domain.WarEvent{}}) {
+		t.Error("expected a route without filters to match everything")
+	}
+}
+
+func TestResolveRoute_MergesTemplates(t *testing.T) {
+	n := &DiscordNotifier{
+		opts:    Options{Templates: &domain.Templates{WarWon: "notifier", WarLost: "notifier"}},
+		catalog: domain.CatalogFor(domain.LocaleEnglish),
+	}
+	r := n.resolveRoute(Route{ChannelID: "c", Templates: &domain.Templates{WarWon: "route"}})
+	if r.templates.WarWon != "route" || r.templates.WarLost != "notifier" {
+		t.Errorf("unexpected templates: won=%q lost=%q", r.templates.WarWon, r.templates.WarLost)
+	}
+	if r.templates.AttackHomeworldStarted != DefaultTemplates().AttackHomeworldStarted {
+		t.Error("expected unset templates to keep the defaults")
+	}
+	if r.titles.WarWon != n.catalog.Titles.WarWon {
+		t.Error("expected template style embeds to keep the catalog titles")
+	}
+}
@@ -0,0 +1,82 @@
+package discord
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/ametis70/hellbot/internal/domain"
+)
+
+// Mention pings roles, users or @everyone for events matching all of its
+// criteria. An empty criterion matches any event.
+type Mention struct {
+	// Factions matches defend and attack events against these factions.
+	Factions []domain.Enemy
+	Kinds    []domain.EventKind
+	// SuperEarth matches only defenses of Super Earth.
+	SuperEarth bool
+
+	Roles    []string
+	Users    []string
+	Everyone bool
+}
+
+// matches reports whether msg satisfies every criterion of m.
+func (m Mention) matches(msg domain.EventMessage) bool {
+	if len(m.Kinds) > 0 && !slices.Contains(m.Kinds, msg.Kind) {
+		return false
+	}
+	if m.SuperEarth && (msg.DefendEvent == nil || !domain.IsSuperEarth(msg.DefendEvent.Region)) {
+		return false
+	}
+	return matchesFaction(m.Factions, msg)
+}
+
+// matchesFaction reports whether msg is a defend or attack event against one
+// of factions. An empty list matches any event.
+func matchesFaction(factions []domain.Enemy, msg domain.EventMessage) bool {
+	if len(factions) == 0 {
+		return true
+	}
+	e, ok := msg.Enemy()
+	return ok && slices.Contains(factions, e)
+}
+
+// mentionsFor returns the mentions of every rule matching msg as a content
+// prefix, and the allowed mentions that let exactly those ping. Anything else
+// in the message, such as an @everyone typed in a template, stays silent.
+func mentionsFor(rules []Mention, msg domain.EventMessage) (string, *discordgo.MessageAllowedMentions) {
+	allowed := &discordgo.MessageAllowedMentions{}
+	everyone := false
+	for _, r := range rules {
+		if !r.matches(msg) {
+			continue
+		}
+		everyone = everyone || r.Everyone
+		for _, id := range r.Roles {
+			if !slices.Contains(allowed.Roles, id) {
+				allowed.Roles = append(allowed.Roles, id)
+			}
+		}
+		for _, id := range r.Users {
+			if !slices.Contains(allowed.Users, id) {
+				allowed.Users = append(allowed.Users, id)
+			}
+		}
+	}
+
+	var parts []string
+	if everyone {
+		parts = append(parts, "@everyone")
+		allowed.Parse = []discordgo.AllowedMentionType{discordgo.AllowedMentionTypeEveryone}
+	}
+	for _, id := range allowed.Roles {
+		parts = append(parts, "<@&"+id+">")
+	}
+	for _, id := range allowed.Users {
+		parts = append(parts, "<@"+id+">")
+	}
+	return strings.Join(parts, " "), allowed
+}
@@ -0,0 +1,99 @@
+package discord
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/ametis70/hellbot/internal/domain"
+	"github.com/ametis70/hellbot/internal/testutil"
+)
+
+var testMentions = []Mention{
+	{Factions: []domain.Enemy{domain.EnemyBug}, Kinds: []domain.EventKind{domain.EventKindAttack}, Roles: []string{"bugs"}},
+	{SuperEarth: true, Everyone: true},
+	{Kinds: []domain.EventKind{domain.EventKindDefend, domain.EventKindAttack}, Roles: []string{"divers"}, Users: []string{"42"}},
+}
+
+func TestMentionsFor_FactionAndKind(t *testing.T) {
+	e := testutil.AttackEventActive()
+	e.Enemy = domain.EnemyBug
+	prefix, allowed := mentionsFor(testMentions, domain.EventMessage{Kind: domain.EventKindAttack, AttackEvent: &e})
+	if prefix != "<@&bugs> <@&divers> <@42>" {
+		t.Errorf("unexpected prefix %q", prefix)
+	}
+	if !slices.Equal(allowed.Roles, []string{"bugs", "divers"}) || !slices.Equal(allowed.Users, []string{"42"}) || len(allowed.Parse) != 0 {
+		t.Errorf("unexpected allowed mentions %+v", allowed)
+	}
+}
+
+func TestMentionsFor_SuperEarth(t *testing.T) {
+	prefix, allowed := mentionsFor(testMentions, defendMsgSuperEarth(domain.EventTransitionStarted))
+	if !strings.HasPrefix(prefix, "@everyone ") {
+		t.Errorf("expected @everyone first, got %q", prefix)
+	}
+	if !slices.Equal(allowed.Parse, []discordgo.AllowedMentionType{discordgo.AllowedMentionTypeEveryone}) {
+		t.Errorf("expected everyone to be allowed, got %+v", allowed.Parse)
+	}
+
+	// A regular defense does not match the Super Earth rule.
+	msg := defendMsg(domain.EventTransitionStarted)
+	msg.DefendEvent.Region = 3
+	if prefix, _ := mentionsFor(testMentions, msg); strings.Contains(prefix, "@everyone") {
+		t.Errorf("expected no @everyone for a region defense, got %q", prefix)
+	}
+}
+
+func TestMentionsFor_NoMatchAllowsNothing(t *testing.T) {
+	prefix, allowed := mentionsFor(testMentions, domain.EventMessage{Kind: domain.EventKindWar, WarEvent: &domain.WarEvent{}})
+	if prefix != "" {
+		t.Errorf("expected no mentions, got %q", prefix)
+	}
+	if allowed == nil || len(allowed.Parse) != 0 || len(allowed.Roles) != 0 || len(allowed.Users) != 0 {
+		t.Errorf("expected empty allowed mentions, got %+v", allowed)
+	}
+}
+
+func TestMessage_PrefixesMentions(t *testing.T) {
+	cat := domain.CatalogFor(domain.LocaleEnglish)
+	n := &DiscordNotifier{
+		opts:    Options{Mentions: testMentions, Embeds: []domain.EventKind{domain.EventKindAttack}},
+		catalog: cat,
+	}
+	r := route{
+		templates: domain.MergeTemplates(DefaultTemplates(), domain.Templates{DefendSuperEarthStarted: "@everyone {FACTION}"}),
+		titles:    cat.Titles,
+	}
+
+	m, err := n.message(r, defendMsgSuperEarth(domain.EventTransitionStarted), n.opts.Mentions)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(m.Content, "@everyone <@&divers> <@42>\n") || m.AllowedMentions == nil {
+		t.Errorf("unexpected message %q %+v", m.Content, m.AllowedMentions)
+	}
+
+	e := testutil.AttackEventActive()
+	e.Enemy = domain.EnemyBug
+	m, err = n.message(r, domain.EventMessage{Kind: domain.EventKindAttack, Transition: domain.EventTransitionStarted, AttackEvent: &e}, n.opts.Mentions)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(m.Embeds) != 1 || m.Content != "<@&bugs> <@&divers> <@42>" {
+		t.Errorf("expected embed with mention content, got %q and %d embeds", m.Content, len(m.Embeds))
+	}
+}
+
+func TestMessage_TemplateCannotPing(t *testing.T) {
+	n := &DiscordNotifier{catalog: domain.CatalogFor(domain.LocaleEnglish)}
+	r := route{templates: domain.MergeTemplates(DefaultTemplates(), domain.Templates{WarWon: "@everyone won"})}
+	m, err := n.message(r, domain.EventMessage{Kind: domain.EventKindWar, Transition: domain.EventTransitionSucceeded, WarEvent: &domain.WarEvent{Season: 1}}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if m.AllowedMentions == nil || len(m.AllowedMentions.Parse) != 0 {
+		t.Errorf("expected allowed mentions to block @everyone, got %+v", m.AllowedMentions)
+	}
+}
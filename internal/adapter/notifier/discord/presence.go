@@ -0,0 +1,145 @@
+package discord
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/ametis70/hellbot/internal/domain"
+)
+
+// DefaultPresenceInterval is the minimum time between presence updates when
+// Presence.Interval is zero. Discord limits how often a bot may change its
+// presence.
+const DefaultPresenceInterval = 5 * time.Minute
+
+// Presence sets the bot's custom status after every poll. Defend is shown
+// while a defend event runs, otherwise Attack while an attack runs, otherwise
+// War with the faction showing the most progress. The formats use the same
+// {VARIABLE} placeholders as event templates; empty formats use the locale's
+// defaults.
+type Presence struct {
+	Defend string
+	Attack string
+	War    string
+	// Interval is the minimum time between updates. Changes within it are
+	// picked up by a later poll.
+	Interval time.Duration
+}
+
+// presenceAPI is the part of *discordgo.Session used to set the bot's status.
+type presenceAPI interface {
+	UpdateStatusComplex(usd discordgo.UpdateStatusData) error
+}
+
+// presence tracks the status last sent so unchanged text is not resent and
+// updates are spaced out.
+type presence struct {
+	api     presenceAPI
+	formats Presence
+	text    string
+	last    time.Time
+}
+
+// defaultPresence returns the presence formats for locale.
+func defaultPresence(locale domain.Locale) Presence {
+	if locale == domain.LocaleSpanish {
+		return Presence{
+			Defend: "Defendiendo {REGION_NAME} — {PERCENT}%",
+			Attack: "Atacando el planeta natal de los {FACTION} — {PERCENT}%",
+			War:    "Guerra {SEASON}: {FACTION} {PERCENT}%",
+		}
+	}
+	return Presence{
+		Defend: "Defending {REGION_NAME} — {PERCENT}%",
+		Attack: "Attacking the {FACTION} homeworld — {PERCENT}%",
+		War:    "War {SEASON}: {FACTION} {PERCENT}%",
+	}
+}
+
+// newPresence merges p over the locale's formats.
+func newPresence(api presenceAPI, locale domain.Locale, p Presence) *presence {
+	formats := defaultPresence(locale)
+	if p.Defend != "" {
+		formats.Defend = p.Defend
+	}
+	if p.Attack != "" {
+		formats.Attack = p.Attack
+	}
+	if p.War != "" {
+		formats.War = p.War
+	}
+	formats.Interval = p.Interval
+	if formats.Interval <= 0 {
+		formats.Interval = DefaultPresenceInterval
+	}
+	return &presence{api: api, formats: formats}
+}
+
+// render returns the status for c, or "" when there is nothing to show.
+func (p *presence) render(cat *domain.Catalog, c *domain.CampaignStatus) string {
+	var text string
+	switch msgs := activeEvents(c); {
+	case len(msgs) > 0 && msgs[0].DefendEvent != nil:
+		vars := domain.BuildDefendVars(msgs[0].DefendEvent, formatUTC)
+		vars.Faction = cat.FactionName(msgs[0].DefendEvent.Enemy)
+		text = domain.Render(p.formats.Defend, vars)
+	case len(msgs) > 0:
+		vars := domain.BuildAttackVars(msgs[0].AttackEvent, formatUTC)
+		vars.Faction = cat.FactionName(msgs[0].AttackEvent.Enemy)
+		text = domain.Render(p.formats.Attack, vars)
+	default:
+		f, ok := leadingFaction(c)
+		if !ok {
+			return ""
+		}
+		text = domain.Render(p.formats.War, domain.TemplateVars{
+			Faction: cat.FactionName(f.Enemy),
+			Season:  fmt.Sprintf("%d", f.Season),
+			Percent: fmt.Sprintf("%d", min(f.Points*100/f.PointsMax, 100)),
+		})
+	}
+	// Discord cuts custom statuses at 128 characters.
+	return truncate(text, 128)
+}
+
+// leadingFaction returns the active faction with the most war progress.
+func leadingFaction(c *domain.CampaignStatus) (domain.FactionStatus, bool) {
+	var best domain.FactionStatus
+	found := false
+	for _, f := range c.FactionsStatus {
+		if f.Status != domain.FactionStatusActive || f.PointsMax <= 0 {
+			continue
+		}
+		if !found || f.Points*best.PointsMax > best.Points*f.PointsMax {
+			best, found = f, true
+		}
+	}
+	return best, found
+}
+
+// update sets the bot's custom status to text unless it is unchanged or the
+// last update was less than the interval ago.
+func (p *presence) update(text string, now time.Time) error {
+	if text == "" || text == p.text {
+		return nil
+	}
+	if !p.last.IsZero() && now.Sub(p.last) < p.formats.Interval {
+		return nil
+	}
+	err := p.api.UpdateStatusComplex(discordgo.UpdateStatusData{
+		Status: "online",
+		Activities: []*discordgo.Activity{{
+			// Discord requires a name but only shows State for custom statuses.
+			Name:  "Custom Status",
+			Type:  discordgo.ActivityTypeCustom,
+			State: text,
+		}},
+	})
+	if err != nil {
+		return err
+	}
+	p.text, p.last = text, now
+	return nil
+}
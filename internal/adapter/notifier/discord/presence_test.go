@@ -0,0 +1,103 @@
+package discord
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/ametis70/hellbot/internal/domain"
+	"github.com/ametis70/hellbot/internal/testutil"
+)
+
+// fakePresenceAPI records status updates.
+type fakePresenceAPI struct {
+	updates []discordgo.UpdateStatusData
+}
+
+func (f *fakePresenceAPI) UpdateStatusComplex(usd discordgo.UpdateStatusData) error {
+	f.updates = append(f.updates, usd)
+	return nil
+}
+
+func TestPresence_Render(t *testing.T) {
+	cat := domain.CatalogFor(domain.LocaleEnglish)
+	p := newPresence(nil, domain.LocaleEnglish, Presence{})
+
+	defend := testutil.DefendEventActive()
+	defend.Enemy, defend.Region = domain.EnemyBug, 3
+	defend.Points, defend.PointsMax = 42, 100
+	if got := p.render(cat, &domain.CampaignStatus{DefendEvent: defend}); got != "Defending Ross System — 42%" {
+		t.Errorf("unexpected defend presence %q", got)
+	}
+
+	attack := testutil.AttackEventActive()
+	attack.Enemy = domain.EnemyCyborg
+	attack.Points, attack.PointsMax = 1, 4
+	if got := p.render(cat, &domain.CampaignStatus{AttackEvents: []domain.AttackEvent{attack}}); got != "Attacking the Cyborgs homeworld — 25%" {
+		t.Errorf("unexpected attack presence %q", got)
+	}
+
+	war := &domain.CampaignStatus{FactionsStatus: []domain.FactionStatus{
+		{Enemy: domain.EnemyBug, Season: 160, Points: 63, PointsMax: 100, Status: domain.FactionStatusActive},
+		{Enemy: domain.EnemyCyborg, Season: 160, Points: 90, PointsMax: 100, Status: domain.FactionStatusDefeated},
+		{Enemy: domain.EnemyIlluminate, Season: 160, Points: 10, PointsMax: 100, Status: domain.FactionStatusActive},
+	}}
+	if got := p.render(cat, war); got != "War 160: Bugs 63%" {
+		t.Errorf("unexpected war presence %q", got)
+	}
+	if got := p.render(cat, &domain.CampaignStatus{}); got != "" {
+		t.Errorf("expected no presence without data, got %q", got)
+	}
+}
+
+func TestPresence_CustomFormat(t *testing.T) {
+	p := newPresence(nil, domain.LocaleSpanish, Presence{War: "{FACTION}: {PERCENT}%"})
+	war := &domain.CampaignStatus{FactionsStatus: []domain.FactionStatus{
+		{Enemy: domain.EnemyIlluminate, Season: 160, Points: 5, PointsMax: 10, Status: domain.FactionStatusActive},
+	}}
+	if got := p.render(domain.CatalogFor(domain.LocaleSpanish), war); got != "Iluminados: 50%" {
+		t.Errorf("unexpected presence %q", got)
+	}
+	if p.formats.Defend != defaultPresence(domain.LocaleSpanish).Defend || p.formats.Interval != DefaultPresenceInterval {
+		t.Errorf("expected Spanish defaults, got %+v", p.formats)
+	}
+}
+
+func TestPresence_UpdateIsRateLimited(t *testing.T) {
+	api := &fakePresenceAPI{}
+	p := newPresence(api, domain.LocaleEnglish, Presence{Interval: time.Minute})
+	start := time.Now()
+
+	for _, step := range []struct {
+		text  string
+		after time.Duration
+	}{
+		{"War 160: Bugs 63%", 0},
+		{"War 160: Bugs 63%", 2 * time.Minute}, // unchanged
+		{"War 160: Bugs 64%", 2*time.Minute + time.Second},
+		{"War 160: Bugs 65%", 2*time.Minute + 30*time.Second}, // too soon
+		{"War 160: Bugs 65%", 3*time.Minute + 30*time.Second},
+	} {
+		if err := p.update(step.text, start.Add(step.after)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	var got []string
+	for _, u := range api.updates {
+		if len(u.Activities) != 1 || u.Activities[0].Type != discordgo.ActivityTypeCustom {
+			t.Fatalf("expected a custom status, got %+v", u)
+		}
+		got = append(got, u.Activities[0].State)
+	}
+	want := []string{"War 160: Bugs 63%", "War 160: Bugs 64%", "War 160: Bugs 65%"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("update %d: expected %q, got %q", i, want[i], got[i])
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package discord
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/ametis70/hellbot/internal/domain"
+)
+
+// scheduleAPI is the part of *discordgo.Session used for guild scheduled events.
+type scheduleAPI interface {
+	GuildScheduledEventCreate(guildID string, event *discordgo.GuildScheduledEventParams, options ...discordgo.RequestOption) (*discordgo.GuildScheduledEvent, error)
+	GuildScheduledEventEdit(guildID, eventID string, event *discordgo.GuildScheduledEventParams, options ...discordgo.RequestOption) (*discordgo.GuildScheduledEvent, error)
+}
+
+// scheduledEventKey is the MessageRefStore key of the scheduled event for a
+// defend or attack event in guildID. ok is false for other events.
+func scheduledEventKey(guildID string, msg domain.EventMessage) (key string, ok bool) {
+	switch {
+	case msg.DefendEvent != nil:
+		return fmt.Sprintf("discord:scheduled:%s:%s:%d", guildID, domain.EventKindDefend, msg.DefendEvent.ID), true
+	case msg.AttackEvent != nil:
+		return fmt.Sprintf("discord:scheduled:%s:%s:%d", guildID, domain.EventKindAttack, msg.AttackEvent.ID), true
+	}
+	return "", false
+}
+
+// eventWindow returns the start and end time of a defend or attack event.
+func eventWindow(msg domain.EventMessage) (start, end time.Time) {
+	switch {
+	case msg.DefendEvent != nil:
+		return msg.DefendEvent.StartTime, msg.DefendEvent.EndTime
+	case msg.AttackEvent != nil:
+		return msg.AttackEvent.StartTime, msg.AttackEvent.EndTime
+	}
+	return time.Time{}, time.Time{}
+}
+
+// syncScheduledEvent creates a guild scheduled event when a defend or attack
+// starts, and completes it with the outcome when it ends.
+func (n *DiscordNotifier) syncScheduledEvent(api scheduleAPI, msg domain.EventMessage) error {
+	if !n.opts.ScheduledEvents {
+		return nil
+	}
+	key, ok := scheduledEventKey(n.opts.GuildID, msg)
+	if !ok {
+		return nil
+	}
+	switch msg.Transition {
+	case domain.EventTransitionStarted:
+		return n.createScheduledEvent(api, key, msg)
+	case domain.EventTransitionSucceeded, domain.EventTransitionFailed:
+		return n.completeScheduledEvent(api, key, msg)
+	}
+	return nil
+}
+
+// createScheduledEvent creates an external event at the event's region and
+// starts it. Discord rejects start times in the past, so an event that is
+// already running is scheduled a minute ahead and started right away.
+func (n *DiscordNotifier) createScheduledEvent(api scheduleAPI, key string, msg domain.EventMessage) error {
+	start, end := eventWindow(msg)
+	if earliest := time.Now().Add(time.Minute); start.Before(earliest) {
+		start = earliest
+	}
+	if !end.After(start) {
+		return nil
+	}
+	name, description, err := n.plainEventDetails(msg)
+	if err != nil {
+		return fmt.Errorf("discord notifier: rendering scheduled event: %w", err)
+	}
+
+	ev, err := api.GuildScheduledEventCreate(n.opts.GuildID, &discordgo.GuildScheduledEventParams{
+		Name:               name,
+		Description:        description,
+		ScheduledStartTime: &start,
+		ScheduledEndTime:   &end,
+		PrivacyLevel:       discordgo.GuildScheduledEventPrivacyLevelGuildOnly,
+		EntityType:         discordgo.GuildScheduledEventEntityTypeExternal,
+		EntityMetadata:     &discordgo.GuildScheduledEventEntityMetadata{Location: eventLocation(msg)},
+	})
+	if err != nil {
+		return fmt.Errorf("discord notifier: creating scheduled event: %w", err)
+	}
+	if err := n.opts.Refs.SaveMessageRef(key, ev.ID); err != nil {
+		return fmt.Errorf("discord notifier: saving scheduled event ID: %w", err)
+	}
+	if _, err := api.GuildScheduledEventEdit(n.opts.GuildID, ev.ID, &discordgo.GuildScheduledEventParams{
+		Status: discordgo.GuildScheduledEventStatusActive,
+	}); err != nil {
+		return fmt.Errorf("discord notifier: starting scheduled event: %w", err)
+	}
+	return nil
+}
+
+// completeScheduledEvent renames the event after its outcome and completes it.
+// Events created before scheduled events were enabled are skipped.
+func (n *DiscordNotifier) completeScheduledEvent(api scheduleAPI, key string, msg domain.EventMessage) error {
+	id, err := n.opts.Refs.GetMessageRef(key)
+	if err != nil {
+		return fmt.Errorf("discord notifier: loading scheduled event ID: %w", err)
+	}
+	if id == "" {
+		return nil
+	}
+	name, description, err := n.plainEventDetails(msg)
+	if err != nil {
+		return fmt.Errorf("discord notifier: rendering scheduled event: %w", err)
+	}
+	if _, err := api.GuildScheduledEventEdit(n.opts.GuildID, id, &discordgo.GuildScheduledEventParams{
+		Name:        name,
+		Description: description,
+		Status:      discordgo.GuildScheduledEventStatusCompleted,
+	}); err != nil {
+		return fmt.Errorf("discord notifier: completing scheduled event: %w", err)
+	}
+	if err := n.opts.Refs.SaveMessageRef(key, ""); err != nil {
+		return fmt.Errorf("discord notifier: clearing scheduled event ID: %w", err)
+	}
+	return nil
+}
+
+// plainEventDetails returns the locale's plain-text title as the name of a
+// scheduled event or thread, and the remaining message fields as its
+// description.
+func (n *DiscordNotifier) plainEventDetails(msg domain.EventMessage) (name, description string, err error) {
+	m, err := n.catalog.BuildMessage(n.catalog.Titles, msg, formatUTC)
+	if err != nil {
+		return "", "", err
+	}
+	name = m.Title
+	m.Title = ""
+	return truncate(name, 100), truncate(m.Format(domain.PlainMarkup(formatUTC)), 1000), nil
+}
+
+// eventLocation returns the region of a defend or attack event and its capital.
+func eventLocation(msg domain.EventMessage) string {
+	var region domain.Region
+	switch {
+	case msg.DefendEvent != nil:
+		region = domain.GetRegion(msg.DefendEvent.Enemy, msg.DefendEvent.Region)
+	case msg.AttackEvent != nil:
+		region = domain.GetRegion(msg.AttackEvent.Enemy, domain.HomeWorldRegion)
+	}
+	if region.Capital == "" || region.Capital == region.Name {
+		return region.Name
+	}
+	return region.Name + " — " + region.Capital
+}
+
+// formatUTC formats times in scheduled events, which cannot show Discord
+// timestamps.
+func formatUTC(t time.Time) string {
+	return t.UTC().Format("2006-01-02 15:04 UTC")
+}
+
+// truncate shortens s to at most n runes.
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
@@ -0,0 +1,161 @@
+package discord
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/ametis70/hellbot/internal/adapter/store/memory"
+	"github.com/ametis70/hellbot/internal/domain"
+	"github.com/ametis70/hellbot/internal/testutil"
+)
+
+// fakeScheduleAPI records scheduled event calls.
+type fakeScheduleAPI struct {
+	created []*discordgo.GuildScheduledEventParams
+	edits   map[string][]*discordgo.GuildScheduledEventParams
+}
+
+func (f *fakeScheduleAPI) GuildScheduledEventCreate(guildID string, e *discordgo.GuildScheduledEventParams, _ ...discordgo.RequestOption) (*discordgo.GuildScheduledEvent, error) {
+	f.created = append(f.created, e)
+	return &discordgo.GuildScheduledEvent{ID: "sched-1", GuildID: guildID}, nil
+}
+
+func (f *fakeScheduleAPI) GuildScheduledEventEdit(guildID, eventID string, e *discordgo.GuildScheduledEventParams, _ ...discordgo.RequestOption) (*discordgo.GuildScheduledEvent, error) {
+	if f.edits == nil {
+		f.edits = map[string][]*discordgo.GuildScheduledEventParams{}
+	}
+	f.edits[eventID] = append(f.edits[eventID], e)
+	return &discordgo.GuildScheduledEvent{ID: eventID, GuildID: guildID}, nil
+}
+
+// scheduledIn creates scheduled events in guildID, storing their IDs in refs.
+func scheduledIn(guildID string, refs *memory.MemoryStore) func(*Options) {
+	return func(o *Options) {
+		o.GuildID = guildID
+		o.ScheduledEvents = true
+		o.Refs = refs
+	}
+}
+
+func TestScheduledEvent_CreateAndComplete(t *testing.T) {
+	refs := memory.New()
+	n := newTestNotifier(t, scheduledIn("guild", refs))
+	api := &fakeScheduleAPI{}
+	e := testutil.DefendEventActive()
+	e.Enemy = domain.EnemyBug
+	e.Region = 3
+	e.StartTime = time.Now().Add(-time.Hour)
+	e.EndTime = time.Now().Add(5 * time.Hour)
+
+	started := domain.EventMessage{Kind: domain.EventKindDefend, Transition: domain.EventTransitionStarted, DefendEvent: e}
+	if err := n.syncScheduledEvent(api, started); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(api.created) != 1 {
+		t.Fatalf("expected one scheduled event, got %d", len(api.created))
+	}
+	c := api.created[0]
+	if c.Name != "⚔️ The Bugs are attacking Ross System!" || c.EntityType != discordgo.GuildScheduledEventEntityTypeExternal {
+		t.Errorf("unexpected event %+v", c)
+	}
+	if c.EntityMetadata == nil || c.EntityMetadata.Location != "Ross System — Tiberia" {
+		t.Errorf("unexpected location %+v", c.EntityMetadata)
+	}
+	if !c.ScheduledStartTime.After(time.Now()) || !c.ScheduledEndTime.Equal(e.EndTime) {
+		t.Errorf("expected a future start and the event's end, got %v %v", c.ScheduledStartTime, c.ScheduledEndTime)
+	}
+	if !strings.Contains(c.Description, "Region: Ross System (3/10)") {
+		t.Errorf("unexpected description %q", c.Description)
+	}
+	if edits := api.edits["sched-1"]; len(edits) != 1 || edits[0].Status != discordgo.GuildScheduledEventStatusActive {
+		t.Errorf("expected the event to be started, got %+v", edits)
+	}
+	key, _ := scheduledEventKey("guild", started)
+	if id, _ := refs.GetMessageRef(key); id != "sched-1" {
+		t.Errorf("expected the scheduled event ID to be stored, got %q", id)
+	}
+
+	succeeded := started
+	succeeded.Transition = domain.EventTransitionSucceeded
+	if err := n.syncScheduledEvent(api, succeeded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	edits := api.edits["sched-1"]
+	if len(edits) != 2 || edits[1].Status != discordgo.GuildScheduledEventStatusCompleted || !strings.HasPrefix(edits[1].Name, "✅") {
+		t.Errorf("expected the event to be completed with the outcome, got %+v", edits)
+	}
+	if id, _ := refs.GetMessageRef(key); id != "" {
+		t.Errorf("expected the ref to be cleared, got %q", id)
+	}
+}
+
+func TestScheduledEvent_PerGuild(t *testing.T) {
+	refs := memory.New()
+	a, b := newTestNotifier(t, scheduledIn("guild-a", refs)), newTestNotifier(t, scheduledIn("guild-b", refs))
+	apiA, apiB := &fakeScheduleAPI{}, &fakeScheduleAPI{}
+	started := attackMsg(domain.EventTransitionStarted)
+	started.AttackEvent.EndTime = time.Now().Add(time.Hour)
+
+	for _, c := range []struct {
+		n   *DiscordNotifier
+		api *fakeScheduleAPI
+	}{{a, apiA}, {b, apiB}} {
+		if err := c.n.syncScheduledEvent(c.api, started); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(c.api.created) != 1 {
+			t.Fatalf("expected one scheduled event in %s, got %d", c.n.opts.GuildID, len(c.api.created))
+		}
+	}
+
+	succeeded := started
+	succeeded.Transition = domain.EventTransitionSucceeded
+	if err := a.syncScheduledEvent(apiA, succeeded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if edits := apiA.edits["sched-1"]; len(edits) != 2 {
+		t.Errorf("expected guild-a's event to be completed, got %+v", edits)
+	}
+	keyB, _ := scheduledEventKey("guild-b", started)
+	if id, _ := refs.GetMessageRef(keyB); id != "sched-1" {
+		t.Errorf("expected guild-b's event to stay open, got %q", id)
+	}
+}
+
+func TestScheduledEvent_SkipsUnknownAndOtherEvents(t *testing.T) {
+	n := newTestNotifier(t, scheduledIn("guild", memory.New()))
+	api := &fakeScheduleAPI{}
+
+	attack := testutil.AttackEventFailed()
+	failed := domain.EventMessage{Kind: domain.EventKindAttack, Transition: domain.EventTransitionFailed, AttackEvent: &attack}
+	if err := n.syncScheduledEvent(api, failed); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	war := domain.EventMessage{Kind: domain.EventKindWar, Transition: domain.EventTransitionSucceeded, WarEvent: &domain.WarEvent{}}
+	if err := n.syncScheduledEvent(api, war); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(api.created) != 0 || len(api.edits) != 0 {
+		t.Errorf("expected no calls, got %+v %+v", api.created, api.edits)
+	}
+}
+
+func TestScheduledEvent_Disabled(t *testing.T) {
+	n := &DiscordNotifier{}
+	api := &fakeScheduleAPI{}
+	if err := n.syncScheduledEvent(api, attackMsg(domain.EventTransitionStarted)); err != nil || len(api.created) != 0 {
+		t.Errorf("expected nothing when disabled, got err=%v created=%d", err, len(api.created))
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	if got := truncate("héllo", 10); got != "héllo" {
+		t.Errorf("unexpected %q", got)
+	}
+	if got := truncate("héllo", 3); got != "hé…" {
+		t.Errorf("unexpected %q", got)
+	}
+}
@@ -0,0 +1,146 @@
+package discord
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/ametis70/hellbot/internal/domain"
+)
+
+// subscriptionScope namespaces the Discord user IDs subscribed through this
+// notifier in the subscription store, so that other notifiers do not DM them.
+func (n *DiscordNotifier) subscriptionScope() string {
+	return "discord:" + n.opts.ID
+}
+
+// dmAPI is the part of *discordgo.Session used to send direct messages.
+type dmAPI interface {
+	UserChannelCreate(recipientID string, options ...discordgo.RequestOption) (*discordgo.Channel, error)
+	ChannelMessageSendComplex(channelID string, data *discordgo.MessageSend, options ...discordgo.RequestOption) (*discordgo.Message, error)
+}
+
+// subscriptionCommands are registered when subscriptions are enabled.
+func subscriptionCommands() []*discordgo.ApplicationCommand {
+	event := textOption(
+		localized{"event", "evento"},
+		localized{"Only this kind of event", "Solo este tipo de evento"},
+		false,
+		choice(localized{"Defend", "Defensa"}, string(domain.EventKindDefend)),
+		choice(localized{"Attack", "Ataque"}, string(domain.EventKindAttack)),
+		choice(localized{"War", "Guerra"}, string(domain.EventKindWar)),
+		choice(localized{"Players", "Jugadores"}, string(domain.EventKindPlayers)),
+		choice(localized{"Milestone", "Hito"}, string(domain.EventKindMilestone)),
+	)
+	return []*discordgo.ApplicationCommand{
+		command(
+			localized{"subscribe", "suscribirse"},
+			localized{"Receive a DM for war events, optionally filtered by faction and event", "Recibe un MD por eventos de la guerra, opcionalmente por facción y evento"},
+			factionOption(false), event),
+		command(
+			localized{"unsubscribe", "desuscribirse"},
+			localized{"Stop receiving war event DMs", "Deja de recibir MD de eventos de la guerra"}),
+	}
+}
+
+// interactionUserID returns the ID of the user who ran a command, in a guild
+// or in a DM.
+func interactionUserID(i *discordgo.InteractionCreate) string {
+	if i.Member != nil && i.Member.User != nil {
+		return i.Member.User.ID
+	}
+	if i.User != nil {
+		return i.User.ID
+	}
+	return ""
+}
+
+func (n *DiscordNotifier) handleSubscribeCommand(s *discordgo.Session, i *discordgo.InteractionCreate, data discordgo.ApplicationCommandInteractionData) {
+	reply, err := n.subscribe(interactionUserID(i), data.Options)
+	if err != nil {
+		n.logger.Error("discord: saving subscription failed", "user", interactionUserID(i), "error", err)
+		reply = n.catalog.SubscribeFailed
+	}
+	n.respondEphemeral(s, i, reply)
+}
+
+func (n *DiscordNotifier) handleUnsubscribeCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	reply := n.catalog.UnsubscribedDM
+	if err := n.opts.Subscriptions.RemoveSubscription(n.subscriptionScope(), interactionUserID(i)); err != nil {
+		n.logger.Error("discord: removing subscription failed", "user", interactionUserID(i), "error", err)
+		reply = n.catalog.UnsubscribeFailed
+	}
+	n.respondEphemeral(s, i, reply)
+}
+
+// respondEphemeral replies with a message only the invoking user can see.
+func (n *DiscordNotifier) respondEphemeral(s *discordgo.Session, i *discordgo.InteractionCreate, content string) {
+	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
+
+// subscribe replaces userID's subscription with the filters from the command
+// options and returns the confirmation shown to the user.
+func (n *DiscordNotifier) subscribe(userID string, options []*discordgo.ApplicationCommandInteractionDataOption) (string, error) {
+	if userID == "" {
+		return "", errors.New("unknown user")
+	}
+	sub := domain.Subscription{ID: userID}
+	for _, opt := range options {
+		switch opt.Name {
+		case "faction":
+			if e, ok := domain.ParseEnemy(opt.StringValue()); ok {
+				sub.Factions = []domain.Enemy{e}
+			}
+		case "event":
+			if k := domain.EventKind(opt.StringValue()); k.Valid() {
+				sub.Kinds = []domain.EventKind{k}
+			}
+		}
+	}
+	if err := n.opts.Subscriptions.SaveSubscription(n.subscriptionScope(), sub); err != nil {
+		return "", err
+	}
+	return n.catalog.SubscribedMessage(sub), nil
+}
+
+// notifySubscribers sends msg as a DM to every subscriber whose filters match.
+// Failing to reach one user, e.g. because their DMs are closed, is logged and
+// does not stop the others.
+func (n *DiscordNotifier) notifySubscribers(api dmAPI, msg domain.EventMessage) error {
+	if n.opts.Subscriptions == nil {
+		return nil
+	}
+	subs, err := n.opts.Subscriptions.ListSubscriptions(n.subscriptionScope())
+	if err != nil {
+		return fmt.Errorf("discord notifier: listing subscriptions: %w", err)
+	}
+
+	var m *discordgo.MessageSend
+	for _, sub := range subs {
+		if !sub.Matches(msg) {
+			continue
+		}
+		if m == nil {
+			// DMs carry no mentions.
+			if m, err = n.message(n.dm, msg, nil); err != nil {
+				return fmt.Errorf("discord notifier: rendering message: %w", err)
+			}
+		}
+		ch, err := api.UserChannelCreate(sub.ID)
+		if err != nil {
+			n.logger.Warn("discord: failed to open DM", "user", sub.ID, "error", err)
+			continue
+		}
+		if _, err := api.ChannelMessageSendComplex(ch.ID, m); err != nil {
+			n.logger.Warn("discord: failed to send DM", "user", sub.ID, "error", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package discord
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/ametis70/hellbot/internal/adapter/store/memory"
+	"github.com/ametis70/hellbot/internal/domain"
+	"github.com/ametis70/hellbot/internal/testutil"
+)
+
+// fakeDMAPI records DMs per user. Users in closed cannot be messaged.
+type fakeDMAPI struct {
+	sent   map[string]*discordgo.MessageSend
+	closed map[string]bool
+}
+
+func (f *fakeDMAPI) UserChannelCreate(recipientID string, _ ...discordgo.RequestOption) (*discordgo.Channel, error) {
+	if f.closed[recipientID] {
+		return nil, errors.New("cannot send messages to this user")
+	}
+	return &discordgo.Channel{ID: "dm-" + recipientID}, nil
+}
+
+func (f *fakeDMAPI) ChannelMessageSendComplex(channelID string, data *discordgo.MessageSend, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+	if f.sent == nil {
+		f.sent = map[string]*discordgo.MessageSend{}
+	}
+	f.sent[channelID] = data
+	return &discordgo.Message{ChannelID: channelID}, nil
+}
+
+// withSubscriptions enables /subscribe with store.
+func withSubscriptions(store *memory.MemoryStore) func(*Options) {
+	return func(o *Options) { o.Subscriptions = store }
+}
+
+func stringOption(name, value string) *discordgo.ApplicationCommandInteractionDataOption {
+	return &discordgo.ApplicationCommandInteractionDataOption{Name: name, Type: discordgo.ApplicationCommandOptionString, Value: value}
+}
+
+func TestSubscribe_SavesFilters(t *testing.T) {
+	store := memory.New()
+	n := newTestNotifier(t, withSubscriptions(store))
+
+	reply, err := n.subscribe("42", []*discordgo.ApplicationCommandInteractionDataOption{
+		stringOption("faction", "bugs"),
+		stringOption("event", "attack"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(reply, "attack events for the Bugs") {
+		t.Errorf("unexpected reply %q", reply)
+	}
+	subs, _ := store.ListSubscriptions(n.subscriptionScope())
+	if len(subs) != 1 || subs[0].ID != "42" || subs[0].Factions[0] != domain.EnemyBug || subs[0].Kinds[0] != domain.EventKindAttack {
+		t.Errorf("unexpected subscriptions %+v", subs)
+	}
+
+	// Subscribing again replaces the filters.
+	if _, err := n.subscribe("42", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	subs, _ = store.ListSubscriptions(n.subscriptionScope())
+	if len(subs) != 1 || len(subs[0].Factions) != 0 || len(subs[0].Kinds) != 0 {
+		t.Errorf("expected filters to be cleared, got %+v", subs)
+	}
+}
+
+func TestSubscribe_Spanish(t *testing.T) {
+	n := newTestNotifier(t, withSubscriptions(memory.New()), func(o *Options) { o.Locale = domain.LocaleSpanish })
+
+	reply, err := n.subscribe("42", []*discordgo.ApplicationCommandInteractionDataOption{stringOption("faction", "bugs")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(reply, "de los Insectos") {
+		t.Errorf("expected a Spanish confirmation, got %q", reply)
+	}
+}
+
+func TestNotifySubscribers_SendsMatchingDMs(t *testing.T) {
+	store := memory.New()
+	n := newTestNotifier(t, withSubscriptions(store), func(o *Options) { o.Mentions = []Mention{{Everyone: true}} })
+	_ = store.SaveSubscription(n.subscriptionScope(), domain.Subscription{ID: "illuminate", Factions: []domain.Enemy{domain.EnemyIlluminate}})
+	_ = store.SaveSubscription(n.subscriptionScope(), domain.Subscription{ID: "bugs", Factions: []domain.Enemy{domain.EnemyBug}})
+	_ = store.SaveSubscription(n.subscriptionScope(), domain.Subscription{ID: "closed"})
+	_ = store.SaveSubscription(n.subscriptionScope(), domain.Subscription{ID: "all"})
+	api := &fakeDMAPI{closed: map[string]bool{"closed": true}}
+
+	msg := domain.EventMessage{Kind: domain.EventKindDefend, Transition: domain.EventTransitionStarted, DefendEvent: testutil.DefendEventActive()}
+	if err := n.notifySubscribers(api, msg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(api.sent) != 2 || api.sent["dm-illuminate"] == nil || api.sent["dm-all"] == nil {
+		t.Fatalf("expected DMs to illuminate and all, got %v", api.sent)
+	}
+	dm := api.sent["dm-all"]
+	if strings.Contains(dm.Content, "@everyone") || len(dm.AllowedMentions.Parse) != 0 {
+		t.Errorf("expected DMs without mentions, got %q", dm.Content)
+	}
+}
+
+func TestNotifySubscribers_OnlyOwnSubscribers(t *testing.T) {
+	store := memory.New()
+	n := newTestNotifier(t, withSubscriptions(store))
+	other := newTestNotifier(t, withSubscriptions(store), func(o *Options) { o.ID = "other" })
+	if _, err := n.subscribe("42", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	msg := domain.EventMessage{Kind: domain.EventKindDefend, Transition: domain.EventTransitionStarted, DefendEvent: testutil.DefendEventActive()}
+	api := &fakeDMAPI{}
+	if err := other.notifySubscribers(api, msg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(api.sent) != 0 {
+		t.Errorf("expected no DMs from another notifier, got %v", api.sent)
+	}
+	if err := n.notifySubscribers(api, msg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(api.sent) != 1 {
+		t.Errorf("expected one DM, got %v", api.sent)
+	}
+}
+
+func TestNotifySubscribers_Disabled(t *testing.T) {
+	n := &DiscordNotifier{}
+	if err := n.notifySubscribers(&fakeDMAPI{}, domain.EventMessage{}); err != nil {
+		t.Errorf("expected no error without a subscription store, got %v", err)
+	}
+}
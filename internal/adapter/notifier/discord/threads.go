@@ -0,0 +1,224 @@
+package discord
+
+import (
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/ametis70/hellbot/internal/domain"
+)
+
+// threadAutoArchive is how long, in minutes, an event thread stays open
+// without activity. Progress updates keep it open while the event runs.
+const threadAutoArchive = 1440
+
+// progressStep is the progress change, in percent, that triggers an update
+// in an event thread.
+const progressStep = 10
+
+// channelAPI is the part of *discordgo.Session used to post event messages
+// and their threads.
+type channelAPI interface {
+	ChannelMessageSendComplex(channelID string, data *discordgo.MessageSend, options ...discordgo.RequestOption) (*discordgo.Message, error)
+	MessageThreadStartComplex(channelID, messageID string, data *discordgo.ThreadStart, options ...discordgo.RequestOption) (*discordgo.Channel, error)
+	ChannelEdit(channelID string, data *discordgo.ChannelEdit, options ...discordgo.RequestOption) (*discordgo.Channel, error)
+}
+
+// threadKey is the MessageRefStore key of the thread for a defend or attack
+// event in channelID.
+func threadKey(channelID string, kind domain.EventKind, id int) string {
+	return fmt.Sprintf("discord:thread:%s:%s:%d", channelID, kind, id)
+}
+
+// eventThreadKey returns the thread key for msg in channelID. ok is false for
+// events other than defends and attacks.
+func eventThreadKey(channelID string, msg domain.EventMessage) (key string, ok bool) {
+	switch {
+	case msg.DefendEvent != nil:
+		return threadKey(channelID, domain.EventKindDefend, msg.DefendEvent.ID), true
+	case msg.AttackEvent != nil:
+		return threadKey(channelID, domain.EventKindAttack, msg.AttackEvent.ID), true
+	}
+	return "", false
+}
+
+// send posts msg to route r. With threads enabled, a started defend or attack
+// opens a thread on its message, and the outcome is posted in that thread,
+// which is then archived.
+func (n *DiscordNotifier) send(api channelAPI, r route, msg domain.EventMessage) error {
+	m, err := n.message(r, msg, n.opts.Mentions)
+	if err != nil {
+		return fmt.Errorf("discord notifier: rendering message: %w", err)
+	}
+
+	key, threaded := eventThreadKey(r.ChannelID, msg)
+	threaded = threaded && n.opts.Threads
+	threadID, err := n.endedThread(r, msg)
+	if err != nil {
+		return err
+	}
+	if threadID != "" {
+		return n.closeThread(api, key, threadID, m)
+	}
+
+	sent, err := api.ChannelMessageSendComplex(r.ChannelID, m)
+	if err != nil {
+		return fmt.Errorf("discord notifier: sending message to %s: %w", r.ChannelID, err)
+	}
+	if threaded && msg.Transition == domain.EventTransitionStarted {
+		return n.startThread(api, key, r.ChannelID, sent.ID, msg)
+	}
+	return nil
+}
+
+// endedThread returns the ID of the thread on route r that msg, an outcome,
+// should close. It is empty for other messages and for events started before
+// threads were enabled.
+func (n *DiscordNotifier) endedThread(r route, msg domain.EventMessage) (string, error) {
+	key, ok := eventThreadKey(r.ChannelID, msg)
+	if !ok || !n.opts.Threads {
+		return "", nil
+	}
+	if msg.Transition != domain.EventTransitionSucceeded && msg.Transition != domain.EventTransitionFailed {
+		return "", nil
+	}
+	threadID, err := n.opts.Refs.GetMessageRef(key)
+	if err != nil {
+		return "", fmt.Errorf("discord notifier: loading thread ID: %w", err)
+	}
+	return threadID, nil
+}
+
+// closeMutedThread posts the outcome in the event's thread and archives it
+// without posting to the channel, so threads do not stay open while muted.
+func (n *DiscordNotifier) closeMutedThread(api channelAPI, r route, msg domain.EventMessage) error {
+	threadID, err := n.endedThread(r, msg)
+	if err != nil || threadID == "" {
+		return err
+	}
+	m, err := n.message(r, msg, nil)
+	if err != nil {
+		return fmt.Errorf("discord notifier: rendering message: %w", err)
+	}
+	key, _ := eventThreadKey(r.ChannelID, msg)
+	return n.closeThread(api, key, threadID, m)
+}
+
+// startThread opens a thread on the start message of an event and remembers
+// it under key.
+func (n *DiscordNotifier) startThread(api channelAPI, key, channelID, messageID string, msg domain.EventMessage) error {
+	name, _, err := n.plainEventDetails(msg)
+	if err != nil {
+		return fmt.Errorf("discord notifier: rendering thread name: %w", err)
+	}
+	th, err := api.MessageThreadStartComplex(channelID, messageID, &discordgo.ThreadStart{
+		Name:                name,
+		AutoArchiveDuration: threadAutoArchive,
+	})
+	if err != nil {
+		return fmt.Errorf("discord notifier: starting thread: %w", err)
+	}
+	if err := n.opts.Refs.SaveMessageRef(key, th.ID); err != nil {
+		return fmt.Errorf("discord notifier: saving thread ID: %w", err)
+	}
+	if p, ok := eventProgress(msg); ok {
+		n.progress[key] = p / progressStep
+	}
+	return nil
+}
+
+// closeThread posts the outcome m in the thread, archives it and forgets it.
+func (n *DiscordNotifier) closeThread(api channelAPI, key, threadID string, m *discordgo.MessageSend) error {
+	if _, err := api.ChannelMessageSendComplex(threadID, m); err != nil {
+		return fmt.Errorf("discord notifier: sending outcome to thread %s: %w", threadID, err)
+	}
+	archived := true
+	if _, err := api.ChannelEdit(threadID, &discordgo.ChannelEdit{Archived: &archived}); err != nil {
+		return fmt.Errorf("discord notifier: archiving thread %s: %w", threadID, err)
+	}
+	delete(n.progress, key)
+	if err := n.opts.Refs.SaveMessageRef(key, ""); err != nil {
+		return fmt.Errorf("discord notifier: clearing thread ID: %w", err)
+	}
+	return nil
+}
+
+// eventPoints returns the points and goal of a defend or attack event.
+func eventPoints(msg domain.EventMessage) (points, pointsMax int) {
+	switch {
+	case msg.DefendEvent != nil:
+		return msg.DefendEvent.Points, msg.DefendEvent.PointsMax
+	case msg.AttackEvent != nil:
+		return msg.AttackEvent.Points, msg.AttackEvent.PointsMax
+	}
+	return 0, 0
+}
+
+// eventProgress returns the progress of a defend or attack event in percent.
+// ok is false when the event has no points.
+func eventProgress(msg domain.EventMessage) (percent int, ok bool) {
+	points, pointsMax := eventPoints(msg)
+	if pointsMax <= 0 {
+		return 0, false
+	}
+	return min(points*100/pointsMax, 100), true
+}
+
+// activeEvents returns the running defend and attack events of c as started
+// event messages.
+func activeEvents(c *domain.CampaignStatus) []domain.EventMessage {
+	var msgs []domain.EventMessage
+	if e := c.DefendEvent; e != nil && e.Status == domain.EventStatusActive {
+		msgs = append(msgs, domain.EventMessage{Kind: domain.EventKindDefend, Transition: domain.EventTransitionStarted, DefendEvent: e})
+	}
+	for i := range c.AttackEvents {
+		if e := &c.AttackEvents[i]; e.Status == domain.EventStatusActive {
+			msgs = append(msgs, domain.EventMessage{Kind: domain.EventKindAttack, Transition: domain.EventTransitionStarted, AttackEvent: e})
+		}
+	}
+	return msgs
+}
+
+// postProgress posts the progress of every running event to its threads
+// each time it moves into a new progressStep. After a restart the current
+// progress is posted once.
+func (n *DiscordNotifier) postProgress(api channelAPI, c *domain.CampaignStatus) {
+	for _, msg := range activeEvents(c) {
+		p, ok := eventProgress(msg)
+		if !ok {
+			continue
+		}
+		for _, r := range n.routes {
+			if !r.matches(msg) {
+				continue
+			}
+			key, _ := eventThreadKey(r.ChannelID, msg)
+			if last, seen := n.progress[key]; seen && last == p/progressStep {
+				continue
+			}
+			threadID, err := n.opts.Refs.GetMessageRef(key)
+			if err != nil {
+				n.logger.Error("discord: failed to load thread ID", "key", key, "error", err)
+				continue
+			}
+			if threadID == "" {
+				continue
+			}
+			if _, err := api.ChannelMessageSendComplex(threadID, &discordgo.MessageSend{
+				Content:         n.progressText(msg, p),
+				AllowedMentions: &discordgo.MessageAllowedMentions{},
+			}); err != nil {
+				n.logger.Error("discord: failed to post progress", "thread", threadID, "error", err)
+				continue
+			}
+			n.progress[key] = p / progressStep
+		}
+	}
+}
+
+// progressText renders a progress update, e.g. "📈 **Progress:** 40% (400 / 1,000)".
+func (n *DiscordNotifier) progressText(msg domain.EventMessage, percent int) string {
+	points, pointsMax := eventPoints(msg)
+	return fmt.Sprintf("📈 **%s:** %d%% (%s / %s)", n.catalog.FieldProgress, percent,
+		n.catalog.FormatInt(points), n.catalog.FormatInt(pointsMax))
+}
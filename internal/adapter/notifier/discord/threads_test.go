@@ -0,0 +1,133 @@
+package discord
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/ametis70/hellbot/internal/adapter/store/memory"
+	"github.com/ametis70/hellbot/internal/domain"
+	"github.com/ametis70/hellbot/internal/testutil"
+)
+
+// fakeChannelAPI records messages per channel, started threads and edits.
+type fakeChannelAPI struct {
+	sent     map[string][]*discordgo.MessageSend
+	threads  map[string]*discordgo.ThreadStart
+	archived []string
+}
+
+func (f *fakeChannelAPI) ChannelMessageSendComplex(channelID string, data *discordgo.MessageSend, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+	if f.sent == nil {
+		f.sent = map[string][]*discordgo.MessageSend{}
+	}
+	f.sent[channelID] = append(f.sent[channelID], data)
+	return &discordgo.Message{ID: "msg-" + channelID, ChannelID: channelID}, nil
+}
+
+func (f *fakeChannelAPI) MessageThreadStartComplex(channelID, messageID string, data *discordgo.ThreadStart, _ ...discordgo.RequestOption) (*discordgo.Channel, error) {
+	if f.threads == nil {
+		f.threads = map[string]*discordgo.ThreadStart{}
+	}
+	f.threads[messageID] = data
+	return &discordgo.Channel{ID: "thread-" + channelID}, nil
+}
+
+func (f *fakeChannelAPI) ChannelEdit(channelID string, data *discordgo.ChannelEdit, _ ...discordgo.RequestOption) (*discordgo.Channel, error) {
+	if data.Archived != nil && *data.Archived {
+		f.archived = append(f.archived, channelID)
+	}
+	return &discordgo.Channel{ID: channelID}, nil
+}
+
+// withThreads opens threads, storing their IDs in refs.
+func withThreads(refs *memory.MemoryStore) func(*Options) {
+	return func(o *Options) {
+		o.Threads = true
+		o.Refs = refs
+	}
+}
+
+func TestThreads_StartProgressAndOutcome(t *testing.T) {
+	refs := memory.New()
+	n := newTestNotifier(t, withThreads(refs))
+	api := &fakeChannelAPI{}
+	e := testutil.DefendEventActive()
+	e.Points, e.PointsMax = 100, 1000
+
+	started := domain.EventMessage{Kind: domain.EventKindDefend, Transition: domain.EventTransitionStarted, DefendEvent: e}
+	if err := n.send(api, n.routes[0], started); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	th, ok := api.threads["msg-events"]
+	if !ok || th.Name == "" || th.AutoArchiveDuration != threadAutoArchive {
+		t.Fatalf("expected a thread on the start message, got %+v", api.threads)
+	}
+	key := threadKey("events", domain.EventKindDefend, e.ID)
+	if id, _ := refs.GetMessageRef(key); id != "thread-events" {
+		t.Errorf("expected the thread ID to be stored, got %q", id)
+	}
+
+	// Progress within the same step is not posted again.
+	n.postProgress(api, &domain.CampaignStatus{DefendEvent: e})
+	if got := len(api.sent["thread-events"]); got != 0 {
+		t.Errorf("expected no progress update yet, got %d", got)
+	}
+	e.Points = 420
+	n.postProgress(api, &domain.CampaignStatus{DefendEvent: e})
+	updates := api.sent["thread-events"]
+	if len(updates) != 1 || updates[0].Content != "📈 **Progress:** 42% (420 / 1,000)" {
+		t.Fatalf("expected one progress update, got %+v", updates)
+	}
+
+	// After a restart the thread is found in the store.
+	n = newTestNotifier(t, withThreads(refs))
+	done := *e
+	done.Status = domain.EventStatusSuccess
+	succeeded := domain.EventMessage{Kind: domain.EventKindDefend, Transition: domain.EventTransitionSucceeded, DefendEvent: &done}
+	if err := n.send(api, n.routes[0], succeeded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := len(api.sent["events"]); got != 1 {
+		t.Errorf("expected the outcome to stay out of the channel, got %d messages", got)
+	}
+	if got := api.sent["thread-events"]; len(got) != 2 || !strings.Contains(got[1].Content, "defended") {
+		t.Errorf("expected the outcome in the thread, got %+v", got)
+	}
+	if len(api.archived) != 1 || api.archived[0] != "thread-events" {
+		t.Errorf("expected the thread to be archived, got %v", api.archived)
+	}
+	if id, _ := refs.GetMessageRef(key); id != "" {
+		t.Errorf("expected the thread ID to be cleared, got %q", id)
+	}
+}
+
+func TestThreads_OutcomeWithoutThreadGoesToChannel(t *testing.T) {
+	n := newTestNotifier(t, withThreads(memory.New()))
+	api := &fakeChannelAPI{}
+	e := testutil.AttackEventFailed()
+	failed := domain.EventMessage{Kind: domain.EventKindAttack, Transition: domain.EventTransitionFailed, AttackEvent: &e}
+	if err := n.send(api, n.routes[0], failed); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := len(api.sent["events"]); got != 1 {
+		t.Errorf("expected the outcome in the channel, got %d messages", got)
+	}
+	if len(api.threads) != 0 || len(api.archived) != 0 {
+		t.Errorf("expected no thread activity, got %+v %v", api.threads, api.archived)
+	}
+}
+
+func TestThreads_Disabled(t *testing.T) {
+	n := newTestNotifier(t, withThreads(memory.New()))
+	n.opts.Threads = false
+	api := &fakeChannelAPI{}
+	started := domain.EventMessage{Kind: domain.EventKindDefend, Transition: domain.EventTransitionStarted, DefendEvent: testutil.DefendEventActive()}
+	if err := n.send(api, n.routes[0], started); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(api.sent["events"]) != 1 || len(api.threads) != 0 {
+		t.Errorf("expected a plain channel message, got %+v %+v", api.sent, api.threads)
+	}
+}
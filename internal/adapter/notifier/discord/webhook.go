@@ -0,0 +1,93 @@
+package discord
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/ametis70/hellbot/internal/domain"
+)
+
+// WebhookProfile is the name and avatar a webhook message is posted as.
+// Empty fields keep the webhook's own settings.
+type WebhookProfile struct {
+	Username  string
+	AvatarURL string
+}
+
+// webhookClient posts messages through a Discord incoming webhook.
+type webhookClient struct {
+	url    string
+	client *http.Client
+}
+
+// newWebhook creates a notifier that posts through opts.WebhookURL without a
+// bot token or gateway connection.
+func newWebhook(opts Options, n *DiscordNotifier) (*DiscordNotifier, error) {
+	if len(opts.Routes) > 0 || opts.BoardChannelID != "" || opts.Subscriptions != nil || opts.ScheduledEvents || opts.Threads || opts.Presence != nil {
+		return nil, fmt.Errorf("discord notifier: routes, board, subscriptions, scheduled events, threads and presence need a bot token, not a webhook")
+	}
+	n.webhook = &webhookClient{url: opts.WebhookURL, client: &http.Client{Timeout: 10 * time.Second}}
+	n.routes = []route{n.resolveRoute(Route{})}
+	return n, nil
+}
+
+// profileFor returns the profile for msg: the faction's when configured,
+// otherwise the default.
+func (n *DiscordNotifier) profileFor(msg domain.EventMessage) WebhookProfile {
+	p := n.opts.WebhookProfile
+	if e, ok := msg.Enemy(); ok {
+		if fp, ok := n.opts.FactionProfiles[e]; ok {
+			if fp.Username != "" {
+				p.Username = fp.Username
+			}
+			if fp.AvatarURL != "" {
+				p.AvatarURL = fp.AvatarURL
+			}
+		}
+	}
+	return p
+}
+
+// executeWebhook renders msg like a channel message and posts it to the
+// webhook as the matching profile.
+func (n *DiscordNotifier) executeWebhook(msg domain.EventMessage) error {
+	m, err := n.message(n.routes[0], msg, n.opts.Mentions)
+	if err != nil {
+		return fmt.Errorf("discord notifier: rendering message: %w", err)
+	}
+	profile := n.profileFor(msg)
+	body, err := json.Marshal(&discordgo.WebhookParams{
+		Content:         m.Content,
+		Embeds:          m.Embeds,
+		AllowedMentions: m.AllowedMentions,
+		Username:        profile.Username,
+		AvatarURL:       profile.AvatarURL,
+	})
+	if err != nil {
+		return fmt.Errorf("discord notifier: marshal webhook payload: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, n.webhook.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("discord notifier: create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.webhook.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("discord notifier: executing webhook: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("discord notifier: webhook returned %d: %s", resp.StatusCode, bytes.TrimSpace(detail))
+	}
+	return nil
+}
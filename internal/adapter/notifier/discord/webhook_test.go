@@ -0,0 +1,122 @@
+package discord_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/ametis70/hellbot/internal/adapter/notifier/discord"
+	"github.com/ametis70/hellbot/internal/adapter/store/memory"
+	"github.com/ametis70/hellbot/internal/domain"
+	"github.com/ametis70/hellbot/internal/testutil"
+)
+
+// webhookServer records the last webhook payload and replies with status.
+func webhookServer(t *testing.T, status int) (*httptest.Server, *discordgo.WebhookParams) {
+	t.Helper()
+	got := &discordgo.WebhookParams{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("unexpected request %s %s", r.Method, r.Header.Get("Content-Type"))
+		}
+		if err := json.NewDecoder(r.Body).Decode(got); err != nil {
+			t.Errorf("decoding payload: %v", err)
+		}
+		w.WriteHeader(status)
+		if status >= 300 {
+			_, _ = w.Write([]byte(`{"message": "Unknown Webhook", "code": 10015}`))
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, got
+}
+
+func TestWebhook_PostsTemplateMessage(t *testing.T) {
+	srv, got := webhookServer(t, http.StatusNoContent)
+	n, err := discord.New(discord.Options{
+		WebhookURL:     srv.URL,
+		WebhookProfile: discord.WebhookProfile{Username: "Super Earth Command", AvatarURL: "https://example.com/se.png"},
+		FactionProfiles: map[domain.Enemy]discord.WebhookProfile{
+			domain.EnemyIlluminate: {Username: "Illuminate Front"},
+		},
+		Mentions: []discord.Mention{{SuperEarth: true, Everyone: true}},
+	}, testutil.DiscardLogger())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer func() { _ = n.Close() }()
+
+	msg := domain.EventMessage{Kind: domain.EventKindDefend, Transition: domain.EventTransitionStarted, DefendEvent: testutil.DefendEventActive()}
+	if err := n.Notify(msg); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	if !strings.HasPrefix(got.Content, "@everyone\n") || !strings.Contains(got.Content, "Super Earth") {
+		t.Errorf("unexpected content %q", got.Content)
+	}
+	if got.Username != "Illuminate Front" || got.AvatarURL != "https://example.com/se.png" {
+		t.Errorf("expected faction username over default avatar, got %q %q", got.Username, got.AvatarURL)
+	}
+	if got.AllowedMentions == nil || len(got.AllowedMentions.Parse) != 1 {
+		t.Errorf("expected allowed mentions for @everyone, got %+v", got.AllowedMentions)
+	}
+}
+
+func TestWebhook_PostsEmbed(t *testing.T) {
+	srv, got := webhookServer(t, http.StatusOK)
+	n, err := discord.New(discord.Options{
+		WebhookURL:     srv.URL,
+		WebhookProfile: discord.WebhookProfile{Username: "Super Earth Command"},
+		Style:          domain.MessageStyleStructured,
+	}, testutil.DiscardLogger())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := n.Notify(domain.EventMessage{Kind: domain.EventKindWar, Transition: domain.EventTransitionSucceeded, WarEvent: &domain.WarEvent{Season: 159}}); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	if len(got.Embeds) != 1 || got.Embeds[0].Title == "" || got.Content != "" {
+		t.Errorf("expected a single embed, got %+v", got)
+	}
+	if got.Username != "Super Earth Command" {
+		t.Errorf("expected default username, got %q", got.Username)
+	}
+}
+
+func TestWebhook_ErrorStatus(t *testing.T) {
+	srv, _ := webhookServer(t, http.StatusNotFound)
+	n, err := discord.New(discord.Options{WebhookURL: srv.URL}, testutil.DiscardLogger())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = n.Notify(domain.EventMessage{Kind: domain.EventKindWar, Transition: domain.EventTransitionSucceeded, WarEvent: &domain.WarEvent{Season: 159}})
+	if err == nil || !strings.Contains(err.Error(), "404") || !strings.Contains(err.Error(), "Unknown Webhook") {
+		t.Errorf("expected 404 error with details, got %v", err)
+	}
+}
+
+func TestWebhook_RejectsBotOnlyFeatures(t *testing.T) {
+	cases := []discord.Options{
+		{WebhookURL: "http://example.invalid", Routes: []discord.Route{{ChannelID: "1"}}},
+		{WebhookURL: "http://example.invalid", BoardChannelID: "1"},
+		{WebhookURL: "http://example.invalid", Subscriptions: memory.New()},
+	}
+	for _, opts := range cases {
+		if _, err := discord.New(opts, testutil.DiscardLogger()); err == nil {
+			t.Errorf("expected error for %+v", opts)
+		}
+	}
+}
+
+func TestWebhook_RegisterCommandsIsNoop(t *testing.T) {
+	n, err := discord.New(discord.Options{WebhookURL: "http://example.invalid"}, testutil.DiscardLogger())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	n.RegisterCommands(memory.New()) // must not panic without a session
+	n.ObserveCampaign(testutil.CampaignWithNoDefend())
+}
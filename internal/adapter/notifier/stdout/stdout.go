@@ -13,7 +13,10 @@ func formatTime(t time.Time, loc *time.Location) string {
 
 // Options holds configuration for the stdout notifier.
 type Options struct {
-	Timezone  *time.Location
+	Timezone *time.Location
+	Locale   domain.Locale
+	// Style selects plain templates (default) or structured messages.
+	Style     domain.MessageStyle
 	Templates *domain.Templates
 }
 
@@ -21,6 +24,7 @@ type Options struct {
 type StdoutNotifier struct {
 	opts      Options
 	templates domain.Templates
+	catalog   *domain.Catalog
 }
 
 // New creates a new StdoutNotifier.
@@ -29,7 +33,11 @@ func New(opts Options) *StdoutNotifier {
 		opts.Timezone = time.UTC
 	}
 
-	templates := DefaultTemplates()
+	catalog := domain.CatalogFor(opts.Locale)
+	templates := LocalizedTemplates(opts.Locale)
+	if opts.Style == domain.MessageStyleStructured {
+		templates = catalog.Titles
+	}
 	if opts.Templates != nil {
 		templates = domain.MergeTemplates(templates, *opts.Templates)
 	}
@@ -37,12 +45,13 @@ func New(opts Options) *StdoutNotifier {
 	return &StdoutNotifier{
 		opts:      opts,
 		templates: templates,
+		catalog:   catalog,
 	}
 }
 
 // Notify prints a formatted event message to stdout.
 func (n *StdoutNotifier) Notify(msg domain.EventMessage) error {
-	text, err := domain.RenderEvent(n.templates, msg, TimeFormatter(n.opts.Timezone))
+	text, err := n.format(msg)
 	if err != nil {
 		return err
 	}
@@ -50,6 +59,20 @@ func (n *StdoutNotifier) Notify(msg domain.EventMessage) error {
 	return nil
 }
 
+// format renders msg with the templates, or as a structured message laid out
+// as plain text.
+func (n *StdoutNotifier) format(msg domain.EventMessage) (string, error) {
+	tf := TimeFormatter(n.opts.Timezone)
+	if n.opts.Style != domain.MessageStyleStructured {
+		return n.catalog.RenderEvent(n.templates, msg, tf)
+	}
+	m, err := n.catalog.BuildMessage(n.templates, msg, tf)
+	if err != nil {
+		return "", err
+	}
+	return m.Format(domain.PlainMarkup(tf)), nil
+}
+
 // formatMessage is kept for tests — delegates to domain.RenderEvent with default templates.
 func formatMessage(msg domain.EventMessage, loc *time.Location) (string, error) {
 	return domain.RenderEvent(DefaultTemplates(), msg, TimeFormatter(loc))
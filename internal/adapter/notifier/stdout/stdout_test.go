@@ -136,3 +136,61 @@ func TestNew_MergesTemplates(t *testing.T) {
 		t.Errorf("expected custom template to be merged, got %q", n.templates.AttackSucceeded)
 	}
 }
+
+func TestDefaultTemplates_Valid(t *testing.T) {
+	if problems := domain.ValidateTemplates(DefaultTemplates()); len(problems) != 0 {
+		t.Errorf("default templates have problems: %v", problems)
+	}
+}
+
+func TestLocalizedTemplates_Spanish(t *testing.T) {
+	es := LocalizedTemplates(domain.LocaleSpanish)
+	if problems := domain.ValidateTemplates(es); len(problems) != 0 {
+		t.Errorf("spanish templates have problems: %v", problems)
+	}
+	if es == DefaultTemplates() {
+		t.Error("expected spanish templates to differ from english")
+	}
+	if LocalizedTemplates("xx") != DefaultTemplates() {
+		t.Error("expected unknown locale to fall back to english")
+	}
+}
+
+func TestNew_SpanishLocale(t *testing.T) {
+	n := New(Options{Locale: domain.LocaleSpanish})
+	got, err := n.catalog.RenderEvent(n.templates, defendMsg(domain.EventTransitionStarted), TimeFormatter(time.UTC))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(got, "[defensa] iniciada — los Iluminados") {
+		t.Errorf("expected spanish message, got %q", got)
+	}
+}
+
+func TestNew_StructuredStyle(t *testing.T) {
+	n := New(Options{Style: domain.MessageStyleStructured})
+	got, err := n.format(attackMsg(domain.EventTransitionStarted))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(got, "🚀 An attack against the") {
+		t.Errorf("expected structured title, got %q", got)
+	}
+	if !strings.Contains(got, "\nEnds: ") || !strings.HasSuffix(got, "War 159") {
+		t.Errorf("expected fields and footer, got %q", got)
+	}
+}
+
+func TestNew_StructuredStyle_TemplateOverride(t *testing.T) {
+	n := New(Options{
+		Style:     domain.MessageStyleStructured,
+		Templates: &domain.Templates{AttackHomeworldStarted: "Attack on {FACTION}"},
+	})
+	got, err := n.format(attackMsg(domain.EventTransitionStarted))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(got, "Attack on ") {
+		t.Errorf("expected overridden title, got %q", got)
+	}
+}
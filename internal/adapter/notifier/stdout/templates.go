@@ -21,6 +21,41 @@ func DefaultTemplates() domain.Templates {
 		AttackFailed:              "[attack] failed — {FACTION} defended homeworld",
 		WarWon:                    "[war] won — Managed Democracy prevails! All enemies crushed, freedom spreads (war {SEASON})",
 		WarLost:                   "[war] lost — Super Earth has fallen (war {SEASON})",
+		PlayersAboveThreshold:     "[players] rose — {PLAYERS} helldivers online, above {THRESHOLD}",
+		PlayersBelowThreshold:     "[players] fell — {PLAYERS} helldivers online, below {THRESHOLD}",
+		PlayersSurge:              "[players] surge — {PLAYERS} helldivers online, up {CHANGE_PERCENT}% in {WINDOW}",
+		PlayersDrop:               "[players] drop — {PLAYERS} helldivers online, down {CHANGE_PERCENT}% in {WINDOW}",
+		MilestoneReached:          "[milestone] reached — {MILESTONE} {COUNTER} in war {SEASON}",
+	}
+}
+
+// LocalizedTemplates returns the default templates for the locale, falling
+// back to English when there is no translation.
+func LocalizedTemplates(locale domain.Locale) domain.Templates {
+	if locale == domain.LocaleSpanish {
+		return spanishTemplates()
+	}
+	return DefaultTemplates()
+}
+
+func spanishTemplates() domain.Templates {
+	return domain.Templates{
+		DefendRegionStarted:       "[defensa] iniciada — los {FACTION} atacan {REGION_NAME} ({REGION_NUMBER}/{TOTAL_REGIONS}), termina {END_TIME_FORMATTED}",
+		DefendSuperEarthStarted:   "[defensa] iniciada — los {FACTION} atacan la Súper Tierra, termina {END_TIME_FORMATTED}",
+		DefendRegionSucceeded:     "[defensa] exitosa — {REGION_NAME} ({REGION_NUMBER}/{TOTAL_REGIONS}) resistió a los {FACTION}",
+		DefendSuperEarthSucceeded: "[defensa] exitosa — la Súper Tierra resistió a los {FACTION}",
+		DefendRegionFailed:        "[defensa] fallida — {REGION_NAME} ({REGION_NUMBER}/{TOTAL_REGIONS}) cayó ante los {FACTION}",
+		DefendSuperEarthFailed:    "[defensa] fallida — la Súper Tierra cayó ante los {FACTION}",
+		AttackHomeworldStarted:    "[ataque] iniciado — contra el planeta natal de los {FACTION}, termina {END_TIME_FORMATTED}",
+		AttackSucceeded:           "[ataque] exitoso — los {FACTION} fueron derrotados",
+		AttackFailed:              "[ataque] fallido — los {FACTION} defendieron su planeta natal",
+		WarWon:                    "[guerra] ganada — ¡la Democracia Gestionada prevalece! Todos los enemigos aplastados, la libertad se extiende (guerra {SEASON})",
+		WarLost:                   "[guerra] perdida — la Súper Tierra ha caído (guerra {SEASON})",
+		PlayersAboveThreshold:     "[jugadores] subida — {PLAYERS} helldivers en línea, más de {THRESHOLD}",
+		PlayersBelowThreshold:     "[jugadores] bajada — {PLAYERS} helldivers en línea, menos de {THRESHOLD}",
+		PlayersSurge:              "[jugadores] aumento — {PLAYERS} helldivers en línea, {CHANGE_PERCENT}% más en {WINDOW}",
+		PlayersDrop:               "[jugadores] caída — {PLAYERS} helldivers en línea, {CHANGE_PERCENT}% menos en {WINDOW}",
+		MilestoneReached:          "[hito] alcanzado — {MILESTONE} {COUNTER} en la guerra {SEASON}",
 	}
 }
 
@@ -0,0 +1,42 @@
+package telegram
+
+import (
+	"slices"
+	"strconv"
+)
+
+// allowed reports whether commands from chatID or userID may run. The
+// configured chat is always allowed.
+func (n *Notifier) allowed(chatID string, userID int64) bool {
+	if len(n.opts.AllowedChats) == 0 && len(n.opts.AllowedUsers) == 0 {
+		return true
+	}
+	if chatID == n.opts.ChatID || slices.Contains(n.opts.AllowedChats, chatID) {
+		return true
+	}
+	return userID != 0 && slices.Contains(n.opts.AllowedUsers, strconv.FormatInt(userID, 10))
+}
+
+// isChatAdmin reports whether the sender of r administers its chat. In a
+// private chat the user is its only member; anonymous admins post as the
+// group itself. Otherwise the sender's status is looked up with
+// getChatMember.
+func (n *Notifier) isChatAdmin(r commandRequest) (bool, error) {
+	if r.chatType == "private" || r.anonymousAdmin {
+		return true, nil
+	}
+	if r.userID == 0 {
+		return false, nil
+	}
+	type params struct {
+		ChatID string `json:"chat_id"`
+		UserID int64  `json:"user_id"`
+	}
+	var member struct {
+		Status string `json:"status"`
+	}
+	if err := n.callResult("getChatMember", params{ChatID: r.chatID, UserID: r.userID}, &member); err != nil {
+		return false, err
+	}
+	return member.Status == "creator" || member.Status == "administrator", nil
+}
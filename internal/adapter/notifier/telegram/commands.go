@@ -0,0 +1,178 @@
+package telegram
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ametis70/hellbot/internal/domain"
+)
+
+// Command menu scopes, as Telegram BotCommandScope types. A chat uses the
+// most specific scope with a command list, so every list is complete on its
+// own: group members see the commands anyone may run, while private chats
+// and group administrators see them all.
+const (
+	scopeDefault = "default"
+	scopePrivate = "all_private_chats"
+	scopeAdmins  = "all_chat_administrators"
+)
+
+// localized is a command description in English, shown to users without a
+// dedicated list, and its Spanish translation.
+type localized struct {
+	en, es string
+}
+
+// in returns the translation for locale.
+func (t localized) in(locale domain.Locale) string {
+	if locale == domain.LocaleSpanish {
+		return t.es
+	}
+	return t.en
+}
+
+// commandRequest is a command received in a chat, with the text after it.
+type commandRequest struct {
+	chatID   string
+	chatType string
+	// threadID is the forum topic the command was sent in, or 0.
+	threadID int
+	// userID is the sender, or 0 when unknown.
+	userID int64
+	// anonymousAdmin is set when a group administrator posted as the group.
+	anonymousAdmin bool
+	arg            string
+}
+
+// botCommand is a command the bot handles and lists in Telegram's menu.
+type botCommand struct {
+	name        string
+	description localized
+	// admin restricts the command to chat administrators in groups.
+	admin  bool
+	handle func(n *Notifier, r commandRequest)
+}
+
+// commands returns the commands this notifier handles, in menu order. It is
+// the single list used by the dispatcher, setMyCommands and /help.
+func (n *Notifier) commands() []botCommand {
+	cmds := []botCommand{
+		{
+			name:        "status",
+			description: localized{"War progress per faction, optionally for one faction", "Progreso de la guerra por facción, opcionalmente de una facción"},
+			handle:      (*Notifier).handleStatusCommand,
+		},
+		{
+			name:        "statistics",
+			description: localized{"Cumulative war statistics", "Estadísticas acumuladas de la guerra"},
+			handle:      (*Notifier).handleStatisticsCommand,
+		},
+	}
+	if n.opts.Subscriptions != nil {
+		cmds = append(cmds,
+			botCommand{
+				name:        "subscribe",
+				description: localized{"Receive events in this chat, optionally by faction and event", "Recibe eventos en este chat, opcionalmente por facción y evento"},
+				admin:       true,
+				handle:      (*Notifier).handleSubscribeCommand,
+			},
+			botCommand{
+				name:        "unsubscribe",
+				description: localized{"Stop receiving events in this chat", "Deja de recibir eventos en este chat"},
+				admin:       true,
+				handle:      (*Notifier).handleUnsubscribeCommand,
+			},
+		)
+	}
+	return append(cmds,
+		botCommand{
+			name:        "test",
+			description: localized{"Check that the bot can post to this chat", "Comprueba que el bot puede publicar en este chat"},
+			admin:       true,
+			handle:      (*Notifier).handleTestCommand,
+		},
+		botCommand{
+			name:        "help",
+			description: localized{"List the available commands", "Lista los comandos disponibles"},
+			handle:      (*Notifier).handleHelpCommand,
+		},
+	)
+}
+
+// dispatch runs the command named name, if any, when the chat or user is
+// allowed and, for admin commands, the sender administers the chat.
+func (n *Notifier) dispatch(name string, r commandRequest) {
+	cmds := n.commands()
+	i := slices.IndexFunc(cmds, func(c botCommand) bool { return c.name == name })
+	if i < 0 {
+		return
+	}
+	c := cmds[i]
+
+	if !n.allowed(r.chatID, r.userID) {
+		n.logger.Info("telegram notifier: ignoring command from a chat that is not allowed", "command", name, "chat", r.chatID, "user", r.userID)
+		return
+	}
+	if c.admin {
+		admin, err := n.isChatAdmin(r)
+		if err != nil {
+			n.logger.Error("telegram notifier: checking chat administrator failed", "chat", r.chatID, "user", r.userID, "error", err)
+			n.reply(r, escape(n.catalog.PermissionsFailed))
+			return
+		}
+		if !admin {
+			n.logger.Info("telegram notifier: admin command denied", "command", name, "chat", r.chatID, "user", r.userID)
+			n.reply(r, escape(fmt.Sprintf(n.catalog.AdminOnly, name)))
+			return
+		}
+	}
+	c.handle(n, r)
+}
+
+// setMyCommands publishes the command menu for every scope, in English by
+// default and in Spanish for Spanish-speaking users.
+func (n *Notifier) setMyCommands() error {
+	type command struct {
+		Command     string `json:"command"`
+		Description string `json:"description"`
+	}
+	type scope struct {
+		Type string `json:"type"`
+	}
+	type params struct {
+		Commands     []command `json:"commands"`
+		Scope        scope     `json:"scope"`
+		LanguageCode string    `json:"language_code,omitempty"`
+	}
+
+	cmds := n.commands()
+	for _, s := range []string{scopeDefault, scopePrivate, scopeAdmins} {
+		for _, lang := range []domain.Locale{"", domain.LocaleSpanish} {
+			p := params{Scope: scope{Type: s}, LanguageCode: string(lang)}
+			for _, c := range cmds {
+				if s != scopeDefault || !c.admin {
+					p.Commands = append(p.Commands, command{Command: c.name, Description: c.description.in(lang)})
+				}
+			}
+			if err := n.call("setMyCommands", p); err != nil {
+				return fmt.Errorf("scope %s, language %q: %w", s, lang, err)
+			}
+		}
+	}
+	return nil
+}
+
+// handleHelpCommand replies with every command and its description in the
+// notifier's locale.
+func (n *Notifier) handleHelpCommand(r commandRequest) {
+	var sb strings.Builder
+	sb.WriteString("*" + escape(n.catalog.HelpTitle) + "*\n")
+	for _, c := range n.commands() {
+		fmt.Fprintf(&sb, "\n/%s — %s", escape(c.name), escape(c.description.in(n.opts.Locale)))
+		if c.admin {
+			sb.WriteString(" _\\(" + escape(n.catalog.HelpAdminOnly) + "\\)_")
+		}
+	}
+	n.reply(r, sb.String())
+}
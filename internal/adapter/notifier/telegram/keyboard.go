@@ -0,0 +1,120 @@
+package telegram
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ametis70/hellbot/internal/domain"
+)
+
+// Callback data of the status keyboard. A faction button sends
+// "status:<faction>", e.g. "status:bugs".
+const (
+	callbackStatus     = "status"
+	callbackStatistics = "statistics"
+)
+
+// keyboardFactions are the faction buttons, in order, and their callback
+// values as accepted by domain.ParseEnemy.
+var keyboardFactions = []struct {
+	enemy domain.Enemy
+	value string
+}{
+	{domain.EnemyBug, "bugs"},
+	{domain.EnemyCyborg, "cyborgs"},
+	{domain.EnemyIlluminate, "illuminate"},
+}
+
+// inlineKeyboard is a Telegram InlineKeyboardMarkup.
+type inlineKeyboard struct {
+	InlineKeyboard [][]inlineButton `json:"inline_keyboard"`
+}
+
+// inlineButton is a Telegram InlineKeyboardButton that sends callback data.
+type inlineButton struct {
+	Text         string `json:"text"`
+	CallbackData string `json:"callback_data"`
+}
+
+// statusKeyboard returns the keyboard attached to /status: all factions, one
+// button per faction, and statistics.
+func (n *Notifier) statusKeyboard() *inlineKeyboard {
+	factions := []inlineButton{{Text: n.catalog.ButtonAll, CallbackData: callbackStatus}}
+	for _, f := range keyboardFactions {
+		factions = append(factions, inlineButton{Text: n.catalog.FactionName(f.enemy), CallbackData: callbackStatus + ":" + f.value})
+	}
+	return &inlineKeyboard{InlineKeyboard: [][]inlineButton{
+		factions,
+		{{Text: n.catalog.ButtonStatistics, CallbackData: callbackStatistics}},
+	}}
+}
+
+// callbackText renders the view selected by a keyboard button.
+func (n *Notifier) callbackText(data string) (string, error) {
+	if data == callbackStatistics {
+		return n.statisticsText()
+	}
+	if data == callbackStatus {
+		return n.statusText(nil)
+	}
+	if value, ok := strings.CutPrefix(data, callbackStatus+":"); ok {
+		if enemy, ok := domain.ParseEnemy(value); ok {
+			return n.statusText(&enemy)
+		}
+	}
+	return "", fmt.Errorf("unknown callback data %q", data)
+}
+
+// handleCallbackQuery edits the message of a pressed status keyboard button
+// in place and keeps the keyboard. The query is always answered so the
+// client stops showing a spinner.
+func (n *Notifier) handleCallbackQuery(q *callbackQuery) {
+	defer func() {
+		if err := n.call("answerCallbackQuery", map[string]string{"callback_query_id": q.ID}); err != nil {
+			n.logger.Error("telegram notifier: answering callback query failed", "error", err)
+		}
+	}()
+
+	if q.Message == nil {
+		return
+	}
+	if !n.allowed(strconv.FormatInt(q.Message.Chat.ID, 10), q.From.ID) {
+		n.logger.Info("telegram notifier: ignoring button from a chat that is not allowed", "chat", q.Message.Chat.ID, "user", q.From.ID)
+		return
+	}
+	if n.provider == nil {
+		n.logger.Warn("telegram notifier: callback query received but no status provider registered")
+		return
+	}
+
+	text, err := n.callbackText(q.Data)
+	if err != nil {
+		n.logger.Error("telegram notifier: callback query failed", "data", q.Data, "error", err)
+		return
+	}
+
+	type payload struct {
+		ChatID      int64           `json:"chat_id"`
+		MessageID   int             `json:"message_id"`
+		Text        string          `json:"text"`
+		ParseMode   string          `json:"parse_mode"`
+		ReplyMarkup *inlineKeyboard `json:"reply_markup"`
+	}
+	err = n.call("editMessageText", payload{
+		ChatID:      q.Message.Chat.ID,
+		MessageID:   q.Message.MessageID,
+		Text:        text,
+		ParseMode:   "MarkdownV2",
+		ReplyMarkup: n.statusKeyboard(),
+	})
+	// Pressing the button of the view already shown changes nothing.
+	var apiErr *apiError
+	if errors.As(err, &apiErr) && strings.Contains(apiErr.Description, "message is not modified") {
+		return
+	}
+	if err != nil {
+		n.logger.Error("telegram notifier: editing status message failed", "error", err)
+	}
+}
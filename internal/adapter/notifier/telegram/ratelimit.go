@@ -0,0 +1,55 @@
+package telegram
+
+import (
+	"sync"
+	"time"
+)
+
+// Telegram's documented limits: about one message per second in a chat and
+// 30 messages per second across all chats. Groups are further limited to 20
+// messages per minute, which is left to Telegram's retry_after.
+const (
+	perChatInterval = time.Second
+	globalInterval  = time.Second / 30
+)
+
+// limiter spaces out messages to stay within Telegram's rate limits. Each
+// send reserves the next free slot for its chat and globally, then waits for
+// it, so concurrent senders queue up in order.
+type limiter struct {
+	mu       sync.Mutex
+	next     time.Time
+	nextChat map[string]time.Time
+	now      func() time.Time
+	sleep    func(time.Duration)
+}
+
+func newLimiter() *limiter {
+	return &limiter{nextChat: map[string]time.Time{}, now: time.Now, sleep: time.Sleep}
+}
+
+// wait blocks until a message may be sent to chatID.
+func (l *limiter) wait(chatID string) {
+	l.mu.Lock()
+	now := l.now()
+	at := now
+	if l.next.After(at) {
+		at = l.next
+	}
+	if t := l.nextChat[chatID]; t.After(at) {
+		at = t
+	}
+	l.next = at.Add(globalInterval)
+	l.nextChat[chatID] = at.Add(perChatInterval)
+	// Forget chats whose slot has passed so the map does not grow forever.
+	for id, t := range l.nextChat {
+		if !t.After(now) {
+			delete(l.nextChat, id)
+		}
+	}
+	l.mu.Unlock()
+
+	if d := at.Sub(now); d > 0 {
+		l.sleep(d)
+	}
+}
@@ -0,0 +1,51 @@
+package telegram
+
+import (
+	"testing"
+	"time"
+)
+
+// fakeClock advances when slept on.
+type fakeClock struct {
+	now   time.Time
+	slept []time.Duration
+}
+
+func (c *fakeClock) sleep(d time.Duration) {
+	c.slept = append(c.slept, d)
+	c.now = c.now.Add(d)
+}
+
+func newFakeLimiter() (*limiter, *fakeClock) {
+	c := &fakeClock{now: time.Unix(0, 0)}
+	l := newLimiter()
+	l.now = func() time.Time { return c.now }
+	l.sleep = c.sleep
+	return l, c
+}
+
+func TestLimiter_PerChat(t *testing.T) {
+	l, c := newFakeLimiter()
+	l.wait("a")
+	l.wait("a")
+	if len(c.slept) != 1 || c.slept[0] != perChatInterval {
+		t.Errorf("expected one wait of %s for the same chat, got %v", perChatInterval, c.slept)
+	}
+}
+
+func TestLimiter_Global(t *testing.T) {
+	l, c := newFakeLimiter()
+	for _, chat := range []string{"a", "b", "c"} {
+		l.wait(chat)
+	}
+	if len(c.slept) != 2 || c.slept[0] != globalInterval || c.slept[1] != globalInterval {
+		t.Errorf("expected two global waits of %s, got %v", globalInterval, c.slept)
+	}
+	// After a pause nothing waits and old chats are forgotten.
+	c.now = c.now.Add(time.Minute)
+	c.slept = nil
+	l.wait("a")
+	if len(c.slept) != 0 || len(l.nextChat) != 1 {
+		t.Errorf("expected no wait and one tracked chat, got %v %v", c.slept, l.nextChat)
+	}
+}
@@ -0,0 +1,105 @@
+package telegram
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/ametis70/hellbot/internal/domain"
+)
+
+// subscriptionScope namespaces the chats subscribed to this notifier in the
+// subscription store.
+func (n *Notifier) subscriptionScope() string {
+	return "telegram:" + n.opts.ID
+}
+
+// parseSubscription builds chatID's subscription from /subscribe arguments,
+// each a faction or an event kind, e.g. "bugs attack". ok is false when a
+// word is neither, and unknown is that word.
+func parseSubscription(chatID, arg string) (sub domain.Subscription, unknown string, ok bool) {
+	sub = domain.Subscription{ID: chatID}
+	for _, word := range strings.Fields(strings.ToLower(arg)) {
+		if e, ok := domain.ParseEnemy(word); ok {
+			sub.Factions = append(sub.Factions, e)
+			continue
+		}
+		if k := domain.EventKind(word); k.Valid() {
+			sub.Kinds = append(sub.Kinds, k)
+			continue
+		}
+		return sub, word, false
+	}
+	return sub, "", true
+}
+
+// handleSubscribeCommand responds to /subscribe [faction] [event] by storing
+// the chat's subscription, replacing any previous one.
+func (n *Notifier) handleSubscribeCommand(r commandRequest) {
+	sub, unknown, ok := parseSubscription(r.chatID, r.arg)
+	sub.ThreadID = r.threadID
+	if !ok {
+		n.reply(r, escape(fmt.Sprintf(n.catalog.SubscribeUnknown, unknown)+" "+n.catalog.SubscribeUsage)+
+			" `/subscribe [bugs|cyborgs|illuminate] [defend|attack|war|players|milestone]`")
+		return
+	}
+	if err := n.opts.Subscriptions.SaveSubscription(n.subscriptionScope(), sub); err != nil {
+		n.logger.Error("telegram notifier: saving subscription failed", "chat", r.chatID, "error", err)
+		n.reply(r, escape(n.catalog.SubscribeFailed))
+		return
+	}
+	n.logger.Info("telegram notifier: chat subscribed", "chat", r.chatID, "factions", sub.Factions, "kinds", sub.Kinds)
+	n.reply(r, escape(n.catalog.SubscribedMessage(sub)))
+}
+
+// handleUnsubscribeCommand responds to /unsubscribe.
+func (n *Notifier) handleUnsubscribeCommand(r commandRequest) {
+	if err := n.opts.Subscriptions.RemoveSubscription(n.subscriptionScope(), r.chatID); err != nil {
+		n.logger.Error("telegram notifier: removing subscription failed", "chat", r.chatID, "error", err)
+		n.reply(r, escape(n.catalog.UnsubscribeFailed))
+		return
+	}
+	n.logger.Info("telegram notifier: chat unsubscribed", "chat", r.chatID)
+	n.reply(r, escape(n.catalog.Unsubscribed))
+}
+
+// reply sends text to the chat and topic of r, logging failures.
+func (n *Notifier) reply(r commandRequest, text string) {
+	if err := n.sendTo(r.chatID, r.threadID, text, nil); err != nil {
+		n.logger.Error("telegram notifier: reply failed", "chat", r.chatID, "error", err)
+	}
+}
+
+// notifySubscribers sends text to every subscribed chat whose filters match
+// msg, other than the configured chat, which already has it. Chats that
+// blocked the bot or removed it are unsubscribed; other failures are logged
+// and do not stop the rest.
+func (n *Notifier) notifySubscribers(msg domain.EventMessage, text string) error {
+	if n.opts.Subscriptions == nil {
+		return nil
+	}
+	subs, err := n.opts.Subscriptions.ListSubscriptions(n.subscriptionScope())
+	if err != nil {
+		return fmt.Errorf("telegram notifier: listing subscriptions: %w", err)
+	}
+
+	for _, sub := range subs {
+		if sub.ID == n.opts.ChatID || !sub.Matches(msg) {
+			continue
+		}
+		err := n.sendTo(sub.ID, sub.ThreadID, text, nil)
+		var apiErr *apiError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusForbidden {
+			n.logger.Warn("telegram notifier: chat unreachable, unsubscribing", "chat", sub.ID, "error", err)
+			if err := n.opts.Subscriptions.RemoveSubscription(n.subscriptionScope(), sub.ID); err != nil {
+				n.logger.Error("telegram notifier: removing subscription failed", "chat", sub.ID, "error", err)
+			}
+			continue
+		}
+		if err != nil {
+			n.logger.Warn("telegram notifier: failed to notify subscriber", "chat", sub.ID, "error", err)
+		}
+	}
+	return nil
+}
@@ -4,9 +4,11 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -18,42 +20,107 @@ const apiBase = "https://api.telegram.org"
 
 // Options holds configuration for a Telegram notifier instance.
 type Options struct {
-	Token     string
-	ChatID    string
-	Timezone  *time.Location
+	// ID is the notifier's ID from the config. Chats subscribe to this
+	// notifier only, even when several share a store.
+	ID       string
+	Token    string
+	ChatID   string
+	Timezone *time.Location
+	Locale   domain.Locale
+	// Style selects MarkdownV2 templates (default) or structured messages.
+	Style     domain.MessageStyle
 	Templates *domain.Templates
+	// MessageThreadID sends events to a forum topic of ChatID instead of its
+	// General topic; FactionThreads overrides it per faction for defend and
+	// attack events.
+	MessageThreadID int
+	FactionThreads  map[domain.Enemy]int
+	// AllowedChats and AllowedUsers restrict commands to these chat and user
+	// IDs, plus ChatID. When both are empty anyone may run commands.
+	AllowedChats []string
+	AllowedUsers []string
+	// Subscriptions enables /subscribe and /unsubscribe so other chats can
+	// receive events; nil disables them.
+	Subscriptions port.SubscriptionStore
+	// Webhook, when set, receives updates through HTTPRoute instead of
+	// polling getUpdates.
+	Webhook *Webhook
 	// APIBase overrides the Telegram Bot API base URL. Defaults to
 	// "https://api.telegram.org". Intended for use in tests.
 	APIBase string
 }
 
-// update is a partial Telegram Update object used for command polling.
+// update is a partial Telegram Update object, received by polling or webhook.
+// ChannelPost is set instead of Message for posts in a channel.
 type update struct {
-	UpdateID int `json:"update_id"`
-	Message  *struct {
-		Text     string `json:"text"`
-		Entities []struct {
-			Type   string `json:"type"`
-			Offset int    `json:"offset"`
-			Length int    `json:"length"`
-		} `json:"entities"`
+	UpdateID      int            `json:"update_id"`
+	Message       *message       `json:"message"`
+	ChannelPost   *message       `json:"channel_post"`
+	CallbackQuery *callbackQuery `json:"callback_query"`
+}
+
+// message is a partial Telegram Message.
+type message struct {
+	// MessageThreadID is the forum topic when IsTopicMessage is set.
+	MessageThreadID int   `json:"message_thread_id"`
+	IsTopicMessage  bool  `json:"is_topic_message"`
+	Chat            chat  `json:"chat"`
+	From            *user `json:"from"`
+	// SenderChat is set instead of From for anonymous group admins, who
+	// post as the group itself, and for channel posts.
+	SenderChat *chat  `json:"sender_chat"`
+	Text       string `json:"text"`
+	Entities   []struct {
+		Type   string `json:"type"`
+		Offset int    `json:"offset"`
+		Length int    `json:"length"`
+	} `json:"entities"`
+}
+
+// chat is a partial Telegram Chat. Type is "private", "group", "supergroup"
+// or "channel".
+type chat struct {
+	ID   int64  `json:"id"`
+	Type string `json:"type"`
+}
+
+// user is a partial Telegram User.
+type user struct {
+	ID int64 `json:"id"`
+}
+
+// callbackQuery is a partial Telegram CallbackQuery, sent when an inline
+// keyboard button is pressed.
+type callbackQuery struct {
+	ID      string `json:"id"`
+	From    user   `json:"from"`
+	Data    string `json:"data"`
+	Message *struct {
+		MessageID int  `json:"message_id"`
+		Chat      chat `json:"chat"`
 	} `json:"message"`
 }
 
 // Notifier implements port.Notifier by sending messages to a Telegram chat.
-// It also polls for bot commands and handles /test, /status, and /statistics.
+// It also receives bot commands, by polling or through a webhook, and handles
+// /test, /status, and /statistics.
 type Notifier struct {
 	opts      Options
 	client    *http.Client
 	logger    *slog.Logger
 	templates domain.Templates
+	catalog   *domain.Catalog
+	messages  *domain.Catalog
 	cancel    context.CancelFunc
 	done      chan struct{}
+	updates   chan update
 	provider  port.StatusProvider
 	apiBase   string
+	limiter   *limiter
 }
 
-// New creates a new Notifier, validates options, and starts the command polling loop.
+// New creates a new Notifier, validates options, and starts the command
+// polling loop, or registers the webhook in webhook mode.
 func New(opts Options, logger *slog.Logger) (*Notifier, error) {
 	if logger == nil {
 		panic("telegram notifier: logger is required")
@@ -69,46 +136,95 @@ func New(opts Options, logger *slog.Logger) (*Notifier, error) {
 		opts.Timezone = time.UTC
 	}
 
-	templates := DefaultTemplates()
+	// Structured messages are escaped when rendered, so their titles are the
+	// catalog's plain-text templates rather than the MarkdownV2 defaults.
+	catalog := domain.CatalogFor(opts.Locale)
+	templates := LocalizedTemplates(opts.Locale)
+	if opts.Style == domain.MessageStyleStructured {
+		templates = catalog.Titles
+	}
 	if opts.Templates != nil {
 		templates = domain.MergeTemplates(templates, *opts.Templates)
 	}
 
-	ctx, cancel := context.WithCancel(context.Background())
+	// Numbers substituted into templates are not escaped afterwards, so the
+	// catalog used for messages needs a MarkdownV2-safe separator (e.g. `\.`).
+	messages := *catalog
+	messages.ThousandsSeparator = escape(catalog.ThousandsSeparator)
 
 	n := &Notifier{
 		opts:      opts,
 		client:    &http.Client{Timeout: 10 * time.Second},
 		logger:    logger,
 		templates: templates,
-		cancel:    cancel,
+		catalog:   catalog,
+		messages:  &messages,
 		done:      make(chan struct{}),
 		apiBase:   apiBase,
+		limiter:   newLimiter(),
 	}
 	if opts.APIBase != "" {
 		n.apiBase = opts.APIBase
 	}
 
-	go n.pollCommands(ctx)
+	if opts.Webhook != nil {
+		if err := n.setWebhook(); err != nil {
+			return nil, fmt.Errorf("telegram notifier: setting webhook: %w", err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	n.cancel = cancel
+	if opts.Webhook != nil {
+		// Updates arrive through HTTPRoute and are handled in the background.
+		n.updates = make(chan update, webhookQueueSize)
+		go n.handleWebhookUpdates(ctx)
+	} else {
+		go n.pollCommands(ctx)
+	}
 
 	return n, nil
 }
 
-// Close stops the command polling loop and waits for it to exit.
+// Close stops the command polling loop and waits for it to exit, or removes
+// the webhook in webhook mode.
 func (n *Notifier) Close() error {
 	n.cancel()
 	<-n.done
+	if n.opts.Webhook != nil {
+		if err := n.deleteWebhook(); err != nil {
+			return fmt.Errorf("telegram notifier: deleting webhook: %w", err)
+		}
+	}
 	return nil
 }
 
-// Notify sends a formatted event message to the configured Telegram chat.
+// Notify sends a formatted event message to the configured Telegram chat, in
+// the faction's forum topic when set, and to subscribed chats whose filters
+// match. Subscribed chats get the message even when the configured chat
+// cannot.
 func (n *Notifier) Notify(msg domain.EventMessage) error {
-	text, err := domain.RenderEvent(n.templates, msg, TimeFormatter(n.opts.Timezone))
+	text, err := n.format(msg)
 	if err != nil {
 		return fmt.Errorf("telegram notifier: rendering message: %w", err)
 	}
 
-	return n.sendMessage(text)
+	mainErr := n.sendTo(n.opts.ChatID, n.threadFor(msg), text, nil)
+	return errors.Join(mainErr, n.notifySubscribers(msg, text))
+}
+
+// format renders msg with the MarkdownV2 templates, or as a structured
+// message escaped for MarkdownV2.
+func (n *Notifier) format(msg domain.EventMessage) (string, error) {
+	tf := TimeFormatter(n.opts.Timezone)
+	if n.opts.Style != domain.MessageStyleStructured {
+		return n.messages.RenderEvent(n.templates, msg, tf)
+	}
+	m, err := n.catalog.BuildMessage(n.templates, msg, tf)
+	if err != nil {
+		return "", err
+	}
+	return m.Format(markdownV2(tf)), nil
 }
 
 // pollCommands long-polls getUpdates and dispatches recognised bot commands.
@@ -141,11 +257,12 @@ func (n *Notifier) pollCommands(ctx context.Context) {
 // getUpdates calls the Telegram getUpdates API with long-polling (timeout=30s).
 func (n *Notifier) getUpdates(ctx context.Context, offset int) ([]update, error) {
 	type params struct {
-		Offset  int `json:"offset"`
-		Timeout int `json:"timeout"`
+		Offset         int      `json:"offset"`
+		Timeout        int      `json:"timeout"`
+		AllowedUpdates []string `json:"allowed_updates"`
 	}
 
-	body, err := json.Marshal(params{Offset: offset, Timeout: 30})
+	body, err := json.Marshal(params{Offset: offset, Timeout: 30, AllowedUpdates: allowedUpdates})
 	if err != nil {
 		return nil, fmt.Errorf("marshaling getUpdates params: %w", err)
 	}
@@ -186,127 +303,245 @@ func (n *Notifier) getUpdates(ctx context.Context, offset int) ([]update, error)
 
 // handleUpdate dispatches a single update to the appropriate command handler.
 func (n *Notifier) handleUpdate(u update) {
-	if u.Message == nil {
+	if u.CallbackQuery != nil {
+		n.handleCallbackQuery(u.CallbackQuery)
+		return
+	}
+	m := u.Message
+	if m == nil {
+		m = u.ChannelPost
+	}
+	if m == nil {
 		return
 	}
 
-	for _, entity := range u.Message.Entities {
+	for _, entity := range m.Entities {
 		if entity.Type != "bot_command" {
 			continue
 		}
 
-		raw := u.Message.Text[entity.Offset : entity.Offset+entity.Length]
+		raw := m.Text[entity.Offset : entity.Offset+entity.Length]
 		// Strip @botname suffix present in group chats.
 		cmd := strings.SplitN(raw, "@", 2)[0]
 
 		// Argument is the text after the command entity.
-		arg := strings.TrimSpace(u.Message.Text[entity.Offset+entity.Length:])
-
-		switch cmd {
-		case "/test":
-			n.handleTestCommand()
-		case "/status":
-			n.handleStatusCommand(arg)
-		case "/statistics":
-			n.handleStatisticsCommand()
+		arg := strings.TrimSpace(m.Text[entity.Offset+entity.Length:])
+
+		r := commandRequest{
+			chatID:   strconv.FormatInt(m.Chat.ID, 10),
+			chatType: m.Chat.Type,
+			arg:      arg,
+		}
+		if m.IsTopicMessage {
+			r.threadID = m.MessageThreadID
 		}
+		if m.From != nil {
+			r.userID = m.From.ID
+		}
+		// Only administrators post as the chat itself: anonymous group
+		// admins and channel posters.
+		if sc := m.SenderChat; sc != nil && sc.ID == m.Chat.ID {
+			r.anonymousAdmin = true
+		}
+		n.dispatch(strings.TrimPrefix(cmd, "/"), r)
 	}
 }
 
-// RegisterCommands implements port.Commander.
+// RegisterCommands implements port.Commander. It also publishes the command
+// menu so users can discover the commands.
 func (n *Notifier) RegisterCommands(provider port.StatusProvider) {
 	n.provider = provider
+	if err := n.setMyCommands(); err != nil {
+		n.logger.Error("telegram notifier: setMyCommands failed", "error", err)
+	}
 }
 
-// handleTestCommand sends a test message to the configured chat_id.
-func (n *Notifier) handleTestCommand() {
-	n.logger.Info("telegram notifier: /test command received, sending test message")
-	err := n.sendMessage("✅ hellbot is connected and can send messages to this chat\\.")
+// handleTestCommand confirms in the requesting chat that the bot can post
+// there.
+func (n *Notifier) handleTestCommand(r commandRequest) {
+	n.logger.Info("telegram notifier: /test command received, sending test message", "chat", r.chatID)
+	err := n.sendTo(r.chatID, r.threadID, escape(n.catalog.TestMessage), nil)
 	if err != nil {
 		n.logger.Error("telegram notifier: /test failed", "error", err)
 	}
 }
 
-// handleStatusCommand responds to /status [faction].
-func (n *Notifier) handleStatusCommand(arg string) {
+// handleStatusCommand responds to /status [faction] with the status and a
+// keyboard to switch factions or show statistics.
+func (n *Notifier) handleStatusCommand(r commandRequest) {
 	if n.provider == nil {
 		n.logger.Warn("telegram notifier: /status received but no status provider registered")
 		return
 	}
 
 	var filter *domain.Enemy
-	if arg != "" {
-		if enemy, ok := domain.ParseEnemy(arg); ok {
+	if r.arg != "" {
+		if enemy, ok := domain.ParseEnemy(r.arg); ok {
 			e := enemy
 			filter = &e
 		}
 	}
 
-	c, err := n.provider.LatestCampaign()
+	text, err := n.statusText(filter)
 	if err != nil {
 		n.logger.Error("telegram notifier: /status failed to fetch campaign", "error", err)
-		_ = n.sendMessage("⚠️ Could not retrieve war status\\.")
+		_ = n.sendTo(r.chatID, r.threadID, escape(n.catalog.StatusFailed), nil)
 		return
 	}
 
-	text := escape(domain.FormatStatus(c, filter))
-	if sendErr := n.sendMessage("```\n" + text + "\n```"); sendErr != nil {
+	if sendErr := n.sendTo(r.chatID, r.threadID, text, n.statusKeyboard()); sendErr != nil {
 		n.logger.Error("telegram notifier: /status failed to send", "error", sendErr)
 	}
 }
 
+// statusText renders the status board, optionally for one faction.
+func (n *Notifier) statusText(filter *domain.Enemy) (string, error) {
+	c, err := n.provider.LatestCampaign()
+	if err != nil {
+		return "", err
+	}
+	return n.catalog.StatusMessage(c, filter).Format(markdownV2(TimeFormatter(n.opts.Timezone))), nil
+}
+
+// statisticsText renders the war statistics.
+func (n *Notifier) statisticsText() (string, error) {
+	c, err := n.provider.LatestCampaign()
+	if err != nil {
+		return "", err
+	}
+	return n.catalog.StatisticsMessage(c).Format(markdownV2(TimeFormatter(n.opts.Timezone))), nil
+}
+
 // handleStatisticsCommand responds to /statistics.
-func (n *Notifier) handleStatisticsCommand() {
+func (n *Notifier) handleStatisticsCommand(r commandRequest) {
 	if n.provider == nil {
 		n.logger.Warn("telegram notifier: /statistics received but no status provider registered")
 		return
 	}
 
-	c, err := n.provider.LatestCampaign()
+	text, err := n.statisticsText()
 	if err != nil {
 		n.logger.Error("telegram notifier: /statistics failed to fetch campaign", "error", err)
-		_ = n.sendMessage("⚠️ Could not retrieve statistics\\.")
+		_ = n.sendTo(r.chatID, r.threadID, escape(n.catalog.StatisticsFailed), nil)
 		return
 	}
 
-	text := escape(domain.FormatStatistics(c))
-	if sendErr := n.sendMessage("```\n" + text + "\n```"); sendErr != nil {
+	if sendErr := n.sendTo(r.chatID, r.threadID, text, nil); sendErr != nil {
 		n.logger.Error("telegram notifier: /statistics failed to send", "error", sendErr)
 	}
 }
 
-// sendMessage calls the Telegram sendMessage API with MarkdownV2 parse mode.
-func (n *Notifier) sendMessage(text string) error {
+// threadFor returns the forum topic of the configured chat that msg is sent
+// to: its faction's when configured, otherwise MessageThreadID.
+func (n *Notifier) threadFor(msg domain.EventMessage) int {
+	if e, ok := msg.Enemy(); ok {
+		if id, ok := n.opts.FactionThreads[e]; ok {
+			return id
+		}
+	}
+	return n.opts.MessageThreadID
+}
+
+// sendTo sends text to chatID, in the forum topic threadID unless it is 0,
+// within the rate limits. When Telegram still asks to slow down, the message
+// is retried once after the given delay.
+func (n *Notifier) sendTo(chatID string, threadID int, text string, keyboard *inlineKeyboard) error {
 	type payload struct {
-		ChatID    string `json:"chat_id"`
-		Text      string `json:"text"`
-		ParseMode string `json:"parse_mode"`
+		ChatID          string          `json:"chat_id"`
+		MessageThreadID int             `json:"message_thread_id,omitempty"`
+		Text            string          `json:"text"`
+		ParseMode       string          `json:"parse_mode"`
+		ReplyMarkup     *inlineKeyboard `json:"reply_markup,omitempty"`
 	}
 
-	body, err := json.Marshal(payload{
-		ChatID:    n.opts.ChatID,
-		Text:      text,
-		ParseMode: "MarkdownV2",
-	})
+	p := payload{
+		ChatID:          chatID,
+		MessageThreadID: threadID,
+		Text:            text,
+		ParseMode:       "MarkdownV2",
+		ReplyMarkup:     keyboard,
+	}
+	n.limiter.wait(chatID)
+	err := n.call("sendMessage", p)
+	var apiErr *apiError
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+		n.logger.Warn("telegram notifier: rate limited", "chat", chatID, "retry_after", apiErr.RetryAfter)
+		n.limiter.sleep(time.Duration(apiErr.RetryAfter) * time.Second)
+		n.limiter.wait(chatID)
+		err = n.call("sendMessage", p)
+	}
 	if err != nil {
-		return fmt.Errorf("telegram notifier: marshaling payload: %w", err)
+		return fmt.Errorf("telegram notifier: sending message: %w", err)
 	}
+	return nil
+}
+
+// apiError is a failed Bot API call. RetryAfter is the number of seconds to
+// wait when Telegram rate limited the call.
+type apiError struct {
+	Method      string
+	StatusCode  int
+	Description string
+	RetryAfter  int
+}
 
-	url := fmt.Sprintf("%s/bot%s/sendMessage", n.apiBase, n.opts.Token)
+func (e *apiError) Error() string {
+	if e.Description == "" {
+		return fmt.Sprintf("%s: unexpected status %d", e.Method, e.StatusCode)
+	}
+	return fmt.Sprintf("%s: unexpected status %d: %s", e.Method, e.StatusCode, e.Description)
+}
+
+// call posts payload as JSON to a Bot API method. A non-200 response is
+// returned as an *apiError with Telegram's description.
+func (n *Notifier) call(method string, payload any) error {
+	return n.callResult(method, payload, nil)
+}
+
+// callResult is call that also decodes the response's result into result,
+// unless it is nil.
+func (n *Notifier) callResult(method string, payload, result any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshaling %s payload: %w", method, err)
+	}
+
+	url := fmt.Sprintf("%s/bot%s/%s", n.apiBase, n.opts.Token, method)
 	resp, err := n.client.Post(url, "application/json", bytes.NewReader(body))
 	if err != nil {
-		return fmt.Errorf("telegram notifier: sending message: %w", err)
+		return fmt.Errorf("%s request: %w", method, err)
 	}
 	defer func() {
 		if err := resp.Body.Close(); err != nil {
-			n.logger.Error("telegram notifier: closing sendMessage response body", "error", err)
+			n.logger.Error("telegram notifier: closing response body", "method", method, "error", err)
 		}
 	}()
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("telegram notifier: unexpected status %d", resp.StatusCode)
+		var failure struct {
+			Description string `json:"description"`
+			Parameters  struct {
+				RetryAfter int `json:"retry_after"`
+			} `json:"parameters"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&failure)
+		return &apiError{
+			Method:      method,
+			StatusCode:  resp.StatusCode,
+			Description: failure.Description,
+			RetryAfter:  failure.Parameters.RetryAfter,
+		}
 	}
 
+	if result == nil {
+		return nil
+	}
+	envelope := struct {
+		Result any `json:"result"`
+	}{Result: result}
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return fmt.Errorf("decoding %s response: %w", method, err)
+	}
 	return nil
 }
 
@@ -325,3 +560,20 @@ func escape(s string) string {
 	}
 	return string(out)
 }
+
+// markdownV2 renders structured message spans as Telegram MarkdownV2.
+// Absolute timestamps use formatTime.
+func markdownV2(formatTime func(time.Time) string) domain.Markup {
+	return domain.Markup{
+		Text:   escape,
+		Bold:   func(s string) string { return "*" + escape(s) + "*" },
+		Italic: func(s string) string { return "_" + escape(s) + "_" },
+		Code:   func(s string) string { return "`" + escape(s) + "`" },
+		Time: func(s domain.Span) string {
+			if s.Relative {
+				return escape(s.Text)
+			}
+			return escape(formatTime(s.Time))
+		},
+	}
+}
@@ -0,0 +1,131 @@
+package telegram_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ametis70/hellbot/internal/adapter/notifier/telegram"
+)
+
+// allowing restricts commands to the given chats and users.
+func allowing(chats, users []string) func(*telegram.Options) {
+	return func(o *telegram.Options) {
+		o.AllowedChats = chats
+		o.AllowedUsers = users
+	}
+}
+
+// groupUpdate is a command sent by userID in the group chatID.
+func groupUpdate(chatID, userID int64, text string) map[string]any {
+	u := botUpdate(text, "bot_command")
+	msg := u["message"].(map[string]any)
+	msg["chat"] = map[string]any{"id": chatID, "type": "supergroup"}
+	msg["from"] = map[string]any{"id": userID}
+	return u
+}
+
+// TestTelegram_Command_RepliesToOriginatingChat verifies commands are
+// answered in the chat they came from, not the configured one.
+func TestTelegram_Command_RepliesToOriginatingChat(t *testing.T) {
+	srv := &commandServer{
+		updates: []map[string]any{groupUpdate(-555, 42, "/help")},
+	}
+	newCommandNotifier(t, srv)
+	waitSend(t, srv)
+
+	if got := srv.sendsTo("-555"); len(got) != 1 {
+		t.Errorf("expected one reply in chat -555, got %v", got)
+	}
+	if got := srv.sendsTo("-1"); len(got) != 0 {
+		t.Errorf("expected nothing in the configured chat, got %v", got)
+	}
+}
+
+// TestTelegram_Command_NotAllowed verifies commands from chats and users
+// outside the allowlist are ignored.
+func TestTelegram_Command_NotAllowed(t *testing.T) {
+	srv := &commandServer{
+		updates: []map[string]any{
+			groupUpdate(-555, 42, "/help"),
+			groupUpdate(-777, 42, "/help"),
+		},
+	}
+	newCommandNotifier(t, srv, allowing([]string{"-777"}, nil))
+	waitSend(t, srv)
+
+	if got := srv.sendsTo("-555"); len(got) != 0 {
+		t.Errorf("expected no reply in chat -555, got %v", got)
+	}
+	if got := srv.sendsTo("-777"); len(got) != 1 {
+		t.Errorf("expected one reply in chat -777, got %v", got)
+	}
+}
+
+// TestTelegram_Command_AllowedUser verifies an allowed user may run commands
+// from any chat.
+func TestTelegram_Command_AllowedUser(t *testing.T) {
+	srv := &commandServer{
+		updates: []map[string]any{groupUpdate(-555, 42, "/help")},
+	}
+	newCommandNotifier(t, srv, allowing([]string{"-777"}, []string{"42"}))
+	waitSend(t, srv)
+
+	if got := srv.sendsTo("-555"); len(got) != 1 {
+		t.Errorf("expected one reply in chat -555, got %v", got)
+	}
+}
+
+// TestTelegram_AdminCommand_DeniedForMember verifies admin commands are
+// refused to members who do not administer the chat.
+func TestTelegram_AdminCommand_DeniedForMember(t *testing.T) {
+	srv := &commandServer{
+		updates: []map[string]any{groupUpdate(-555, 42, "/test")},
+		member:  "member",
+	}
+	newCommandNotifier(t, srv)
+	waitSend(t, srv)
+
+	if got := srv.sendsTo("-555"); len(got) != 1 || !strings.HasPrefix(got[0], "⛔") {
+		t.Errorf("expected a denial in chat -555, got %v", got)
+	}
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	if len(srv.members) != 1 || srv.members[0]["chat_id"] != "-555" || srv.members[0]["user_id"] != float64(42) {
+		t.Errorf("expected getChatMember for user 42 in chat -555, got %v", srv.members)
+	}
+}
+
+// TestTelegram_AdminCommand_PrivateChat verifies admin commands run in a
+// private chat without looking up the member.
+func TestTelegram_AdminCommand_PrivateChat(t *testing.T) {
+	srv := &commandServer{
+		updates: []map[string]any{chatUpdate(555, "/test")},
+	}
+	newCommandNotifier(t, srv)
+	waitSend(t, srv)
+
+	if got := srv.sendsTo("555"); len(got) != 1 || strings.HasPrefix(got[0], "⛔") {
+		t.Errorf("expected the test message in chat 555, got %v", got)
+	}
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	if len(srv.members) != 0 {
+		t.Errorf("expected no getChatMember calls, got %v", srv.members)
+	}
+}
+
+// TestTelegram_CallbackQuery_NotAllowed verifies buttons pressed in a chat
+// outside the allowlist are answered but not acted on.
+func TestTelegram_CallbackQuery_NotAllowed(t *testing.T) {
+	srv := &commandServer{
+		updates: []map[string]any{callbackUpdate("status")},
+	}
+	newCommandNotifier(t, srv, allowing([]string{"-777"}, nil))
+	waitAnswer(t, srv)
+
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	if len(srv.edits) != 0 {
+		t.Errorf("expected no edits, got %d", len(srv.edits))
+	}
+}
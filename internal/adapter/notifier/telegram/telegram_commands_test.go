@@ -4,30 +4,59 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/ametis70/hellbot/internal/adapter/notifier/telegram"
 	"github.com/ametis70/hellbot/internal/adapter/store/memory"
+	"github.com/ametis70/hellbot/internal/domain"
 	"github.com/ametis70/hellbot/internal/testutil"
 )
 
 // commandServer is a fake Telegram server that serves a sequence of updates
-// then returns empty updates. It also captures sendMessage calls.
+// then returns empty updates. It also captures sendMessage, editMessageText,
+// getChatMember and answerCallbackQuery calls. While a webhook is set,
+// getUpdates fails like it does on Telegram.
 type commandServer struct {
 	mu      sync.Mutex
+	calls   []string
+	webhook map[string]any
 	updates []map[string]any
 	idx     int
 	sends   []string
+	bodies  []map[string]any
+	edits   []map[string]any
+	answers []string
+	menus   []map[string]any
+	// editError, when set, is returned as the description of a 400 response
+	// to editMessageText.
+	editError string
+	// blocked is a chat ID whose sendMessage calls fail with 403.
+	blocked string
+	// hold, when set, delays sendMessage responses until it is closed.
+	hold chan struct{}
+	// member is the status getChatMember returns, "member" when empty.
+	member  string
+	members []map[string]any
 }
 
 func (c *commandServer) handler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
+	c.mu.Lock()
+	c.calls = append(c.calls, r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:])
+	c.mu.Unlock()
 
 	switch {
 	case hasSuffix(r.URL.Path, "getUpdates"):
 		c.mu.Lock()
+		if c.webhook != nil {
+			c.mu.Unlock()
+			w.WriteHeader(http.StatusConflict)
+			_ = json.NewEncoder(w).Encode(map[string]any{"ok": false, "description": "Conflict: can't use getUpdates method while webhook is active"})
+			return
+		}
 		var result []map[string]any
 		if c.idx < len(c.updates) {
 			result = c.updates[c.idx : c.idx+1]
@@ -37,12 +66,78 @@ func (c *commandServer) handler(w http.ResponseWriter, r *http.Request) {
 		_ = json.NewEncoder(w).Encode(map[string]any{"ok": true, "result": result})
 
 	case hasSuffix(r.URL.Path, "sendMessage"):
+		if c.hold != nil {
+			<-c.hold
+		}
 		var body map[string]any
 		_ = json.NewDecoder(r.Body).Decode(&body)
+		if c.blocked != "" && body["chat_id"] == c.blocked {
+			w.WriteHeader(http.StatusForbidden)
+			_ = json.NewEncoder(w).Encode(map[string]any{"ok": false, "description": "Forbidden: bot was blocked by the user"})
+			return
+		}
 		c.mu.Lock()
 		if text, ok := body["text"].(string); ok {
 			c.sends = append(c.sends, text)
 		}
+		c.bodies = append(c.bodies, body)
+		c.mu.Unlock()
+		_ = json.NewEncoder(w).Encode(map[string]any{"ok": true})
+
+	case hasSuffix(r.URL.Path, "editMessageText"):
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		c.mu.Lock()
+		c.edits = append(c.edits, body)
+		editError := c.editError
+		c.mu.Unlock()
+		if editError != "" {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]any{"ok": false, "description": editError})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"ok": true})
+
+	case hasSuffix(r.URL.Path, "setMyCommands"):
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		c.mu.Lock()
+		c.menus = append(c.menus, body)
+		c.mu.Unlock()
+		_ = json.NewEncoder(w).Encode(map[string]any{"ok": true, "result": true})
+
+	case hasSuffix(r.URL.Path, "getChatMember"):
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		c.mu.Lock()
+		c.members = append(c.members, body)
+		status := c.member
+		c.mu.Unlock()
+		if status == "" {
+			status = "member"
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"ok": true, "result": map[string]any{"status": status}})
+
+	case hasSuffix(r.URL.Path, "setWebhook"):
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		c.mu.Lock()
+		c.webhook = body
+		c.mu.Unlock()
+		_ = json.NewEncoder(w).Encode(map[string]any{"ok": true, "result": true})
+
+	case hasSuffix(r.URL.Path, "deleteWebhook"):
+		c.mu.Lock()
+		c.webhook = nil
+		c.mu.Unlock()
+		_ = json.NewEncoder(w).Encode(map[string]any{"ok": true, "result": true})
+
+	case hasSuffix(r.URL.Path, "answerCallbackQuery"):
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		c.mu.Lock()
+		id, _ := body["callback_query_id"].(string)
+		c.answers = append(c.answers, id)
 		c.mu.Unlock()
 		_ = json.NewEncoder(w).Encode(map[string]any{"ok": true})
 
@@ -57,6 +152,19 @@ func (c *commandServer) sendCount() int {
 	return len(c.sends)
 }
 
+// called returns how many times method was called.
+func (c *commandServer) called(method string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	count := 0
+	for _, m := range c.calls {
+		if m == method {
+			count++
+		}
+	}
+	return count
+}
+
 func hasSuffix(s, suffix string) bool {
 	return len(s) >= len(suffix) && s[len(s)-len(suffix):] == suffix
 }
@@ -81,16 +189,23 @@ func botUpdate(text, cmdType string) map[string]any {
 	}
 }
 
-func newCommandNotifier(t *testing.T, srv *commandServer) *telegram.Notifier {
+// newCommandNotifier returns a notifier for chat -1 talking to srv, with
+// opts applied on top.
+func newCommandNotifier(t *testing.T, srv *commandServer, opts ...func(*telegram.Options)) *telegram.Notifier {
 	t.Helper()
 	ts := httptest.NewServer(http.HandlerFunc(srv.handler))
 	t.Cleanup(ts.Close)
-	n, err := telegram.New(telegram.Options{
+	o := telegram.Options{
+		ID:       "main",
 		Token:    "tok",
 		ChatID:   "-1",
 		Timezone: time.UTC,
 		APIBase:  ts.URL,
-	}, testutil.DiscardLogger())
+	}
+	for _, fn := range opts {
+		fn(&o)
+	}
+	n, err := telegram.New(o, testutil.DiscardLogger())
 	if err != nil {
 		t.Fatalf("New: %v", err)
 	}
@@ -111,13 +226,19 @@ func waitSend(t *testing.T, srv *commandServer) {
 	t.Fatalf("timed out waiting for send, got %d", srv.sendCount())
 }
 
-// TestTelegram_HandleUpdate_TestCommand verifies that /test triggers a sendMessage.
+// TestTelegram_HandleUpdate_TestCommand verifies that /test from a chat
+// administrator triggers a sendMessage.
 func TestTelegram_HandleUpdate_TestCommand(t *testing.T) {
 	srv := &commandServer{
-		updates: []map[string]any{botUpdate("/test", "bot_command")},
+		updates: []map[string]any{groupUpdate(-100, 42, "/test")},
+		member:  "administrator",
 	}
 	newCommandNotifier(t, srv)
 	waitSend(t, srv)
+
+	if got := srv.sendsTo("-100"); len(got) != 1 || strings.HasPrefix(got[0], "⛔") {
+		t.Errorf("expected the test message in chat -100, got %v", got)
+	}
 }
 
 // TestTelegram_HandleUpdate_StatusCommand_NoProvider verifies /status with no
@@ -172,6 +293,23 @@ func TestTelegram_HandleUpdate_StatusCommand_StoreError(t *testing.T) {
 	waitSend(t, srv)
 }
 
+// TestTelegram_HandleUpdate_StatusCommand_StoreErrorSpanish verifies the
+// error reply follows the notifier's locale.
+func TestTelegram_HandleUpdate_StatusCommand_StoreErrorSpanish(t *testing.T) {
+	srv := &commandServer{
+		updates: []map[string]any{botUpdate("/status", "bot_command")},
+	}
+	n := newCommandNotifier(t, srv, func(o *telegram.Options) { o.Locale = domain.LocaleSpanish })
+	n.RegisterCommands(&testutil.ErrorStore{})
+	waitSend(t, srv)
+
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	if len(srv.sends) != 1 || !strings.Contains(srv.sends[0], "No se pudo obtener el estado de la guerra") {
+		t.Errorf("expected a Spanish error reply, got %v", srv.sends)
+	}
+}
+
 // TestTelegram_HandleUpdate_StatisticsCommand_WithProvider verifies /statistics
 // with a provider sends a message.
 func TestTelegram_HandleUpdate_StatisticsCommand_WithProvider(t *testing.T) {
@@ -243,3 +381,257 @@ func TestTelegram_RegisterCommands(t *testing.T) {
 	store := memory.New()
 	n.RegisterCommands(store) // should not panic
 }
+
+// TestTelegram_HandleUpdate_StatusCommand_Formatted verifies /status renders
+// the board as MarkdownV2 rather than a code block.
+func TestTelegram_HandleUpdate_StatusCommand_Formatted(t *testing.T) {
+	store := memory.New()
+	_ = store.SaveCampaign(testutil.CampaignWithNoDefend())
+
+	srv := &commandServer{
+		updates: []map[string]any{botUpdate("/status", "bot_command")},
+	}
+	n := newCommandNotifier(t, srv)
+	n.RegisterCommands(store)
+	waitSend(t, srv)
+
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	if want := "*War 159 — Status*"; !strings.HasPrefix(srv.sends[0], want) {
+		t.Errorf("expected bold title %q, got %q", want, srv.sends[0])
+	}
+	if strings.Contains(srv.sends[0], "```") {
+		t.Errorf("expected no code block, got %q", srv.sends[0])
+	}
+}
+
+func callbackUpdate(data string) map[string]any {
+	return map[string]any{
+		"update_id": 1,
+		"callback_query": map[string]any{
+			"id":   "cb-1",
+			"data": data,
+			"message": map[string]any{
+				"message_id": 42,
+				"chat":       map[string]any{"id": -100},
+			},
+		},
+	}
+}
+
+// waitAnswer polls until the server has answered a callback query or times out.
+func waitAnswer(t *testing.T, srv *commandServer) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		srv.mu.Lock()
+		n := len(srv.answers)
+		srv.mu.Unlock()
+		if n >= 1 {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("timed out waiting for the callback query answer")
+}
+
+// TestTelegram_StatusCommand_Keyboard verifies /status replies with the
+// faction and statistics buttons.
+func TestTelegram_StatusCommand_Keyboard(t *testing.T) {
+	store := memory.New()
+	_ = store.SaveCampaign(testutil.CampaignWithNoDefend())
+
+	srv := &commandServer{
+		updates: []map[string]any{botUpdate("/status", "bot_command")},
+	}
+	n := newCommandNotifier(t, srv)
+	n.RegisterCommands(store)
+	waitSend(t, srv)
+
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	markup, _ := json.Marshal(srv.bodies[0]["reply_markup"])
+	for _, want := range []string{`"status"`, `"status:bugs"`, `"status:cyborgs"`, `"status:illuminate"`, `"statistics"`, `"Bugs"`} {
+		if !strings.Contains(string(markup), want) {
+			t.Errorf("expected %s in keyboard, got %s", want, markup)
+		}
+	}
+}
+
+// TestTelegram_CallbackQuery_EditsMessage verifies a faction button edits the
+// status message in place, keeps the keyboard and answers the query.
+func TestTelegram_CallbackQuery_EditsMessage(t *testing.T) {
+	store := memory.New()
+	_ = store.SaveCampaign(testutil.CampaignWithNoDefend())
+
+	srv := &commandServer{
+		updates: []map[string]any{callbackUpdate("status:bugs")},
+	}
+	n := newCommandNotifier(t, srv)
+	n.RegisterCommands(store)
+	waitAnswer(t, srv)
+
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	if len(srv.edits) != 1 || len(srv.sends) != 0 {
+		t.Fatalf("expected one edit and no sends, got %d edits and %d sends", len(srv.edits), len(srv.sends))
+	}
+	edit := srv.edits[0]
+	if edit["message_id"] != float64(42) || edit["chat_id"] != float64(-100) {
+		t.Errorf("expected message 42 in chat -100 to be edited, got %v", edit)
+	}
+	text, _ := edit["text"].(string)
+	if !strings.Contains(text, "Bugs") {
+		t.Errorf("expected the Bugs status, got %q", text)
+	}
+	if edit["reply_markup"] == nil {
+		t.Error("expected the keyboard to be kept")
+	}
+	if srv.answers[0] != "cb-1" {
+		t.Errorf("expected callback cb-1 to be answered, got %v", srv.answers)
+	}
+}
+
+// TestTelegram_CallbackQuery_Statistics verifies the statistics button.
+func TestTelegram_CallbackQuery_Statistics(t *testing.T) {
+	store := memory.New()
+	_ = store.SaveCampaign(testutil.CampaignWithNoDefend())
+
+	srv := &commandServer{
+		updates: []map[string]any{callbackUpdate("statistics")},
+	}
+	n := newCommandNotifier(t, srv)
+	n.RegisterCommands(store)
+	waitAnswer(t, srv)
+
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	if len(srv.edits) != 1 {
+		t.Fatalf("expected one edit, got %d", len(srv.edits))
+	}
+	if text, _ := srv.edits[0]["text"].(string); !strings.Contains(text, "Statistics") {
+		t.Errorf("expected statistics, got %q", text)
+	}
+}
+
+// TestTelegram_CallbackQuery_NotModified verifies pressing the button of the
+// view already shown is still answered.
+func TestTelegram_CallbackQuery_NotModified(t *testing.T) {
+	store := memory.New()
+	_ = store.SaveCampaign(testutil.CampaignWithNoDefend())
+
+	srv := &commandServer{
+		updates:   []map[string]any{callbackUpdate("status")},
+		editError: "Bad Request: message is not modified",
+	}
+	n := newCommandNotifier(t, srv)
+	n.RegisterCommands(store)
+	waitAnswer(t, srv)
+}
+
+// TestTelegram_CallbackQuery_UnknownData verifies unknown buttons edit nothing.
+func TestTelegram_CallbackQuery_UnknownData(t *testing.T) {
+	store := memory.New()
+	_ = store.SaveCampaign(testutil.CampaignWithNoDefend())
+
+	srv := &commandServer{
+		updates: []map[string]any{callbackUpdate("status:automatons")},
+	}
+	n := newCommandNotifier(t, srv)
+	n.RegisterCommands(store)
+	waitAnswer(t, srv)
+
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	if len(srv.edits) != 0 {
+		t.Errorf("expected no edits, got %d", len(srv.edits))
+	}
+}
+
+// menuCommands returns the command names of the menu for scope and language.
+func (c *commandServer) menuCommands(scope, language string) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, m := range c.menus {
+		lang, _ := m["language_code"].(string)
+		if m["scope"].(map[string]any)["type"] != scope || lang != language {
+			continue
+		}
+		var names []string
+		for _, cmd := range m["commands"].([]any) {
+			names = append(names, cmd.(map[string]any)["command"].(string))
+		}
+		return names
+	}
+	return nil
+}
+
+// TestTelegram_RegisterCommands_SetsMenu verifies the command menu is
+// published per scope and language.
+func TestTelegram_RegisterCommands_SetsMenu(t *testing.T) {
+	srv := &commandServer{}
+	n := newCommandNotifier(t, srv)
+	n.RegisterCommands(memory.New())
+
+	srv.mu.Lock()
+	menus := len(srv.menus)
+	srv.mu.Unlock()
+	if menus != 6 {
+		t.Fatalf("expected 6 menus (3 scopes x 2 languages), got %d", menus)
+	}
+	if got := strings.Join(srv.menuCommands("default", ""), ","); got != "status,statistics,help" {
+		t.Errorf("unexpected default menu %s", got)
+	}
+	if got := strings.Join(srv.menuCommands("all_chat_administrators", "es"), ","); got != "status,statistics,test,help" {
+		t.Errorf("unexpected administrator menu %s", got)
+	}
+	if got := strings.Join(srv.menuCommands("all_private_chats", ""), ","); got != "status,statistics,test,help" {
+		t.Errorf("unexpected private chat menu %s", got)
+	}
+
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	for _, m := range srv.menus {
+		if m["language_code"] != "es" {
+			continue
+		}
+		first := m["commands"].([]any)[0].(map[string]any)
+		if first["description"] != "Progreso de la guerra por facción, opcionalmente de una facción" {
+			t.Errorf("expected Spanish descriptions, got %v", first)
+		}
+	}
+}
+
+// TestTelegram_RegisterCommands_SubscriptionsInMenu verifies subscription
+// commands are listed only when enabled, and only to administrators.
+func TestTelegram_RegisterCommands_SubscriptionsInMenu(t *testing.T) {
+	srv := &commandServer{}
+	n := newCommandNotifier(t, srv, withSubscriptions(memory.New()))
+	n.RegisterCommands(memory.New())
+	if got := strings.Join(srv.menuCommands("default", ""), ","); got != "status,statistics,help" {
+		t.Errorf("unexpected default menu %s", got)
+	}
+	if got := strings.Join(srv.menuCommands("all_chat_administrators", ""), ","); got != "status,statistics,subscribe,unsubscribe,test,help" {
+		t.Errorf("unexpected administrator menu %s", got)
+	}
+}
+
+// TestTelegram_HandleUpdate_HelpCommand verifies /help lists the commands.
+func TestTelegram_HandleUpdate_HelpCommand(t *testing.T) {
+	srv := &commandServer{
+		updates: []map[string]any{botUpdate("/help", "bot_command")},
+	}
+	newCommandNotifier(t, srv)
+	waitSend(t, srv)
+
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	for _, want := range []string{"*Commands*", "/status — ", "/statistics — ", "/test — ", "/help — "} {
+		if !strings.Contains(srv.sends[0], want) {
+			t.Errorf("expected %q in help, got %q", want, srv.sends[0])
+		}
+	}
+	if strings.Contains(srv.sends[0], "/subscribe") {
+		t.Errorf("expected no subscription commands when disabled, got %q", srv.sends[0])
+	}
+}
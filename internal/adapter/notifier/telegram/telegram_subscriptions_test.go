@@ -0,0 +1,196 @@
+package telegram_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ametis70/hellbot/internal/adapter/notifier/telegram"
+	"github.com/ametis70/hellbot/internal/adapter/store/memory"
+	"github.com/ametis70/hellbot/internal/domain"
+	"github.com/ametis70/hellbot/internal/testutil"
+)
+
+// withSubscriptions enables /subscribe with store.
+func withSubscriptions(store *memory.MemoryStore) func(*telegram.Options) {
+	return func(o *telegram.Options) { o.Subscriptions = store }
+}
+
+// chatUpdate is a command sent in the private chat chatID.
+func chatUpdate(chatID int64, text string) map[string]any {
+	u := botUpdate(text, "bot_command")
+	u["message"].(map[string]any)["chat"] = map[string]any{"id": chatID, "type": "private"}
+	return u
+}
+
+// sendsTo returns the texts sent to chatID.
+func (c *commandServer) sendsTo(chatID string) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var texts []string
+	for _, b := range c.bodies {
+		if b["chat_id"] == chatID {
+			texts = append(texts, b["text"].(string))
+		}
+	}
+	return texts
+}
+
+// TestTelegram_Subscribe verifies /subscribe stores the chat's filters and
+// replies in that chat.
+func TestTelegram_Subscribe(t *testing.T) {
+	store := memory.New()
+	srv := &commandServer{
+		updates: []map[string]any{chatUpdate(555, "/subscribe bugs attack")},
+	}
+	newCommandNotifier(t, srv, withSubscriptions(store))
+	waitSend(t, srv)
+
+	subs, _ := store.ListSubscriptions("telegram:main")
+	if len(subs) != 1 || subs[0].ID != "555" {
+		t.Fatalf("expected chat 555 to be subscribed, got %+v", subs)
+	}
+	if len(subs[0].Factions) != 1 || subs[0].Factions[0] != domain.EnemyBug ||
+		len(subs[0].Kinds) != 1 || subs[0].Kinds[0] != domain.EventKindAttack {
+		t.Errorf("unexpected filters %+v", subs[0])
+	}
+	if got := srv.sendsTo("555"); len(got) != 1 || !strings.HasPrefix(got[0], "🔔") {
+		t.Errorf("expected a confirmation in chat 555, got %v", got)
+	}
+}
+
+// TestTelegram_Subscribe_Spanish verifies the confirmation follows the
+// notifier's locale.
+func TestTelegram_Subscribe_Spanish(t *testing.T) {
+	srv := &commandServer{
+		updates: []map[string]any{chatUpdate(555, "/subscribe attack")},
+	}
+	newCommandNotifier(t, srv, withSubscriptions(memory.New()), func(o *telegram.Options) { o.Locale = domain.LocaleSpanish })
+	waitSend(t, srv)
+
+	if got := srv.sendsTo("555"); len(got) != 1 || !strings.Contains(got[0], "Suscrito a eventos de tipo attack") {
+		t.Errorf("expected a Spanish confirmation in chat 555, got %v", got)
+	}
+}
+
+// TestTelegram_Subscribe_UnknownArgument verifies a bad filter is rejected.
+func TestTelegram_Subscribe_UnknownArgument(t *testing.T) {
+	store := memory.New()
+	srv := &commandServer{
+		updates: []map[string]any{chatUpdate(555, "/subscribe automatons")},
+	}
+	newCommandNotifier(t, srv, withSubscriptions(store))
+	waitSend(t, srv)
+
+	if subs, _ := store.ListSubscriptions("telegram:main"); len(subs) != 0 {
+		t.Errorf("expected no subscription, got %+v", subs)
+	}
+	if got := srv.sendsTo("555"); len(got) != 1 || !strings.HasPrefix(got[0], "⚠️") {
+		t.Errorf("expected an error reply, got %v", got)
+	}
+}
+
+// TestTelegram_Unsubscribe verifies /unsubscribe removes the chat.
+func TestTelegram_Unsubscribe(t *testing.T) {
+	store := memory.New()
+	_ = store.SaveSubscription("telegram:main", domain.Subscription{ID: "555"})
+	srv := &commandServer{
+		updates: []map[string]any{chatUpdate(555, "/unsubscribe")},
+	}
+	newCommandNotifier(t, srv, withSubscriptions(store))
+	waitSend(t, srv)
+
+	if subs, _ := store.ListSubscriptions("telegram:main"); len(subs) != 0 {
+		t.Errorf("expected no subscription, got %+v", subs)
+	}
+}
+
+// TestTelegram_Subscribe_Disabled verifies the commands are ignored without a
+// subscription store.
+func TestTelegram_Subscribe_Disabled(t *testing.T) {
+	srv := &commandServer{
+		updates: []map[string]any{chatUpdate(555, "/subscribe")},
+	}
+	newCommandNotifier(t, srv)
+	time.Sleep(150 * time.Millisecond)
+	if srv.sendCount() != 0 {
+		t.Errorf("expected 0 sends, got %d", srv.sendCount())
+	}
+}
+
+// TestTelegram_Notify_Subscribers verifies events fan out to matching
+// subscribed chats, and chats that blocked the bot are unsubscribed.
+func TestTelegram_Notify_Subscribers(t *testing.T) {
+	store := memory.New()
+	_ = store.SaveSubscription("telegram:main", domain.Subscription{ID: "10"})
+	_ = store.SaveSubscription("telegram:main", domain.Subscription{ID: "20", Factions: []domain.Enemy{domain.EnemyCyborg}})
+	_ = store.SaveSubscription("telegram:main", domain.Subscription{ID: "30"})
+	// The configured chat is not sent the event twice.
+	_ = store.SaveSubscription("telegram:main", domain.Subscription{ID: "-1"})
+
+	srv := &commandServer{blocked: "30"}
+	n := newCommandNotifier(t, srv, withSubscriptions(store))
+
+	ev := testutil.AttackEventActive()
+	ev.Enemy = domain.EnemyBug
+	if err := n.Notify(domain.EventMessage{Kind: domain.EventKindAttack, Transition: domain.EventTransitionStarted, AttackEvent: &ev}); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+
+	if got := len(srv.sendsTo("-1")); got != 1 {
+		t.Errorf("expected 1 message in the configured chat, got %d", got)
+	}
+	if got := len(srv.sendsTo("10")); got != 1 {
+		t.Errorf("expected 1 message for the unfiltered subscriber, got %d", got)
+	}
+	if got := len(srv.sendsTo("20")); got != 0 {
+		t.Errorf("expected the Cyborg subscriber to be skipped, got %d", got)
+	}
+	subs, _ := store.ListSubscriptions("telegram:main")
+	for _, s := range subs {
+		if s.ID == "30" {
+			t.Error("expected the blocked chat to be unsubscribed")
+		}
+	}
+}
+
+// TestTelegram_Notify_OtherNotifiersSubscribers verifies a notifier neither
+// sends to nor unsubscribes chats that subscribed to another notifier
+// sharing its store.
+func TestTelegram_Notify_OtherNotifiersSubscribers(t *testing.T) {
+	store := memory.New()
+	_ = store.SaveSubscription("telegram:main", domain.Subscription{ID: "10"})
+
+	srv := &commandServer{blocked: "10"}
+	other := newCommandNotifier(t, srv, withSubscriptions(store), func(o *telegram.Options) { o.ID = "other" })
+
+	ev := testutil.AttackEventActive()
+	if err := other.Notify(domain.EventMessage{Kind: domain.EventKindAttack, Transition: domain.EventTransitionStarted, AttackEvent: &ev}); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	if srv.called("sendMessage") != 1 {
+		t.Errorf("expected only the configured chat to be sent the event, got %d sends", srv.called("sendMessage"))
+	}
+	if subs, _ := store.ListSubscriptions("telegram:main"); len(subs) != 1 {
+		t.Errorf("expected the other notifier's subscription to be kept, got %+v", subs)
+	}
+}
+
+// TestTelegram_Notify_SubscribersWhenChatFails verifies subscribed chats get
+// the event even when the configured chat cannot, and the failure is still
+// reported.
+func TestTelegram_Notify_SubscribersWhenChatFails(t *testing.T) {
+	store := memory.New()
+	_ = store.SaveSubscription("telegram:main", domain.Subscription{ID: "10"})
+
+	srv := &commandServer{blocked: "-1"}
+	n := newCommandNotifier(t, srv, withSubscriptions(store))
+
+	ev := testutil.AttackEventActive()
+	if err := n.Notify(domain.EventMessage{Kind: domain.EventKindAttack, Transition: domain.EventTransitionStarted, AttackEvent: &ev}); err == nil {
+		t.Error("expected the configured chat's failure to be returned")
+	}
+	if got := len(srv.sendsTo("10")); got != 1 {
+		t.Errorf("expected 1 message for the subscriber, got %d", got)
+	}
+}
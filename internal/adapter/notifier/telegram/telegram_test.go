@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -180,3 +181,88 @@ func TestTelegram_New_MissingChatID(t *testing.T) {
 		t.Error("expected error for missing chat_id")
 	}
 }
+
+func TestDefaultTemplates_Valid(t *testing.T) {
+	if problems := domain.ValidateTemplates(telegram.DefaultTemplates()); len(problems) != 0 {
+		t.Errorf("default templates have problems: %v", problems)
+	}
+}
+
+func TestLocalizedTemplates_Spanish(t *testing.T) {
+	es := telegram.LocalizedTemplates(domain.LocaleSpanish)
+	if problems := domain.ValidateTemplates(es); len(problems) != 0 {
+		t.Errorf("spanish templates have problems: %v", problems)
+	}
+	if es == telegram.DefaultTemplates() {
+		t.Error("expected spanish templates to differ from english")
+	}
+	if telegram.LocalizedTemplates("xx") != telegram.DefaultTemplates() {
+		t.Error("expected unknown locale to fall back to english")
+	}
+}
+
+// TestTelegram_Notify_SpanishEscapesSeparator verifies that Spanish thousands
+// separators are escaped for MarkdownV2.
+func TestTelegram_Notify_SpanishEscapesSeparator(t *testing.T) {
+	fs, srv := newFakeServer()
+	defer srv.Close()
+
+	n, err := telegram.New(telegram.Options{
+		Token:   "testtoken",
+		ChatID:  "-100",
+		Locale:  domain.LocaleSpanish,
+		APIBase: srv.URL,
+	}, testutil.DiscardLogger())
+	if err != nil {
+		t.Fatalf("failed to create telegram notifier: %v", err)
+	}
+	t.Cleanup(func() { _ = n.Close() })
+
+	err = n.Notify(domain.EventMessage{
+		Kind:           domain.EventKindMilestone,
+		Transition:     domain.EventTransitionReached,
+		MilestoneEvent: &domain.MilestoneEvent{Season: 159, Counter: domain.MilestoneKills, Milestone: 1000000, Value: 1000412},
+	})
+	if err != nil {
+		t.Fatalf("Notify returned error: %v", err)
+	}
+	text, _ := fs.sends[0]["text"].(string)
+	if !strings.Contains(text, `1\.000\.000 bajas`) {
+		t.Errorf("expected escaped spanish milestone, got %q", text)
+	}
+}
+
+// TestTelegram_Notify_Structured verifies that structured messages are escaped
+// for MarkdownV2 and use bold titles.
+func TestTelegram_Notify_Structured(t *testing.T) {
+	fs, srv := newFakeServer()
+	defer srv.Close()
+
+	n, err := telegram.New(telegram.Options{
+		Token:    "testtoken",
+		ChatID:   "-100",
+		Timezone: time.UTC,
+		Style:    domain.MessageStyleStructured,
+		APIBase:  srv.URL,
+	}, testutil.DiscardLogger())
+	if err != nil {
+		t.Fatalf("failed to create telegram notifier: %v", err)
+	}
+	t.Cleanup(func() { _ = n.Close() })
+
+	err = n.Notify(domain.EventMessage{
+		Kind:        domain.EventKindDefend,
+		Transition:  domain.EventTransitionFailed,
+		DefendEvent: testutil.DefendEventActive(),
+	})
+	if err != nil {
+		t.Fatalf("Notify returned error: %v", err)
+	}
+	text, _ := fs.sends[0]["text"].(string)
+	if !strings.HasPrefix(text, `*❌ Super Earth has fallen to the Illuminate\.*`) {
+		t.Errorf("expected escaped bold title, got %q", text)
+	}
+	if !strings.Contains(text, "*Progress:* `") || !strings.HasSuffix(text, "_War 159_") {
+		t.Errorf("expected progress field and footer, got %q", text)
+	}
+}
@@ -0,0 +1,114 @@
+package telegram_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ametis70/hellbot/internal/adapter/notifier/telegram"
+	"github.com/ametis70/hellbot/internal/adapter/store/memory"
+	"github.com/ametis70/hellbot/internal/domain"
+	"github.com/ametis70/hellbot/internal/testutil"
+)
+
+// inTopics posts to topic 5 of the chat, and Bug events to topic 7.
+func inTopics(o *telegram.Options) {
+	o.MessageThreadID = 5
+	o.FactionThreads = map[domain.Enemy]int{domain.EnemyBug: 7}
+}
+
+// threadsTo returns the message_thread_id of each message sent to chatID, 0
+// when unset.
+func (c *commandServer) threadsTo(chatID string) []float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var threads []float64
+	for _, b := range c.bodies {
+		if b["chat_id"] == chatID {
+			id, _ := b["message_thread_id"].(float64)
+			threads = append(threads, id)
+		}
+	}
+	return threads
+}
+
+// TestTelegram_Notify_Topics verifies events go to the faction's topic, or
+// the default topic, and that subscribed chats get them in the topic they
+// subscribed from.
+func TestTelegram_Notify_Topics(t *testing.T) {
+	store := memory.New()
+	_ = store.SaveSubscription("telegram:main", domain.Subscription{ID: "10", ThreadID: 3})
+	srv := &commandServer{}
+	n := newCommandNotifier(t, srv, inTopics, withSubscriptions(store))
+
+	ev := testutil.AttackEventActive()
+	ev.Enemy = domain.EnemyBug
+	if err := n.Notify(domain.EventMessage{Kind: domain.EventKindAttack, Transition: domain.EventTransitionStarted, AttackEvent: &ev}); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	if err := n.Notify(domain.EventMessage{Kind: domain.EventKindWar, Transition: domain.EventTransitionSucceeded, WarEvent: &domain.WarEvent{Season: 50}}); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+
+	if got := srv.threadsTo("-1"); len(got) != 2 || got[0] != 7 || got[1] != 5 {
+		t.Errorf("expected topics [7 5] in the configured chat, got %v", got)
+	}
+	if got := srv.threadsTo("10"); len(got) != 2 || got[0] != 3 || got[1] != 3 {
+		t.Errorf("expected topic 3 for the subscriber, got %v", got)
+	}
+}
+
+// TestTelegram_Command_RepliesInTopic verifies a command sent in a forum
+// topic is answered in that topic.
+func TestTelegram_Command_RepliesInTopic(t *testing.T) {
+	u := groupUpdate(-555, 42, "/help")
+	msg := u["message"].(map[string]any)
+	msg["is_topic_message"] = true
+	msg["message_thread_id"] = 9
+	srv := &commandServer{updates: []map[string]any{u}}
+	newCommandNotifier(t, srv)
+	waitSend(t, srv)
+
+	if got := srv.threadsTo("-555"); len(got) != 1 || got[0] != 9 {
+		t.Errorf("expected a reply in topic 9, got %v", got)
+	}
+}
+
+// TestTelegram_Subscribe_InTopic verifies /subscribe sent in a forum topic
+// records that topic.
+func TestTelegram_Subscribe_InTopic(t *testing.T) {
+	u := groupUpdate(-555, 42, "/subscribe")
+	msg := u["message"].(map[string]any)
+	msg["is_topic_message"] = true
+	msg["message_thread_id"] = 9
+	store := memory.New()
+	srv := &commandServer{updates: []map[string]any{u}, member: "administrator"}
+	newCommandNotifier(t, srv, withSubscriptions(store))
+	waitSend(t, srv)
+
+	subs, _ := store.ListSubscriptions("telegram:main")
+	if len(subs) != 1 || subs[0].ID != "-555" || subs[0].ThreadID != 9 {
+		t.Errorf("expected chat -555 subscribed in topic 9, got %+v", subs)
+	}
+}
+
+// TestTelegram_ChannelPost_Command verifies commands posted in a channel are
+// handled, including admin-only ones, since only admins post there.
+func TestTelegram_ChannelPost_Command(t *testing.T) {
+	post := botUpdate("/test", "bot_command")["message"].(map[string]any)
+	post["chat"] = map[string]any{"id": -200, "type": "channel"}
+	post["sender_chat"] = map[string]any{"id": -200, "type": "channel"}
+	srv := &commandServer{
+		updates: []map[string]any{{"update_id": 1, "channel_post": post}},
+	}
+	newCommandNotifier(t, srv)
+	waitSend(t, srv)
+
+	if got := srv.sendsTo("-200"); len(got) != 1 || strings.HasPrefix(got[0], "⛔") {
+		t.Errorf("expected the test message in the channel, got %v", got)
+	}
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	if len(srv.members) != 0 {
+		t.Errorf("expected no getChatMember calls, got %v", srv.members)
+	}
+}
@@ -0,0 +1,148 @@
+package telegram_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ametis70/hellbot/internal/adapter/notifier/telegram"
+	"github.com/ametis70/hellbot/internal/testutil"
+)
+
+// withWebhook receives updates through a webhook instead of polling.
+func withWebhook(o *telegram.Options) {
+	o.Webhook = &telegram.Webhook{URL: "https://bot.example.com/telegram/hook", SecretToken: "s3cret"}
+}
+
+// postUpdate serves an update through the notifier's webhook route.
+func postUpdate(t *testing.T, n *telegram.Notifier, secret string, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	pattern, handler := n.HTTPRoute()
+	mux := http.NewServeMux()
+	mux.Handle(pattern, handler)
+	req := httptest.NewRequest(http.MethodPost, "/telegram/hook", strings.NewReader(body))
+	if secret != "" {
+		req.Header.Set("X-Telegram-Bot-Api-Secret-Token", secret)
+	}
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	return rec
+}
+
+// TestTelegram_Webhook_Lifecycle verifies the webhook is set at startup with
+// the secret token, getUpdates is never called, and Close deletes it.
+func TestTelegram_Webhook_Lifecycle(t *testing.T) {
+	api := &commandServer{}
+	n := newCommandNotifier(t, api, withWebhook)
+
+	api.mu.Lock()
+	webhook := api.webhook
+	api.mu.Unlock()
+	if webhook["url"] != "https://bot.example.com/telegram/hook" || webhook["secret_token"] != "s3cret" {
+		t.Errorf("unexpected setWebhook params %v", webhook)
+	}
+	if pattern, _ := n.HTTPRoute(); pattern != "POST /telegram/hook" {
+		t.Errorf("unexpected route %q", pattern)
+	}
+
+	if err := n.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if api.called("deleteWebhook") != 1 {
+		t.Error("expected the webhook to be deleted on Close")
+	}
+	if api.called("getUpdates") != 0 {
+		t.Error("expected no getUpdates calls in webhook mode")
+	}
+}
+
+// TestTelegram_Webhook_HandlesUpdate verifies a posted update with the
+// secret token runs the command.
+func TestTelegram_Webhook_HandlesUpdate(t *testing.T) {
+	api := &commandServer{}
+	n := newCommandNotifier(t, api, withWebhook)
+
+	body, _ := json.Marshal(botUpdate("/test", "bot_command"))
+	if rec := postUpdate(t, n, "s3cret", string(body)); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	waitSend(t, api)
+}
+
+// TestTelegram_Webhook_AcknowledgesBeforeHandling verifies Telegram gets its
+// 200 while the update is still being handled.
+func TestTelegram_Webhook_AcknowledgesBeforeHandling(t *testing.T) {
+	api := &commandServer{hold: make(chan struct{})}
+	n := newCommandNotifier(t, api, withWebhook)
+
+	body, _ := json.Marshal(botUpdate("/test", "bot_command"))
+	acked := make(chan int)
+	go func() { acked <- postUpdate(t, n, "s3cret", string(body)).Code }()
+	select {
+	case code := <-acked:
+		if code != http.StatusOK {
+			t.Errorf("expected 200, got %d", code)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the update to be acknowledged before the reply is sent")
+	}
+	close(api.hold)
+	waitSend(t, api)
+}
+
+// TestTelegram_Webhook_RejectsWrongSecret verifies updates without the
+// secret token are ignored.
+func TestTelegram_Webhook_RejectsWrongSecret(t *testing.T) {
+	api := &commandServer{}
+	n := newCommandNotifier(t, api, withWebhook)
+
+	body, _ := json.Marshal(botUpdate("/test", "bot_command"))
+	for _, secret := range []string{"", "wrong"} {
+		if rec := postUpdate(t, n, secret, string(body)); rec.Code != http.StatusUnauthorized {
+			t.Errorf("secret %q: expected 401, got %d", secret, rec.Code)
+		}
+	}
+	if api.called("sendMessage") != 0 {
+		t.Error("expected no messages for rejected updates")
+	}
+}
+
+// TestTelegram_Webhook_BadBody verifies malformed updates are rejected.
+func TestTelegram_Webhook_BadBody(t *testing.T) {
+	api := &commandServer{}
+	n := newCommandNotifier(t, api, withWebhook)
+
+	if rec := postUpdate(t, n, "s3cret", "{"); rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", rec.Code)
+	}
+}
+
+// TestTelegram_Webhook_SetFails verifies New fails when Telegram refuses the
+// webhook.
+func TestTelegram_Webhook_SetFails(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]any{"ok": false, "description": "Bad Request: bad webhook: HTTPS url must be provided for webhook"})
+	}))
+	defer ts.Close()
+	_, err := telegram.New(telegram.Options{
+		Token:   "tok",
+		ChatID:  "-1",
+		Webhook: &telegram.Webhook{URL: "https://bot.example.com/hook", SecretToken: "s3cret"},
+		APIBase: ts.URL,
+	}, testutil.DiscardLogger())
+	if err == nil || !strings.Contains(err.Error(), "HTTPS url must be provided") {
+		t.Errorf("expected Telegram's error, got %v", err)
+	}
+}
+
+// TestTelegram_Polling_NoRoute verifies polling mode serves nothing.
+func TestTelegram_Polling_NoRoute(t *testing.T) {
+	n := newCommandNotifier(t, &commandServer{})
+	if pattern, handler := n.HTTPRoute(); pattern != "" || handler != nil {
+		t.Errorf("expected no route, got %q", pattern)
+	}
+}
@@ -21,6 +21,41 @@ func DefaultTemplates() domain.Templates {
 		AttackFailed:              "❌ *Attack failed\\! The {FACTION} defended their homeworld\\.*",
 		WarWon:                    "🏆 *Managed Democracy prevails\\! All enemies have been crushed and freedom spreads across the galaxy\\. \\(War {SEASON}\\)*",
 		WarLost:                   "💀 *The war is lost\\. Super Earth has fallen\\. \\(War {SEASON}\\)*",
+		PlayersAboveThreshold:     "📈 *{PLAYERS} helldivers are now online — over {THRESHOLD}\\!*",
+		PlayersBelowThreshold:     "📉 *Only {PLAYERS} helldivers remain online — under {THRESHOLD}\\.*",
+		PlayersSurge:              "📈 *Helldivers are rallying\\! {PLAYERS} online, up {CHANGE_PERCENT}% in {WINDOW}\\.*",
+		PlayersDrop:               "📉 *Helldiver numbers are dropping\\. {PLAYERS} online, down {CHANGE_PERCENT}% in {WINDOW}\\.*",
+		MilestoneReached:          "🎖️ *Milestone reached\\! {MILESTONE} {COUNTER} in War {SEASON}\\.*",
+	}
+}
+
+// LocalizedTemplates returns the default templates for the locale, falling
+// back to English when there is no translation.
+func LocalizedTemplates(locale domain.Locale) domain.Templates {
+	if locale == domain.LocaleSpanish {
+		return spanishTemplates()
+	}
+	return DefaultTemplates()
+}
+
+func spanishTemplates() domain.Templates {
+	return domain.Templates{
+		DefendRegionStarted:       "⚔️ *¡Los {FACTION} atacan {REGION_NAME} \\({REGION_NUMBER}/{TOTAL_REGIONS}\\)\\!*\nFin: {END_TIME_FORMATTED}",
+		DefendSuperEarthStarted:   "🚨 *¡Los {FACTION} atacan la Súper Tierra\\!*\nFin: {END_TIME_FORMATTED}",
+		DefendRegionSucceeded:     "✅ *¡{REGION_NAME} \\({REGION_NUMBER}/{TOTAL_REGIONS}\\) resistió el ataque de los {FACTION}\\!*",
+		DefendSuperEarthSucceeded: "✅ *¡La Súper Tierra resistió el ataque de los {FACTION}\\!*",
+		DefendRegionFailed:        "❌ *{REGION_NAME} \\({REGION_NUMBER}/{TOTAL_REGIONS}\\) cayó ante los {FACTION}\\.*",
+		DefendSuperEarthFailed:    "❌ *La Súper Tierra cayó ante los {FACTION}\\.*",
+		AttackHomeworldStarted:    "🚀 *¡Comenzó el ataque al planeta natal de los {FACTION}\\!*\nFin: {END_TIME_FORMATTED}",
+		AttackSucceeded:           "✅ *¡Ataque exitoso\\! Los {FACTION} fueron derrotados\\.*",
+		AttackFailed:              "❌ *¡Ataque fallido\\! Los {FACTION} defendieron su planeta natal\\.*",
+		WarWon:                    "🏆 *¡La Democracia Gestionada prevalece\\! Todos los enemigos fueron aplastados y la libertad se extiende por la galaxia\\. \\(Guerra {SEASON}\\)*",
+		WarLost:                   "💀 *La guerra está perdida\\. La Súper Tierra ha caído\\. \\(Guerra {SEASON}\\)*",
+		PlayersAboveThreshold:     "📈 *¡Ya hay {PLAYERS} helldivers en línea, más de {THRESHOLD}\\!*",
+		PlayersBelowThreshold:     "📉 *Solo quedan {PLAYERS} helldivers en línea, menos de {THRESHOLD}\\.*",
+		PlayersSurge:              "📈 *¡Los helldivers se reagrupan\\! {PLAYERS} en línea, {CHANGE_PERCENT}% más en {WINDOW}\\.*",
+		PlayersDrop:               "📉 *Cada vez hay menos helldivers\\. {PLAYERS} en línea, {CHANGE_PERCENT}% menos en {WINDOW}\\.*",
+		MilestoneReached:          "🎖️ *¡Hito alcanzado\\! {MILESTONE} {COUNTER} en la Guerra {SEASON}\\.*",
 	}
 }
 
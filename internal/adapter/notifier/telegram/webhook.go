@@ -0,0 +1,101 @@
+package telegram
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// secretTokenHeader carries the webhook's secret token on every update.
+const secretTokenHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+// maxUpdateSize bounds the body of a webhook request.
+const maxUpdateSize = 1 << 20
+
+// webhookQueueSize is how many acknowledged updates may wait to be handled.
+const webhookQueueSize = 100
+
+// allowedUpdates are the update types hellbot handles. Telegram remembers
+// the last list it was given, so it is always sent in full.
+var allowedUpdates = []string{"message", "channel_post", "callback_query"}
+
+// Webhook receives updates through hellbot's HTTP server instead of long
+// polling. Telegram posts to URL, which must be HTTPS and reach the path
+// returned by HTTPRoute, and sends SecretToken with every update.
+type Webhook struct {
+	URL         string
+	SecretToken string
+}
+
+// setWebhook registers the webhook with Telegram.
+func (n *Notifier) setWebhook() error {
+	type params struct {
+		URL            string   `json:"url"`
+		SecretToken    string   `json:"secret_token"`
+		AllowedUpdates []string `json:"allowed_updates"`
+	}
+	return n.call("setWebhook", params{
+		URL:            n.opts.Webhook.URL,
+		SecretToken:    n.opts.Webhook.SecretToken,
+		AllowedUpdates: allowedUpdates,
+	})
+}
+
+// deleteWebhook removes the webhook so another instance, or long polling,
+// can take over.
+func (n *Notifier) deleteWebhook() error {
+	return n.call("deleteWebhook", struct{}{})
+}
+
+// HTTPRoute implements port.HTTPRouter. It serves the webhook URL's path in
+// webhook mode and nothing otherwise.
+func (n *Notifier) HTTPRoute() (string, http.Handler) {
+	if n.opts.Webhook == nil {
+		return "", nil
+	}
+	path := "/"
+	if u, err := url.Parse(n.opts.Webhook.URL); err == nil && u.Path != "" {
+		path = u.Path
+	}
+	return "POST " + path, http.HandlerFunc(n.serveWebhook)
+}
+
+// serveWebhook acknowledges an update posted by Telegram and queues it, so
+// that slow commands do not make Telegram time out and resend it. Requests
+// without the secret token are rejected.
+func (n *Notifier) serveWebhook(w http.ResponseWriter, r *http.Request) {
+	token := r.Header.Get(secretTokenHeader)
+	if subtle.ConstantTimeCompare([]byte(token), []byte(n.opts.Webhook.SecretToken)) != 1 {
+		n.logger.Warn("telegram notifier: webhook request with a wrong secret token", "remote", r.RemoteAddr)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var u update
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxUpdateSize)).Decode(&u); err != nil {
+		http.Error(w, fmt.Sprintf("decoding update: %v", err), http.StatusBadRequest)
+		return
+	}
+	select {
+	case n.updates <- u:
+	default:
+		n.logger.Warn("telegram notifier: webhook queue full, dropping update", "update", u.UpdateID)
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// handleWebhookUpdates handles queued updates in order until ctx is done.
+func (n *Notifier) handleWebhookUpdates(ctx context.Context) {
+	defer close(n.done)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case u := <-n.updates:
+			n.handleUpdate(u)
+		}
+	}
+}
@@ -50,11 +50,13 @@ func New(opts Options, logger *slog.Logger) (*Notifier, error) {
 
 // Payload is the JSON body sent to the webhook endpoint for every event.
 type Payload struct {
-	Kind        string       `json:"kind"`
-	Transition  string       `json:"transition"`
-	DefendEvent *DefendEvent `json:"defend_event,omitempty"`
-	AttackEvent *AttackEvent `json:"attack_event,omitempty"`
-	WarEvent    *WarEvent    `json:"war_event,omitempty"`
+	Kind           string          `json:"kind"`
+	Transition     string          `json:"transition"`
+	DefendEvent    *DefendEvent    `json:"defend_event,omitempty"`
+	AttackEvent    *AttackEvent    `json:"attack_event,omitempty"`
+	WarEvent       *WarEvent       `json:"war_event,omitempty"`
+	PlayersEvent   *PlayersEvent   `json:"players_event,omitempty"`
+	MilestoneEvent *MilestoneEvent `json:"milestone_event,omitempty"`
 }
 
 type DefendEvent struct {
@@ -62,6 +64,7 @@ type DefendEvent struct {
 	ID             int    `json:"id"`
 	Region         int    `json:"region"`
 	RegionName     string `json:"region_name"`
+	RegionCapital  string `json:"region_capital"`
 	Enemy          string `json:"enemy"`
 	PointsMax      int    `json:"points_max"`
 	Points         int    `json:"points"`
@@ -76,6 +79,9 @@ type DefendEvent struct {
 type AttackEvent struct {
 	Season         int    `json:"season"`
 	ID             int    `json:"id"`
+	MaxEventID     int    `json:"max_event_id"`
+	RegionName     string `json:"region_name"`
+	RegionCapital  string `json:"region_capital"`
 	Enemy          string `json:"enemy"`
 	PointsMax      int    `json:"points_max"`
 	Points         int    `json:"points"`
@@ -88,17 +94,37 @@ type AttackEvent struct {
 }
 
 type WarEvent struct {
-	Season int `json:"season"`
+	Season          int   `json:"season"`
+	DurationSeconds int64 `json:"duration_seconds,omitempty"`
+}
+
+type PlayersEvent struct {
+	Season          int    `json:"season"`
+	Trigger         string `json:"trigger"`
+	Players         int    `json:"players"`
+	PreviousPlayers int    `json:"previous_players"`
+	Threshold       int    `json:"threshold,omitempty"`
+	ChangePercent   int    `json:"change_percent"`
+	WindowSeconds   int64  `json:"window_seconds,omitempty"`
+}
+
+type MilestoneEvent struct {
+	Season    int    `json:"season"`
+	Counter   string `json:"counter"`
+	Milestone int    `json:"milestone"`
+	Value     int    `json:"value"`
 }
 
 // ── domain → payload mappers ─────────────────────────────────────────────────
 
 func toDefendEvent(e *domain.DefendEvent) *DefendEvent {
+	region := domain.GetRegion(e.Enemy, e.Region)
 	return &DefendEvent{
 		Season:         e.Season,
 		ID:             e.ID,
 		Region:         e.Region,
-		RegionName:     domain.GetRegion(e.Enemy, e.Region).Name,
+		RegionName:     region.Name,
+		RegionCapital:  region.Capital,
 		Enemy:          e.Enemy.String(),
 		PointsMax:      e.PointsMax,
 		Points:         e.Points,
@@ -112,9 +138,13 @@ func toDefendEvent(e *domain.DefendEvent) *DefendEvent {
 }
 
 func toAttackEvent(e *domain.AttackEvent) *AttackEvent {
+	region := domain.GetRegion(e.Enemy, domain.HomeWorldRegion)
 	return &AttackEvent{
 		Season:         e.Season,
 		ID:             e.ID,
+		MaxEventID:     e.MaxEventID,
+		RegionName:     region.Name,
+		RegionCapital:  region.Capital,
 		Enemy:          e.Enemy.String(),
 		PointsMax:      e.PointsMax,
 		Points:         e.Points,
@@ -139,7 +169,31 @@ func buildPayload(msg domain.EventMessage) Payload {
 		p.AttackEvent = toAttackEvent(msg.AttackEvent)
 	}
 	if msg.WarEvent != nil {
-		p.WarEvent = &WarEvent{Season: msg.WarEvent.Season}
+		p.WarEvent = &WarEvent{
+			Season:          msg.WarEvent.Season,
+			DurationSeconds: int64(msg.WarEvent.Duration.Seconds()),
+		}
+	}
+	if msg.PlayersEvent != nil {
+		e := msg.PlayersEvent
+		p.PlayersEvent = &PlayersEvent{
+			Season:          e.Season,
+			Trigger:         string(e.Trigger),
+			Players:         e.Players,
+			PreviousPlayers: e.Previous,
+			Threshold:       e.Threshold,
+			ChangePercent:   e.ChangePercent,
+			WindowSeconds:   int64(e.Window.Seconds()),
+		}
+	}
+	if msg.MilestoneEvent != nil {
+		e := msg.MilestoneEvent
+		p.MilestoneEvent = &MilestoneEvent{
+			Season:    e.Season,
+			Counter:   string(e.Counter),
+			Milestone: e.Milestone,
+			Value:     e.Value,
+		}
 	}
 	return p
 }
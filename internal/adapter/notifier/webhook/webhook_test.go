@@ -83,6 +83,12 @@ func TestWebhook_PostsJSON(t *testing.T) {
 	if payload.AttackEvent.ID != testutil.AttackEventActive().ID {
 		t.Errorf("expected attack event ID %d, got %d", testutil.AttackEventActive().ID, payload.AttackEvent.ID)
 	}
+	if payload.AttackEvent.MaxEventID != testutil.AttackEventActive().MaxEventID {
+		t.Errorf("expected max event ID %d, got %d", testutil.AttackEventActive().MaxEventID, payload.AttackEvent.MaxEventID)
+	}
+	if payload.AttackEvent.RegionName != "Cyberstan Region" || payload.AttackEvent.RegionCapital != "Cyberstan" {
+		t.Errorf("expected homeworld region, got %q / %q", payload.AttackEvent.RegionName, payload.AttackEvent.RegionCapital)
+	}
 	if payload.DefendEvent != nil {
 		t.Error("expected defend_event to be nil")
 	}
@@ -184,6 +190,44 @@ func TestWebhook_DefendEventPayload(t *testing.T) {
 	}
 }
 
+// TestWebhook_PlayersEventPayload verifies player alerts are serialised.
+func TestWebhook_PlayersEventPayload(t *testing.T) {
+	capture, srv := newCapture(http.StatusOK)
+	defer srv.Close()
+
+	n := newNotifier(t, srv.URL)
+	_ = n.Notify(domain.EventMessage{
+		Kind:       domain.EventKindPlayers,
+		Transition: domain.EventTransitionRose,
+		PlayersEvent: &domain.PlayersEvent{
+			Season:        159,
+			Trigger:       domain.PlayersTriggerChange,
+			Players:       1500,
+			Previous:      1000,
+			ChangePercent: 50,
+			Window:        time.Hour,
+		},
+	})
+
+	var payload webhook.Payload
+	if err := json.Unmarshal(capture.body, &payload); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if payload.Kind != "players" || payload.Transition != "rose" {
+		t.Errorf("unexpected kind/transition: %q/%q", payload.Kind, payload.Transition)
+	}
+	pe := payload.PlayersEvent
+	if pe == nil {
+		t.Fatal("expected players_event to be set")
+	}
+	if pe.Trigger != "change" || pe.Players != 1500 || pe.PreviousPlayers != 1000 || pe.ChangePercent != 50 {
+		t.Errorf("unexpected players_event: %+v", pe)
+	}
+	if pe.WindowSeconds != 3600 {
+		t.Errorf("window_seconds: want 3600, got %d", pe.WindowSeconds)
+	}
+}
+
 // TestWebhook_URLRequired verifies that New returns an error when URL is empty.
 func TestWebhook_URLRequired(t *testing.T) {
 	_, err := webhook.New(webhook.Options{}, testutil.DiscardLogger())
@@ -3,20 +3,29 @@ package memory
 import (
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"sync"
 
 	"github.com/ametis70/hellbot/internal/domain"
 )
 
 type MemoryStore struct {
-	mu       sync.RWMutex
-	campaign *domain.CampaignStatus
-	events   map[string]*domain.OngoingEvent
+	mu         sync.RWMutex
+	campaign   *domain.CampaignStatus
+	events     map[string]*domain.OngoingEvent
+	milestones map[string]struct{}
+	refs       map[string]string
+	// subs maps scope to recipient ID to subscription.
+	subs map[string]map[string]domain.Subscription
 }
 
 func New() *MemoryStore {
 	return &MemoryStore{
-		events: make(map[string]*domain.OngoingEvent, 4),
+		events:     make(map[string]*domain.OngoingEvent, 4),
+		milestones: make(map[string]struct{}),
+		refs:       make(map[string]string),
+		subs:       make(map[string]map[string]domain.Subscription),
 	}
 }
 
@@ -81,3 +90,62 @@ func (s *MemoryStore) ListOngoingEvents(kind domain.EventKind) ([]*domain.Ongoin
 
 	return result, nil
 }
+
+func milestoneKey(season int, counter domain.MilestoneCounter, milestone int) string {
+	return fmt.Sprintf("%d:%s:%d", season, counter, milestone)
+}
+
+func (s *MemoryStore) SaveMilestone(season int, counter domain.MilestoneCounter, milestone int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.milestones[milestoneKey(season, counter, milestone)] = struct{}{}
+	return nil
+}
+
+func (s *MemoryStore) HasMilestone(season int, counter domain.MilestoneCounter, milestone int) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.milestones[milestoneKey(season, counter, milestone)]
+	return ok, nil
+}
+
+func (s *MemoryStore) SaveMessageRef(key, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.refs[key] = id
+	return nil
+}
+
+func (s *MemoryStore) GetMessageRef(key string) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.refs[key], nil
+}
+
+func (s *MemoryStore) SaveSubscription(scope string, sub domain.Subscription) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.subs[scope] == nil {
+		s.subs[scope] = make(map[string]domain.Subscription)
+	}
+	s.subs[scope][sub.ID] = sub
+	return nil
+}
+
+func (s *MemoryStore) RemoveSubscription(scope, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.subs[scope], id)
+	return nil
+}
+
+func (s *MemoryStore) ListSubscriptions(scope string) ([]domain.Subscription, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	result := make([]domain.Subscription, 0, len(s.subs[scope]))
+	for _, sub := range s.subs[scope] {
+		result = append(result, sub)
+	}
+	slices.SortFunc(result, func(a, b domain.Subscription) int { return strings.Compare(a.ID, b.ID) })
+	return result, nil
+}
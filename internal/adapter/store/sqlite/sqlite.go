@@ -22,9 +22,29 @@ CREATE TABLE IF NOT EXISTS ongoing_events (
 	kind TEXT    NOT NULL,
 	PRIMARY KEY (id, kind)
 );
+
+CREATE TABLE IF NOT EXISTS milestones (
+	season    INTEGER NOT NULL,
+	counter   TEXT    NOT NULL,
+	milestone INTEGER NOT NULL,
+	PRIMARY KEY (season, counter, milestone)
+);
+
+CREATE TABLE IF NOT EXISTS message_refs (
+	key TEXT PRIMARY KEY,
+	id  TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS subscriptions (
+	scope   TEXT NOT NULL,
+	id      TEXT NOT NULL,
+	payload TEXT NOT NULL,
+	PRIMARY KEY (scope, id)
+);
 `
 
-// Store implements port.CampaignStore and port.EventStore using a SQLite database.
+// Store implements port.CampaignStore, port.EventStore, port.MilestoneStore,
+// port.MessageRefStore and port.SubscriptionStore using a SQLite database.
 type Store struct {
 	db *sql.DB
 }
@@ -168,3 +188,106 @@ func (s *Store) ListOngoingEvents(kind domain.EventKind) (_ []*domain.OngoingEve
 	}
 	return result, nil
 }
+
+// ── MilestoneStore ───────────────────────────────────────────────────────────
+
+func (s *Store) SaveMilestone(season int, counter domain.MilestoneCounter, milestone int) error {
+	_, err := s.db.Exec(
+		`INSERT OR IGNORE INTO milestones (season, counter, milestone) VALUES (?, ?, ?)`,
+		season, string(counter), milestone,
+	)
+	if err != nil {
+		return fmt.Errorf("sqlite: save milestone: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) HasMilestone(season int, counter domain.MilestoneCounter, milestone int) (bool, error) {
+	var n int
+	err := s.db.QueryRow(
+		`SELECT COUNT(*) FROM milestones WHERE season = ? AND counter = ? AND milestone = ?`,
+		season, string(counter), milestone,
+	).Scan(&n)
+	if err != nil {
+		return false, fmt.Errorf("sqlite: get milestone: %w", err)
+	}
+	return n > 0, nil
+}
+
+// ── MessageRefStore ──────────────────────────────────────────────────────────
+
+func (s *Store) SaveMessageRef(key, id string) error {
+	_, err := s.db.Exec(
+		`INSERT INTO message_refs (key, id) VALUES (?, ?) ON CONFLICT(key) DO UPDATE SET id = excluded.id`,
+		key, id,
+	)
+	if err != nil {
+		return fmt.Errorf("sqlite: save message ref: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) GetMessageRef(key string) (string, error) {
+	var id string
+	err := s.db.QueryRow(`SELECT id FROM message_refs WHERE key = ?`, key).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("sqlite: get message ref: %w", err)
+	}
+	return id, nil
+}
+
+// ── SubscriptionStore ────────────────────────────────────────────────────────
+
+func (s *Store) SaveSubscription(scope string, sub domain.Subscription) error {
+	data, err := json.Marshal(sub)
+	if err != nil {
+		return fmt.Errorf("sqlite: marshal subscription: %w", err)
+	}
+	_, err = s.db.Exec(
+		`INSERT INTO subscriptions (scope, id, payload) VALUES (?, ?, ?) ON CONFLICT(scope, id) DO UPDATE SET payload = excluded.payload`,
+		scope, sub.ID, string(data),
+	)
+	if err != nil {
+		return fmt.Errorf("sqlite: save subscription: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) RemoveSubscription(scope, id string) error {
+	if _, err := s.db.Exec(`DELETE FROM subscriptions WHERE scope = ? AND id = ?`, scope, id); err != nil {
+		return fmt.Errorf("sqlite: remove subscription: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) ListSubscriptions(scope string) (_ []domain.Subscription, err error) {
+	rows, err := s.db.Query(`SELECT payload FROM subscriptions WHERE scope = ? ORDER BY id`, scope)
+	if err != nil {
+		return nil, fmt.Errorf("sqlite: list subscriptions: %w", err)
+	}
+	defer func() {
+		if cerr := rows.Close(); cerr != nil && err == nil {
+			err = fmt.Errorf("sqlite: close rows: %w", cerr)
+		}
+	}()
+
+	result := make([]domain.Subscription, 0)
+	for rows.Next() {
+		var payload string
+		if err := rows.Scan(&payload); err != nil {
+			return nil, fmt.Errorf("sqlite: scan subscription: %w", err)
+		}
+		var sub domain.Subscription
+		if err := json.Unmarshal([]byte(payload), &sub); err != nil {
+			return nil, fmt.Errorf("sqlite: unmarshal subscription: %w", err)
+		}
+		result = append(result, sub)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("sqlite: list subscriptions rows: %w", err)
+	}
+	return result, nil
+}
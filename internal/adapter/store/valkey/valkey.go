@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
+	"slices"
 
 	"github.com/redis/go-redis/v9"
 
@@ -15,9 +17,17 @@ const (
 	campaignKey    = "hellbot:campaign"
 	eventsSetKey   = "hellbot:events"
 	eventKeyPrefix = "hellbot:event:"
+	// milestonesKeyPrefix is followed by the season; each key is a set of
+	// "counter:milestone" members.
+	milestonesKeyPrefix = "hellbot:milestones:"
+	messageRefKeyPrefix = "hellbot:message:"
+	// subscriptionsKeyPrefix is followed by the scope; each key is a hash of
+	// recipient ID to JSON subscription.
+	subscriptionsKeyPrefix = "hellbot:subscriptions:"
 )
 
-// Store implements port.CampaignStore and port.EventStore using a Redis/Valkey backend.
+// Store implements port.CampaignStore, port.EventStore, port.MilestoneStore,
+// port.MessageRefStore and port.SubscriptionStore using a Redis/Valkey backend.
 type Store struct {
 	client *redis.Client
 }
@@ -164,3 +174,86 @@ func (s *Store) ListOngoingEvents(kind domain.EventKind) ([]*domain.OngoingEvent
 	}
 	return result, nil
 }
+
+// ── MilestoneStore ───────────────────────────────────────────────────────────
+
+func milestonesKey(season int) string {
+	return fmt.Sprintf("%s%d", milestonesKeyPrefix, season)
+}
+
+func milestoneMember(counter domain.MilestoneCounter, milestone int) string {
+	return fmt.Sprintf("%s:%d", counter, milestone)
+}
+
+func (s *Store) SaveMilestone(season int, counter domain.MilestoneCounter, milestone int) error {
+	err := s.client.SAdd(context.Background(), milestonesKey(season), milestoneMember(counter, milestone)).Err()
+	if err != nil {
+		return fmt.Errorf("valkey: save milestone: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) HasMilestone(season int, counter domain.MilestoneCounter, milestone int) (bool, error) {
+	ok, err := s.client.SIsMember(context.Background(), milestonesKey(season), milestoneMember(counter, milestone)).Result()
+	if err != nil {
+		return false, fmt.Errorf("valkey: get milestone: %w", err)
+	}
+	return ok, nil
+}
+
+// ── MessageRefStore ──────────────────────────────────────────────────────────
+
+func (s *Store) SaveMessageRef(key, id string) error {
+	if err := s.client.Set(context.Background(), messageRefKeyPrefix+key, id, 0).Err(); err != nil {
+		return fmt.Errorf("valkey: save message ref: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) GetMessageRef(key string) (string, error) {
+	id, err := s.client.Get(context.Background(), messageRefKeyPrefix+key).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("valkey: get message ref: %w", err)
+	}
+	return id, nil
+}
+
+// ── SubscriptionStore ────────────────────────────────────────────────────────
+
+func (s *Store) SaveSubscription(scope string, sub domain.Subscription) error {
+	data, err := json.Marshal(sub)
+	if err != nil {
+		return fmt.Errorf("valkey: marshal subscription: %w", err)
+	}
+	if err := s.client.HSet(context.Background(), subscriptionsKeyPrefix+scope, sub.ID, data).Err(); err != nil {
+		return fmt.Errorf("valkey: save subscription: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) RemoveSubscription(scope, id string) error {
+	if err := s.client.HDel(context.Background(), subscriptionsKeyPrefix+scope, id).Err(); err != nil {
+		return fmt.Errorf("valkey: remove subscription: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) ListSubscriptions(scope string) ([]domain.Subscription, error) {
+	entries, err := s.client.HGetAll(context.Background(), subscriptionsKeyPrefix+scope).Result()
+	if err != nil {
+		return nil, fmt.Errorf("valkey: list subscriptions: %w", err)
+	}
+
+	result := make([]domain.Subscription, 0, len(entries))
+	for _, id := range slices.Sorted(maps.Keys(entries)) {
+		var sub domain.Subscription
+		if err := json.Unmarshal([]byte(entries[id]), &sub); err != nil {
+			return nil, fmt.Errorf("valkey: unmarshal subscription %q: %w", id, err)
+		}
+		result = append(result, sub)
+	}
+	return result, nil
+}
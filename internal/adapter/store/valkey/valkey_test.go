@@ -1,6 +1,7 @@
 package valkey_test
 
 import (
+	"slices"
 	"testing"
 
 	"github.com/alicebob/miniredis/v2"
@@ -187,3 +188,85 @@ func TestValkey_ListOngoingEvents_StaleKeyCleanup(t *testing.T) {
 		t.Errorf("expected 0 events after stale key skipped, got %d", len(events))
 	}
 }
+
+// --- MilestoneStore ---
+
+func TestValkey_SaveAndHasMilestone(t *testing.T) {
+	s := newStore(t)
+	if has, err := s.HasMilestone(159, domain.MilestoneKills, 1000); err != nil || has {
+		t.Fatalf("expected unknown milestone, got has=%v err=%v", has, err)
+	}
+	if err := s.SaveMilestone(159, domain.MilestoneKills, 1000); err != nil {
+		t.Fatalf("SaveMilestone: %v", err)
+	}
+	if err := s.SaveMilestone(159, domain.MilestoneKills, 1000); err != nil {
+		t.Errorf("expected idempotent save, got error: %v", err)
+	}
+	if has, err := s.HasMilestone(159, domain.MilestoneKills, 1000); err != nil || !has {
+		t.Errorf("expected milestone to be recorded, got has=%v err=%v", has, err)
+	}
+	if has, _ := s.HasMilestone(160, domain.MilestoneKills, 1000); has {
+		t.Error("expected milestones to be scoped per season")
+	}
+	if has, _ := s.HasMilestone(159, domain.MilestoneDeaths, 1000); has {
+		t.Error("expected milestones to be scoped per counter")
+	}
+}
+
+// --- MessageRefStore ---
+
+func TestValkey_SaveAndGetMessageRef(t *testing.T) {
+	s := newStore(t)
+	if id, err := s.GetMessageRef("discord:board:1"); err != nil || id != "" {
+		t.Fatalf("expected no ref, got id=%q err=%v", id, err)
+	}
+	if err := s.SaveMessageRef("discord:board:1", "100"); err != nil {
+		t.Fatalf("SaveMessageRef: %v", err)
+	}
+	if err := s.SaveMessageRef("discord:board:1", "200"); err != nil {
+		t.Fatalf("SaveMessageRef overwrite: %v", err)
+	}
+	if id, err := s.GetMessageRef("discord:board:1"); err != nil || id != "200" {
+		t.Errorf("expected latest ref 200, got id=%q err=%v", id, err)
+	}
+	if id, _ := s.GetMessageRef("discord:board:2"); id != "" {
+		t.Errorf("expected refs to be scoped per key, got %q", id)
+	}
+}
+
+// --- SubscriptionStore ---
+
+func TestValkey_Subscriptions(t *testing.T) {
+	s := newStore(t)
+	bugs := domain.Subscription{ID: "2", Factions: []domain.Enemy{domain.EnemyBug}, Kinds: []domain.EventKind{domain.EventKindAttack}}
+	if err := s.SaveSubscription("discord", bugs); err != nil {
+		t.Fatalf("SaveSubscription: %v", err)
+	}
+	if err := s.SaveSubscription("discord", domain.Subscription{ID: "1"}); err != nil {
+		t.Fatalf("SaveSubscription: %v", err)
+	}
+	if err := s.SaveSubscription("telegram", domain.Subscription{ID: "3"}); err != nil {
+		t.Fatalf("SaveSubscription: %v", err)
+	}
+
+	subs, err := s.ListSubscriptions("discord")
+	if err != nil {
+		t.Fatalf("ListSubscriptions: %v", err)
+	}
+	if len(subs) != 2 || subs[0].ID != "1" || subs[1].ID != "2" {
+		t.Fatalf("expected subscriptions 1 and 2 in order, got %+v", subs)
+	}
+	if !slices.Equal(subs[1].Factions, bugs.Factions) || !slices.Equal(subs[1].Kinds, bugs.Kinds) {
+		t.Errorf("expected filters to round-trip, got %+v", subs[1])
+	}
+
+	if err := s.RemoveSubscription("discord", "2"); err != nil {
+		t.Fatalf("RemoveSubscription: %v", err)
+	}
+	if err := s.RemoveSubscription("discord", "unknown"); err != nil {
+		t.Errorf("expected removing an unknown subscription to succeed, got %v", err)
+	}
+	if subs, _ := s.ListSubscriptions("discord"); len(subs) != 1 || subs[0].ID != "1" {
+		t.Errorf("expected only subscription 1 left, got %+v", subs)
+	}
+}
@@ -0,0 +1,51 @@
+package app
+
+import (
+	"github.com/ametis70/hellbot/internal/domain"
+	"github.com/ametis70/hellbot/internal/port"
+)
+
+// KindFilter wraps a notifier and drops messages whose kind it has not opted
+// into.
+type KindFilter struct {
+	next  port.Notifier
+	kinds map[domain.EventKind]struct{}
+}
+
+// FilterKinds wraps n so it only receives the given event kinds.
+// An empty list means domain.DefaultEventKinds.
+func FilterKinds(n port.Notifier, kinds []domain.EventKind) *KindFilter {
+	if len(kinds) == 0 {
+		kinds = domain.DefaultEventKinds
+	}
+	set := make(map[domain.EventKind]struct{}, len(kinds))
+	for _, k := range kinds {
+		set[k] = struct{}{}
+	}
+	return &KindFilter{next: n, kinds: set}
+}
+
+// Notify forwards msg if its kind is enabled.
+func (f *KindFilter) Notify(msg domain.EventMessage) error {
+	if _, ok := f.kinds[msg.Kind]; !ok {
+		return nil
+	}
+	return f.next.Notify(msg)
+}
+
+// Unwrap returns the wrapped notifier.
+func (f *KindFilter) Unwrap() port.Notifier {
+	return f.next
+}
+
+// Unwrap returns the innermost notifier behind any KindFilter wrappers, so
+// callers can type-assert optional interfaces such as port.Commander.
+func Unwrap(n port.Notifier) port.Notifier {
+	for {
+		w, ok := n.(interface{ Unwrap() port.Notifier })
+		if !ok {
+			return n
+		}
+		n = w.Unwrap()
+	}
+}
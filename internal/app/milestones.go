@@ -0,0 +1,43 @@
+package app
+
+import (
+	"github.com/ametis70/hellbot/internal/domain"
+	"github.com/ametis70/hellbot/internal/port"
+)
+
+// WatchMilestones enables milestone notifications. Announced milestones are
+// recorded in store so they are not repeated after a restart.
+func (p *Poller) WatchMilestones(rules domain.MilestoneRules, store port.MilestoneStore) {
+	p.milestoneRules = rules
+	p.milestones = store
+}
+
+func (p *Poller) handleMilestones(current, previous *domain.CampaignStatus) bool {
+	if p.milestones == nil {
+		return false
+	}
+
+	changed := false
+	for _, m := range domain.DetectMilestones(p.milestoneRules, previous, current) {
+		announced, err := p.milestones.HasMilestone(m.Season, m.Counter, m.Milestone)
+		if err != nil {
+			p.logger.Error("failed to look up milestone", "counter", m.Counter, "milestone", m.Milestone, "error", err)
+			continue
+		}
+		if announced {
+			continue
+		}
+		if err := p.milestones.SaveMilestone(m.Season, m.Counter, m.Milestone); err != nil {
+			p.logger.Error("failed to save milestone", "counter", m.Counter, "milestone", m.Milestone, "error", err)
+			continue
+		}
+		milestone := m
+		p.notify(domain.EventMessage{
+			Kind:           domain.EventKindMilestone,
+			Transition:     domain.EventTransitionReached,
+			MilestoneEvent: &milestone,
+		})
+		changed = true
+	}
+	return changed
+}
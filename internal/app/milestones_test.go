@@ -0,0 +1,59 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/ametis70/hellbot/internal/adapter/store/memory"
+	"github.com/ametis70/hellbot/internal/domain"
+	"github.com/ametis70/hellbot/internal/testutil"
+)
+
+func campaignWithKills(kills int) *domain.CampaignStatus {
+	c := testutil.CampaignWithNoDefend()
+	c.Statistics = []domain.Statistics{{Season: 159, Kills: kills}}
+	return c
+}
+
+func TestHandleMilestones_DisabledByDefault(t *testing.T) {
+	notifier := &testutil.MockNotifier{}
+	p := newTestPoller(notifier)
+	if p.handleMilestones(campaignWithKills(5000), campaignWithKills(0)) {
+		t.Error("expected no change when milestones are disabled")
+	}
+}
+
+func TestHandleMilestones_AnnouncedOnce(t *testing.T) {
+	notifier := &testutil.MockNotifier{}
+	p := newTestPoller(notifier)
+	p.WatchMilestones(domain.MilestoneRules{domain.MilestoneKills: {1000}}, memory.New())
+
+	if !p.handleMilestones(campaignWithKills(1200), campaignWithKills(900)) {
+		t.Error("expected handleMilestones to report a change")
+	}
+	// Same crossing seen again (e.g. the campaign was not saved before a restart).
+	p.handleMilestones(campaignWithKills(1200), campaignWithKills(900))
+
+	if notifier.Count() != 1 {
+		t.Fatalf("expected 1 notification, got %d", notifier.Count())
+	}
+	msg := notifier.First()
+	if msg.Kind != domain.EventKindMilestone || msg.Transition != domain.EventTransitionReached {
+		t.Errorf("unexpected message kind=%s transition=%s", msg.Kind, msg.Transition)
+	}
+	if msg.MilestoneEvent.Milestone != 1000 || msg.MilestoneEvent.Season != 159 {
+		t.Errorf("unexpected milestone event: %+v", msg.MilestoneEvent)
+	}
+}
+
+func TestHandleMilestones_StoreError(t *testing.T) {
+	notifier := &testutil.MockNotifier{}
+	p := newTestPoller(notifier)
+	p.WatchMilestones(domain.MilestoneRules{domain.MilestoneKills: {1000}}, &testutil.ErrorStore{})
+
+	if p.handleMilestones(campaignWithKills(1200), campaignWithKills(900)) {
+		t.Error("expected no change when the store fails")
+	}
+	if notifier.Count() != 0 {
+		t.Errorf("expected 0 notifications, got %d", notifier.Count())
+	}
+}
@@ -0,0 +1,64 @@
+package app
+
+import (
+	"github.com/ametis70/hellbot/internal/domain"
+)
+
+// playerWatch keeps a rolling window of online player counts so the poller
+// can raise threshold and surge/drop alerts.
+type playerWatch struct {
+	rules   domain.PlayerAlertRules
+	history []domain.PlayerSample
+	sides   domain.ThresholdSides
+}
+
+// observe records a sample and returns any alerts it triggers. After a change
+// alert the window restarts from the current sample so the same surge is not
+// reported on every poll.
+func (w *playerWatch) observe(season int, sample domain.PlayerSample) []domain.PlayersEvent {
+	alerts := domain.DetectPlayerAlerts(w.rules, season, w.history, sample, w.sides)
+
+	for _, a := range alerts {
+		if a.Trigger == domain.PlayersTriggerChange {
+			w.history = nil
+			break
+		}
+	}
+
+	w.history = append(w.history, sample)
+
+	// Drop samples that fell out of the window, always keeping the latest one.
+	since := sample.Time.Add(-w.rules.Window)
+	keep := 0
+	for keep < len(w.history)-1 && w.history[keep].Time.Before(since) {
+		keep++
+	}
+	w.history = w.history[keep:]
+
+	return alerts
+}
+
+// WatchPlayers enables player population alerts using the given rules.
+func (p *Poller) WatchPlayers(rules domain.PlayerAlertRules) {
+	p.players = &playerWatch{rules: rules, sides: domain.ThresholdSides{}}
+}
+
+func (p *Poller) handlePlayerAlerts(current *domain.CampaignStatus) bool {
+	if p.players == nil {
+		return false
+	}
+
+	alerts := p.players.observe(current.Season(), domain.PlayerSample{
+		Time:    current.Time,
+		Players: domain.TotalPlayers(current),
+	})
+	for i := range alerts {
+		alert := alerts[i]
+		p.notify(domain.EventMessage{
+			Kind:         domain.EventKindPlayers,
+			Transition:   alert.Transition(),
+			PlayersEvent: &alert,
+		})
+	}
+	return len(alerts) > 0
+}
@@ -0,0 +1,105 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ametis70/hellbot/internal/domain"
+	"github.com/ametis70/hellbot/internal/testutil"
+)
+
+func campaignWithPlayers(offset time.Duration, players ...int) *domain.CampaignStatus {
+	c := testutil.CampaignWithNoDefend()
+	c.Time = testutil.T0.Add(offset)
+	for _, n := range players {
+		c.Statistics = append(c.Statistics, domain.Statistics{Season: 159, Players: n})
+	}
+	return c
+}
+
+func TestHandlePlayerAlerts_DisabledByDefault(t *testing.T) {
+	notifier := &testutil.MockNotifier{}
+	p := newTestPoller(notifier)
+	p.handlePlayerAlerts(campaignWithPlayers(0, 100))
+	p.handlePlayerAlerts(campaignWithPlayers(time.Minute, 100000))
+	if notifier.Count() != 0 {
+		t.Errorf("expected 0 notifications, got %d", notifier.Count())
+	}
+}
+
+func TestHandlePlayerAlerts_ThresholdSumsFactions(t *testing.T) {
+	notifier := &testutil.MockNotifier{}
+	p := newTestPoller(notifier)
+	p.WatchPlayers(domain.PlayerAlertRules{Thresholds: []int{1000}})
+
+	p.handlePlayerAlerts(campaignWithPlayers(0, 300, 300, 300))
+	if notifier.Count() != 0 {
+		t.Fatalf("expected no alert on first sample, got %d", notifier.Count())
+	}
+
+	p.handlePlayerAlerts(campaignWithPlayers(time.Minute, 400, 400, 400))
+	if notifier.Count() != 1 {
+		t.Fatalf("expected 1 notification, got %d", notifier.Count())
+	}
+	msg := notifier.Last()
+	if msg.Kind != domain.EventKindPlayers || msg.Transition != domain.EventTransitionRose {
+		t.Errorf("unexpected message kind=%s transition=%s", msg.Kind, msg.Transition)
+	}
+	if msg.PlayersEvent.Players != 1200 || msg.PlayersEvent.Season != 159 {
+		t.Errorf("unexpected players event: %+v", msg.PlayersEvent)
+	}
+}
+
+func TestHandlePlayerAlerts_SurgeReportedOnce(t *testing.T) {
+	notifier := &testutil.MockNotifier{}
+	p := newTestPoller(notifier)
+	p.WatchPlayers(domain.PlayerAlertRules{ChangePercent: 50, Window: time.Hour})
+
+	p.handlePlayerAlerts(campaignWithPlayers(0, 1000))
+	p.handlePlayerAlerts(campaignWithPlayers(10*time.Minute, 1600))
+	p.handlePlayerAlerts(campaignWithPlayers(20*time.Minute, 1700))
+
+	if notifier.Count() != 1 {
+		t.Fatalf("expected 1 notification, got %d", notifier.Count())
+	}
+	if notifier.First().PlayersEvent.Trigger != domain.PlayersTriggerChange {
+		t.Errorf("expected change trigger, got %s", notifier.First().PlayersEvent.Trigger)
+	}
+}
+
+func TestPlayerWatch_TrimsHistoryToWindow(t *testing.T) {
+	w := &playerWatch{rules: domain.PlayerAlertRules{ChangePercent: 90, Window: time.Hour}}
+	for i := range 5 {
+		w.observe(159, domain.PlayerSample{Time: testutil.T0.Add(time.Duration(i) * 30 * time.Minute), Players: 1000})
+	}
+	if len(w.history) != 3 {
+		t.Errorf("expected 3 samples inside the 1h window, got %d", len(w.history))
+	}
+}
+
+func TestFilterKinds_DefaultsExcludePlayers(t *testing.T) {
+	notifier := &testutil.MockNotifier{}
+	f := FilterKinds(notifier, nil)
+
+	_ = f.Notify(domain.EventMessage{Kind: domain.EventKindPlayers, Transition: domain.EventTransitionRose})
+	_ = f.Notify(domain.EventMessage{Kind: domain.EventKindWar, Transition: domain.EventTransitionSucceeded})
+
+	if notifier.Count() != 1 || notifier.First().Kind != domain.EventKindWar {
+		t.Errorf("expected only the war message to pass, got %d messages", notifier.Count())
+	}
+}
+
+func TestFilterKinds_OptIn(t *testing.T) {
+	notifier := &testutil.MockNotifier{}
+	f := FilterKinds(notifier, []domain.EventKind{domain.EventKindPlayers})
+
+	_ = f.Notify(domain.EventMessage{Kind: domain.EventKindPlayers, Transition: domain.EventTransitionRose})
+	_ = f.Notify(domain.EventMessage{Kind: domain.EventKindWar, Transition: domain.EventTransitionSucceeded})
+
+	if notifier.Count() != 1 || notifier.First().Kind != domain.EventKindPlayers {
+		t.Errorf("expected only the players message to pass, got %d messages", notifier.Count())
+	}
+	if Unwrap(f) != notifier {
+		t.Error("expected Unwrap to return the wrapped notifier")
+	}
+}
@@ -16,6 +16,13 @@ type Poller struct {
 	notifiers []port.Notifier
 	interval  time.Duration
 	logger    *slog.Logger
+	players   *playerWatch
+
+	milestoneRules domain.MilestoneRules
+	milestones     port.MilestoneStore
+
+	observers []port.CampaignObserver
+	trigger   chan struct{}
 }
 
 func New(
@@ -33,6 +40,7 @@ func New(
 		notifiers: notifiers,
 		interval:  interval,
 		logger:    logger,
+		trigger:   make(chan struct{}, 1),
 	}
 }
 
@@ -46,10 +54,27 @@ func (p *Poller) Run(ctx context.Context) error {
 			return nil
 		case <-ticker.C:
 			p.poll()
+		case <-p.trigger:
+			p.logger.Info("poll triggered")
+			p.poll()
 		}
 	}
 }
 
+// TriggerPoll implements port.PollTrigger. Run polls as soon as the current
+// poll, if any, finishes.
+func (p *Poller) TriggerPoll() {
+	select {
+	case p.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// Observe registers o to receive every fetched campaign after it is saved.
+func (p *Poller) Observe(o port.CampaignObserver) {
+	p.observers = append(p.observers, o)
+}
+
 // PollOnce executes a single poll cycle. It is intended for use in tests.
 func (p *Poller) PollOnce() {
 	p.poll()
@@ -72,9 +97,15 @@ func (p *Poller) poll() {
 		}
 	}
 
+	p.handlePlayerAlerts(current)
+
 	if err := p.campaigns.SaveCampaign(current); err != nil {
 		p.logger.Error("failed to save campaign", "error", err)
 	}
+
+	for _, o := range p.observers {
+		o.ObserveCampaign(current)
+	}
 }
 
 func (p *Poller) notify(msg domain.EventMessage) {
@@ -85,207 +116,147 @@ func (p *Poller) notify(msg domain.EventMessage) {
 	}
 }
 
+// handleEvents notifies the defend, attack and war changes between the two
+// snapshots, as reported by domain.Diff, and announces reached milestones.
+// A defend or attack is only announced once the event store has recorded it
+// as started or ended, so a store failure never repeats a notification.
 func (p *Poller) handleEvents(current, previous *domain.CampaignStatus) bool {
-	defendEventsChanged := p.handleDefendEvent(current, previous)
-	attackEventsChanged := p.handleAttackEvents(current)
-	warEventsChanged := p.handleWarEvents(current, previous)
-
-	return defendEventsChanged || attackEventsChanged || warEventsChanged
-}
-
-func (p *Poller) handleDefendEvent(current, previous *domain.CampaignStatus) bool {
-	stored, err := p.events.ListOngoingEvents(domain.EventKindDefend)
-	if err != nil {
-		p.logger.Error("failed to list ongoing defend events", "error", err)
-		return false
-	}
-
-	// at most one defend event at a time
-	var storedEvent *domain.OngoingEvent
-	if len(stored) > 0 {
-		storedEvent = stored[0]
-	}
-
-	if current.DefendEvent == nil && storedEvent == nil {
-		return false
-	}
-
-	// no stored event yet — new event
-	if current.DefendEvent != nil && storedEvent == nil {
-		if current.DefendEvent.Status != domain.EventStatusActive {
-			return false
+	recorded := p.recordedEvents()
+	changed := false
+	for _, c := range domain.Diff(announced(previous, recorded), current) {
+		msg, ok := c.EventMessage()
+		if !ok {
+			continue
 		}
-		if err := p.events.SaveOngoingEvent(current.DefendEvent.ID, domain.EventKindDefend); err != nil {
-			p.logger.Error("failed to save ongoing defend event", "error", err)
-			return false
+		msg = withOutcome(msg, previous)
+		if !p.recordEvent(msg, recorded) {
+			continue
 		}
-		p.notify(domain.EventMessage{
-			Kind:        domain.EventKindDefend,
-			Transition:  domain.EventTransitionStarted,
-			DefendEvent: current.DefendEvent,
-		})
-		return true
+		p.notify(msg)
+		changed = true
 	}
 
-	// same event — check if status changed from active to ended
-	if current.DefendEvent != nil && current.DefendEvent.ID == storedEvent.ID {
-		if current.DefendEvent.Status == domain.EventStatusActive {
-			return false
-		}
-		if err := p.events.RemoveOngoingEvent(storedEvent.ID, domain.EventKindDefend); err != nil {
-			p.logger.Error("failed to remove ongoing defend event", "error", err)
-			return false
-		}
-		transition := domain.EventTransitionFailed
-		if current.DefendEvent.Status == domain.EventStatusSuccess {
-			transition = domain.EventTransitionSucceeded
-		}
-		p.notify(domain.EventMessage{
-			Kind:        domain.EventKindDefend,
-			Transition:  transition,
-			DefendEvent: current.DefendEvent,
-		})
-		return true
-	}
+	milestonesChanged := p.handleMilestones(current, previous)
+	return changed || milestonesChanged
+}
 
-	// different event ID — old ended, new started
-	if current.DefendEvent != nil && current.DefendEvent.ID != storedEvent.ID {
-		if previous.DefendEvent != nil && previous.DefendEvent.ID == storedEvent.ID {
-			transition := domain.EventTransitionFailed
-			if previous.DefendEvent.Status == domain.EventStatusSuccess {
-				transition = domain.EventTransitionSucceeded
-			}
-			p.notify(domain.EventMessage{
-				Kind:        domain.EventKindDefend,
-				Transition:  transition,
-				DefendEvent: previous.DefendEvent,
-			})
-		}
-		if err := p.events.RemoveOngoingEvent(storedEvent.ID, domain.EventKindDefend); err != nil {
-			p.logger.Error("failed to remove ongoing defend event", "error", err)
-			return false
+// recordedEvents returns the IDs of the defends and attacks announced as
+// started and not yet as ended, by kind. A kind is missing when the event
+// store cannot be read.
+func (p *Poller) recordedEvents() map[domain.EventKind]map[int]bool {
+	recorded := map[domain.EventKind]map[int]bool{}
+	for _, kind := range []domain.EventKind{domain.EventKindDefend, domain.EventKindAttack} {
+		stored, err := p.events.ListOngoingEvents(kind)
+		if err != nil {
+			p.logger.Error("failed to list ongoing events", "kind", kind, "error", err)
+			continue
 		}
-		if current.DefendEvent.Status == domain.EventStatusActive {
-			if err := p.events.SaveOngoingEvent(current.DefendEvent.ID, domain.EventKindDefend); err != nil {
-				p.logger.Error("failed to save ongoing defend event", "error", err)
-				return false
-			}
-			p.notify(domain.EventMessage{
-				Kind:        domain.EventKindDefend,
-				Transition:  domain.EventTransitionStarted,
-				DefendEvent: current.DefendEvent,
-			})
+		recorded[kind] = make(map[int]bool, len(stored))
+		for _, s := range stored {
+			recorded[kind][s.ID] = true
 		}
-		return true
 	}
-
-	return false
+	return recorded
 }
 
-func (p *Poller) handleAttackEvents(current *domain.CampaignStatus) bool {
-	stored, err := p.events.ListOngoingEvents(domain.EventKindAttack)
-	if err != nil {
-		p.logger.Error("failed to list ongoing attack events", "error", err)
-		return false
+// announced returns previous as it was announced: a defend or attack is
+// active exactly when it is recorded as ongoing. Diff then reports events
+// already running when the first snapshot was taken as started, and retries
+// the end of events whose removal failed. Kinds that could not be read are
+// left as is.
+func announced(previous *domain.CampaignStatus, recorded map[domain.EventKind]map[int]bool) *domain.CampaignStatus {
+	if previous == nil {
+		return nil
 	}
-
-	// build map of current active attack IDs for O(1) lookup
-	currentActive := make(map[int]domain.AttackEvent)
-	for _, e := range current.AttackEvents {
-		if e.Status == domain.EventStatusActive {
-			currentActive[e.ID] = e
+	view := *previous
+	if ids, ok := recorded[domain.EventKindDefend]; ok {
+		view.DefendEvent = nil
+		if d := previous.DefendEvent; d != nil && ids[d.ID] {
+			active := *d
+			active.Status = domain.EventStatusActive
+			view.DefendEvent = &active
 		}
 	}
-
-	changed := false
-
-	// stored events not in current active → ended
-	for _, s := range stored {
-		if _, stillActive := currentActive[s.ID]; !stillActive {
-			if err := p.events.RemoveOngoingEvent(s.ID, domain.EventKindAttack); err != nil {
-				p.logger.Error("failed to remove ongoing attack event", "error", err)
-				continue
-			}
-
-			for _, e := range current.AttackEvents {
-				if e.ID == s.ID {
-					transition := domain.EventTransitionFailed
-					if e.Status == domain.EventStatusSuccess {
-						transition = domain.EventTransitionSucceeded
-					}
-					attackCopy := e
-					p.notify(domain.EventMessage{
-						Kind:        domain.EventKindAttack,
-						Transition:  transition,
-						AttackEvent: &attackCopy,
-					})
-					break
-				}
+	if ids, ok := recorded[domain.EventKindAttack]; ok {
+		view.AttackEvents = nil
+		for _, e := range previous.AttackEvents {
+			if ids[e.ID] {
+				e.Status = domain.EventStatusActive
+				view.AttackEvents = append(view.AttackEvents, e)
 			}
-			changed = true
 		}
 	}
+	return &view
+}
 
-	// current active events not in store → new
-	storedIDs := make(map[int]struct{})
-	for _, s := range stored {
-		storedIDs[s.ID] = struct{}{}
-	}
-
-	for _, e := range current.AttackEvents {
-		if e.Status != domain.EventStatusActive {
-			continue
+// withOutcome restores the status an ended event had in previous when Diff
+// reported it from the announced view, where it is still active.
+func withOutcome(msg domain.EventMessage, previous *domain.CampaignStatus) domain.EventMessage {
+	switch {
+	case msg.DefendEvent != nil && msg.Transition != domain.EventTransitionStarted &&
+		msg.DefendEvent.Status == domain.EventStatusActive:
+		if d := previous.DefendEvent; d != nil && d.ID == msg.DefendEvent.ID {
+			msg.DefendEvent = d
+			msg.Transition = domain.EventTransitionFailed
+			if d.Status == domain.EventStatusSuccess {
+				msg.Transition = domain.EventTransitionSucceeded
+			}
 		}
-		if _, exists := storedIDs[e.ID]; !exists {
-			if err := p.events.SaveOngoingEvent(e.ID, domain.EventKindAttack); err != nil {
-				p.logger.Error("failed to save ongoing attack event", "error", err)
-				continue
+	case msg.AttackEvent != nil && msg.Transition != domain.EventTransitionStarted &&
+		msg.AttackEvent.Status == domain.EventStatusActive:
+		for _, e := range previous.AttackEvents {
+			if e.ID == msg.AttackEvent.ID {
+				attack := e
+				msg.AttackEvent = &attack
+				msg.Transition = domain.EventTransitionFailed
+				if e.Status == domain.EventStatusSuccess {
+					msg.Transition = domain.EventTransitionSucceeded
+				}
+				break
 			}
-			attackCopy := e
-			p.notify(domain.EventMessage{
-				Kind:        domain.EventKindAttack,
-				Transition:  domain.EventTransitionStarted,
-				AttackEvent: &attackCopy,
-			})
-			changed = true
 		}
 	}
-	return changed
+	return msg
 }
 
-func (p *Poller) handleWarEvents(current, previous *domain.CampaignStatus) bool {
-	if len(previous.FactionsStatus) == 0 || len(current.FactionsStatus) == 0 {
-		return false
+// recordEvent saves a started defend or attack in the event store, or
+// removes an ended one, and reports whether msg should be announced. It is
+// not when the store cannot be read or written, or when the event was
+// already recorded as started or ended. Other messages are always announced.
+func (p *Poller) recordEvent(msg domain.EventMessage, recorded map[domain.EventKind]map[int]bool) bool {
+	var id int
+	switch {
+	case msg.Kind == domain.EventKindDefend && msg.DefendEvent != nil:
+		id = msg.DefendEvent.ID
+	case msg.Kind == domain.EventKindAttack && msg.AttackEvent != nil:
+		id = msg.AttackEvent.ID
+	default:
+		return true
 	}
-
-	prevSeason := previous.FactionsStatus[0].Season
-	currSeason := current.FactionsStatus[0].Season
-	if prevSeason == currSeason {
+	ids, ok := recorded[msg.Kind]
+	if !ok {
 		return false
 	}
 
-	// Season changed — determine outcome from the previous season's final state.
-	// War is won if all non-hidden factions were defeated.
-	allDefeated := true
-	for _, f := range previous.FactionsStatus {
-		if f.Status == domain.FactionStatusHidden {
-			continue
+	if msg.Transition == domain.EventTransitionStarted {
+		if ids[id] {
+			return false
 		}
-		if f.Status != domain.FactionStatusDefeated {
-			allDefeated = false
-			break
+		if err := p.events.SaveOngoingEvent(id, msg.Kind); err != nil {
+			p.logger.Error("failed to save ongoing event", "kind", msg.Kind, "id", id, "error", err)
+			return false
 		}
+		ids[id] = true
+		return true
 	}
 
-	transition := domain.EventTransitionFailed
-	if allDefeated {
-		transition = domain.EventTransitionSucceeded
+	if !ids[id] {
+		return false
 	}
-
-	p.notify(domain.EventMessage{
-		Kind:       domain.EventKindWar,
-		Transition: transition,
-		WarEvent:   &domain.WarEvent{Season: prevSeason},
-	})
+	if err := p.events.RemoveOngoingEvent(id, msg.Kind); err != nil {
+		p.logger.Error("failed to remove ongoing event", "kind", msg.Kind, "id", id, "error", err)
+		return false
+	}
+	delete(ids, id)
 	return true
 }
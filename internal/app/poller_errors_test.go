@@ -59,7 +59,7 @@ func TestHandleDefendEvent_ListError(t *testing.T) {
 		notifiers: []port.Notifier{notifier},
 		logger:    logger,
 	}
-	result := p.handleDefendEvent(testutil.CampaignWithActiveDefend(), testutil.CampaignWithNoDefend())
+	result := p.handleEvents(testutil.CampaignWithActiveDefend(), testutil.CampaignWithNoDefend())
 	if result {
 		t.Error("expected false when ListOngoingEvents errors")
 	}
@@ -81,7 +81,7 @@ func TestHandleDefendEvent_SaveOngoingEventError(t *testing.T) {
 		notifiers: []port.Notifier{notifier},
 		logger:    logger,
 	}
-	result := p.handleDefendEvent(testutil.CampaignWithActiveDefend(), testutil.CampaignWithNoDefend())
+	result := p.handleEvents(testutil.CampaignWithActiveDefend(), testutil.CampaignWithNoDefend())
 	if result {
 		t.Error("expected false when SaveOngoingEvent errors")
 	}
@@ -104,7 +104,7 @@ func TestHandleDefendEvent_RemoveOngoingEventError(t *testing.T) {
 		notifiers: []port.Notifier{notifier},
 		logger:    logger,
 	}
-	result := p.handleDefendEvent(testutil.CampaignWithFailedDefend(), testutil.CampaignWithActiveDefend())
+	result := p.handleEvents(testutil.CampaignWithFailedDefend(), testutil.CampaignWithActiveDefend())
 	if result {
 		t.Error("expected false when RemoveOngoingEvent errors")
 	}
@@ -123,7 +123,7 @@ func TestHandleAttackEvents_ListError(t *testing.T) {
 		notifiers: []port.Notifier{notifier},
 		logger:    logger,
 	}
-	result := p.handleAttackEvents(testutil.CampaignWithActiveAttack())
+	result := p.handleEvents(testutil.CampaignWithActiveAttack(), testutil.CampaignWithNoDefend())
 	if result {
 		t.Error("expected false when ListOngoingEvents errors")
 	}
@@ -146,7 +146,7 @@ func TestHandleAttackEvents_RemoveError(t *testing.T) {
 		logger:    logger,
 	}
 	// Attack ended (success) — remove will fail.
-	p.handleAttackEvents(testutil.CampaignWithEndedAttack())
+	p.handleEvents(testutil.CampaignWithEndedAttack(), testutil.CampaignWithActiveAttack())
 	// Should not panic, changed = false because remove failed.
 }
 
@@ -162,13 +162,34 @@ func TestHandleAttackEvents_SaveError(t *testing.T) {
 		notifiers: []port.Notifier{notifier},
 		logger:    logger,
 	}
-	p.handleAttackEvents(testutil.CampaignWithActiveAttack())
+	p.handleEvents(testutil.CampaignWithActiveAttack(), testutil.CampaignWithNoDefend())
 	// No panic, no notification (save failed so event not registered).
 	if notifier.Count() != 0 {
 		t.Errorf("expected 0 notifications when save fails, got %d", notifier.Count())
 	}
 }
 
+// SaveCampaign keeps failing, so previous never catches up → each change is
+// still announced once.
+func TestHandleEvents_StalePreviousNotifiesOnce(t *testing.T) {
+	notifier := &testutil.MockNotifier{}
+	p := newTestPoller(notifier)
+
+	stale := testutil.CampaignWithNoDefend()
+	p.handleEvents(testutil.CampaignWithActiveDefend(), stale)
+	p.handleEvents(testutil.CampaignWithActiveDefend(), stale)
+	if notifier.Count() != 1 {
+		t.Fatalf("expected 1 started notification, got %d", notifier.Count())
+	}
+
+	stale = testutil.CampaignWithActiveDefend()
+	p.handleEvents(testutil.CampaignWithFailedDefend(), stale)
+	p.handleEvents(testutil.CampaignWithFailedDefend(), stale)
+	if notifier.Count() != 2 {
+		t.Errorf("expected 1 ended notification, got %d", notifier.Count()-1)
+	}
+}
+
 // --- store error helpers ---
 
 // saveFailStore delegates List/Get/Remove to inner, but fails Save operations.
@@ -53,6 +53,35 @@ func TestPollOnce_FirstPollNoDiff(t *testing.T) {
 	}
 }
 
+type recordingObserver struct {
+	seen []*domain.CampaignStatus
+}
+
+func (o *recordingObserver) ObserveCampaign(c *domain.CampaignStatus) {
+	o.seen = append(o.seen, c)
+}
+
+// Observers receive every fetched campaign, including the first.
+func TestPollOnce_NotifiesObservers(t *testing.T) {
+	campaign := testutil.CampaignWithActiveAttack()
+	p := newFullPoller(&testutil.MockFetcher{Campaign: campaign}, &testutil.MockNotifier{})
+	obs := &recordingObserver{}
+	p.Observe(obs)
+
+	p.PollOnce()
+	p.PollOnce()
+	if len(obs.seen) != 2 || obs.seen[0] != campaign {
+		t.Errorf("expected 2 observations of the campaign, got %d", len(obs.seen))
+	}
+
+	// A failed fetch is not observed.
+	p.fetcher = &testutil.MockFetcher{Err: errors.New("network error")}
+	p.PollOnce()
+	if len(obs.seen) != 2 {
+		t.Errorf("expected no observation on fetch error, got %d", len(obs.seen))
+	}
+}
+
 // PollOnce second poll detects a new attack event.
 func TestPollOnce_SecondPollDetectsAttack(t *testing.T) {
 	notifier := &testutil.MockNotifier{}
@@ -81,6 +110,35 @@ func TestRun_CancelExits(t *testing.T) {
 	}
 }
 
+// signalObserver reports every observed poll on a channel.
+type signalObserver chan struct{}
+
+func (o signalObserver) ObserveCampaign(*domain.CampaignStatus) { o <- struct{}{} }
+
+// TriggerPoll makes Run poll before the interval elapses.
+func TestRun_TriggerPoll(t *testing.T) {
+	fetcher := &testutil.MockFetcher{Campaign: testutil.CampaignWithNoDefend()}
+	p := newFullPoller(fetcher, &testutil.MockNotifier{})
+	polled := make(signalObserver)
+	p.Observe(polled)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- p.Run(ctx) }()
+
+	<-polled // initial poll
+	p.TriggerPoll()
+	select {
+	case <-polled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected a triggered poll")
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("expected nil error from Run, got %v", err)
+	}
+}
+
 // handleEvents returns true when any sub-handler detects a change.
 func TestHandleEvents_ReturnsTrueOnChange(t *testing.T) {
 	notifier := &testutil.MockNotifier{}
@@ -124,14 +182,14 @@ func TestNotify_FailingNotifierLogged(t *testing.T) {
 	}
 }
 
-// --- handleWarEvents ---
+// --- war events ---
 
-func TestHandleWarEvents_NoPreviousFactions(t *testing.T) {
+func TestHandleEvents_WarNoPreviousFactions(t *testing.T) {
 	notifier := &testutil.MockNotifier{}
 	p := newTestPoller(notifier)
 	current := testutil.CampaignWithNoDefend()
 	previous := &domain.CampaignStatus{}
-	result := p.handleWarEvents(current, previous)
+	result := p.handleEvents(current, previous)
 	if result {
 		t.Error("expected false when previous has no factions")
 	}
@@ -140,23 +198,23 @@ func TestHandleWarEvents_NoPreviousFactions(t *testing.T) {
 	}
 }
 
-func TestHandleWarEvents_NoCurrentFactions(t *testing.T) {
+func TestHandleEvents_WarNoCurrentFactions(t *testing.T) {
 	notifier := &testutil.MockNotifier{}
 	p := newTestPoller(notifier)
 	previous := testutil.CampaignWithNoDefend()
 	current := &domain.CampaignStatus{}
-	result := p.handleWarEvents(current, previous)
+	result := p.handleEvents(current, previous)
 	if result {
 		t.Error("expected false when current has no factions")
 	}
 }
 
-func TestHandleWarEvents_SameSeason(t *testing.T) {
+func TestHandleEvents_WarSameSeason(t *testing.T) {
 	notifier := &testutil.MockNotifier{}
 	p := newTestPoller(notifier)
 	current := testutil.CampaignWithNoDefend()
 	previous := testutil.CampaignWithNoDefend()
-	result := p.handleWarEvents(current, previous)
+	result := p.handleEvents(current, previous)
 	if result {
 		t.Error("expected false when season has not changed")
 	}
@@ -165,7 +223,7 @@ func TestHandleWarEvents_SameSeason(t *testing.T) {
 	}
 }
 
-func TestHandleWarEvents_WarWon(t *testing.T) {
+func TestHandleEvents_WarWarWon(t *testing.T) {
 	notifier := &testutil.MockNotifier{}
 	p := newTestPoller(notifier)
 
@@ -184,7 +242,7 @@ func TestHandleWarEvents_WarWon(t *testing.T) {
 		},
 	}
 
-	result := p.handleWarEvents(current, previous)
+	result := p.handleEvents(current, previous)
 	if !result {
 		t.Error("expected true when war ends")
 	}
@@ -203,7 +261,7 @@ func TestHandleWarEvents_WarWon(t *testing.T) {
 	}
 }
 
-func TestHandleWarEvents_WarLost(t *testing.T) {
+func TestHandleEvents_WarWarLost(t *testing.T) {
 	notifier := &testutil.MockNotifier{}
 	p := newTestPoller(notifier)
 
@@ -220,7 +278,7 @@ func TestHandleWarEvents_WarLost(t *testing.T) {
 		},
 	}
 
-	result := p.handleWarEvents(current, previous)
+	result := p.handleEvents(current, previous)
 	if !result {
 		t.Error("expected true when war ends")
 	}
@@ -230,7 +288,7 @@ func TestHandleWarEvents_WarLost(t *testing.T) {
 	}
 }
 
-func TestHandleWarEvents_HiddenFactionsIgnored(t *testing.T) {
+func TestHandleEvents_WarHiddenFactionsIgnored(t *testing.T) {
 	notifier := &testutil.MockNotifier{}
 	p := newTestPoller(notifier)
 
@@ -247,7 +305,7 @@ func TestHandleWarEvents_HiddenFactionsIgnored(t *testing.T) {
 		},
 	}
 
-	p.handleWarEvents(current, previous)
+	p.handleEvents(current, previous)
 	msg := notifier.First()
 	if msg.Transition != domain.EventTransitionSucceeded {
 		t.Errorf("expected succeeded when only hidden factions remain, got %s", msg.Transition)
@@ -23,32 +23,41 @@ func newTestPoller(notifier *testutil.MockNotifier) *Poller {
 	}
 }
 
-// --- handleDefendEvent tests ---
+// ongoingIDs returns the IDs of the ongoing events of kind in p's store.
+func ongoingIDs(t *testing.T, p *Poller, kind domain.EventKind) []int {
+	t.Helper()
+	stored, err := p.events.ListOngoingEvents(kind)
+	if err != nil {
+		t.Fatalf("ListOngoingEvents: %v", err)
+	}
+	ids := make([]int, len(stored))
+	for i, e := range stored {
+		ids[i] = e.ID
+	}
+	return ids
+}
+
+// --- defend events ---
 
 // Case 1: no defend event anywhere — no notification
-func TestHandleDefendEvent_NoneAnywhere(t *testing.T) {
+func TestHandleEvents_DefendNoneAnywhere(t *testing.T) {
 	notifier := &testutil.MockNotifier{}
 	p := newTestPoller(notifier)
 
-	current := testutil.CampaignWithNoDefend()
-	previous := testutil.CampaignWithNoDefend()
-
-	p.handleDefendEvent(current, previous)
+	p.handleEvents(testutil.CampaignWithNoDefend(), testutil.CampaignWithNoDefend())
 
 	if notifier.Count() != 0 {
 		t.Errorf("expected 0 notifications, got %d", notifier.Count())
 	}
 }
 
-// Case 2: new active defend event, nothing stored — notify started
-func TestHandleDefendEvent_NewActive(t *testing.T) {
+// Case 2: new active defend event — notify started and record it as ongoing
+func TestHandleEvents_DefendNewActive(t *testing.T) {
 	notifier := &testutil.MockNotifier{}
 	p := newTestPoller(notifier)
 
 	current := testutil.CampaignWithActiveDefend()
-	previous := testutil.CampaignWithNoDefend()
-
-	p.handleDefendEvent(current, previous)
+	p.handleEvents(current, testutil.CampaignWithNoDefend())
 
 	if notifier.Count() != 1 {
 		t.Fatalf("expected 1 notification, got %d", notifier.Count())
@@ -60,97 +69,91 @@ func TestHandleDefendEvent_NewActive(t *testing.T) {
 	if msg.Kind != domain.EventKindDefend {
 		t.Errorf("expected kind %s, got %s", domain.EventKindDefend, msg.Kind)
 	}
+	if ids := ongoingIDs(t, p, domain.EventKindDefend); len(ids) != 1 || ids[0] != current.DefendEvent.ID {
+		t.Errorf("expected defend %d to be ongoing, got %v", current.DefendEvent.ID, ids)
+	}
 }
 
-// Case 3: defend event already failed on first poll — should not notify started (stale)
-func TestHandleDefendEvent_StaleFailedOnFirstPoll(t *testing.T) {
+// Case 3: defend event already failed when first seen — should not notify (stale)
+func TestHandleEvents_DefendStaleFailed(t *testing.T) {
 	notifier := &testutil.MockNotifier{}
 	p := newTestPoller(notifier)
 
-	current := testutil.CampaignWithFailedDefend()
-	previous := testutil.CampaignWithNoDefend()
-
-	p.handleDefendEvent(current, previous)
+	p.handleEvents(testutil.CampaignWithFailedDefend(), testutil.CampaignWithNoDefend())
 
 	if notifier.Count() != 0 {
 		t.Errorf("expected 0 notifications, got %d", notifier.Count())
 	}
+	if ids := ongoingIDs(t, p, domain.EventKindDefend); len(ids) != 0 {
+		t.Errorf("expected no ongoing defend, got %v", ids)
+	}
 }
 
 // Case 4: same event still active — no notification
-func TestHandleDefendEvent_StillActive(t *testing.T) {
+func TestHandleEvents_DefendStillActive(t *testing.T) {
 	notifier := &testutil.MockNotifier{}
 	p := newTestPoller(notifier)
 
-	current := testutil.CampaignWithActiveDefend()
 	previous := testutil.CampaignWithActiveDefend()
+	_ = p.events.SaveOngoingEvent(previous.DefendEvent.ID, domain.EventKindDefend)
 
-	// store the event first
-	_ = p.events.SaveOngoingEvent(current.DefendEvent.ID, domain.EventKindDefend)
-
-	p.handleDefendEvent(current, previous)
+	p.handleEvents(testutil.CampaignWithActiveDefend(), previous)
 
 	if notifier.Count() != 0 {
 		t.Errorf("expected 0 notifications, got %d", notifier.Count())
 	}
 }
 
-// Case 5: same event ended with fail — notify failed
-func TestHandleDefendEvent_EndedFailed(t *testing.T) {
+// Case 5: same event ended with fail — notify failed and forget it
+func TestHandleEvents_DefendEndedFailed(t *testing.T) {
 	notifier := &testutil.MockNotifier{}
 	p := newTestPoller(notifier)
 
-	current := testutil.CampaignWithFailedDefend()
 	previous := testutil.CampaignWithActiveDefend()
-
-	// store the active event first
 	_ = p.events.SaveOngoingEvent(previous.DefendEvent.ID, domain.EventKindDefend)
 
-	p.handleDefendEvent(current, previous)
+	p.handleEvents(testutil.CampaignWithFailedDefend(), previous)
 
 	if notifier.Count() != 1 {
 		t.Fatalf("expected 1 notification, got %d", notifier.Count())
 	}
-	msg := notifier.First()
-	if msg.Transition != domain.EventTransitionFailed {
+	if msg := notifier.First(); msg.Transition != domain.EventTransitionFailed {
 		t.Errorf("expected transition %s, got %s", domain.EventTransitionFailed, msg.Transition)
 	}
+	if ids := ongoingIDs(t, p, domain.EventKindDefend); len(ids) != 0 {
+		t.Errorf("expected no ongoing defend, got %v", ids)
+	}
 }
 
 // Case 6: same event ended with success — notify succeeded
-func TestHandleDefendEvent_EndedSucceeded(t *testing.T) {
+func TestHandleEvents_DefendEndedSucceeded(t *testing.T) {
 	notifier := &testutil.MockNotifier{}
 	p := newTestPoller(notifier)
 
-	current := testutil.CampaignWithSucceededDefend()
 	previous := testutil.CampaignWithActiveDefend()
-
 	_ = p.events.SaveOngoingEvent(previous.DefendEvent.ID, domain.EventKindDefend)
 
-	p.handleDefendEvent(current, previous)
+	p.handleEvents(testutil.CampaignWithSucceededDefend(), previous)
 
 	if notifier.Count() != 1 {
 		t.Fatalf("expected 1 notification, got %d", notifier.Count())
 	}
-	msg := notifier.First()
-	if msg.Transition != domain.EventTransitionSucceeded {
+	if msg := notifier.First(); msg.Transition != domain.EventTransitionSucceeded {
 		t.Errorf("expected transition %s, got %s", domain.EventTransitionSucceeded, msg.Transition)
 	}
 }
 
 // Case 7: different event ID — old ended (failed), new started
-func TestHandleDefendEvent_NewEventReplacesOld(t *testing.T) {
+func TestHandleEvents_DefendReplaced(t *testing.T) {
 	notifier := &testutil.MockNotifier{}
 	p := newTestPoller(notifier)
 
 	previous := testutil.CampaignWithFailedDefend()
-	newDefend := testutil.DefendEventNewActive()
 	current := testutil.CampaignWithNoDefend()
-	current.DefendEvent = newDefend
-
+	current.DefendEvent = testutil.DefendEventNewActive()
 	_ = p.events.SaveOngoingEvent(previous.DefendEvent.ID, domain.EventKindDefend)
 
-	p.handleDefendEvent(current, previous)
+	p.handleEvents(current, previous)
 
 	if notifier.Count() != 2 {
 		t.Fatalf("expected 2 notifications (ended + started), got %d", notifier.Count())
@@ -161,32 +164,63 @@ func TestHandleDefendEvent_NewEventReplacesOld(t *testing.T) {
 	if notifier.Last().Transition != domain.EventTransitionStarted {
 		t.Errorf("expected second notification to be started, got %s", notifier.Last().Transition)
 	}
+	if ids := ongoingIDs(t, p, domain.EventKindDefend); len(ids) != 1 || ids[0] != current.DefendEvent.ID {
+		t.Errorf("expected only defend %d to be ongoing, got %v", current.DefendEvent.ID, ids)
+	}
 }
 
-// --- handleAttackEvents tests ---
+// Case 8: active event gone from the feed — reported as ended
+func TestHandleEvents_DefendGone(t *testing.T) {
+	notifier := &testutil.MockNotifier{}
+	p := newTestPoller(notifier)
 
-// Case 1: no attack events anywhere — no notification
-func TestHandleAttackEvents_NoneAnywhere(t *testing.T) {
+	previous := testutil.CampaignWithActiveDefend()
+	_ = p.events.SaveOngoingEvent(previous.DefendEvent.ID, domain.EventKindDefend)
+
+	p.handleEvents(testutil.CampaignWithNoDefend(), previous)
+
+	if notifier.Count() != 1 || notifier.First().DefendEvent.ID != previous.DefendEvent.ID {
+		t.Fatalf("expected the vanished defend to be reported, got %d notifications", notifier.Count())
+	}
+	if ids := ongoingIDs(t, p, domain.EventKindDefend); len(ids) != 0 {
+		t.Errorf("expected no ongoing defend, got %v", ids)
+	}
+}
+
+// Case 9: defend already active in the first snapshot — announced on the next poll
+func TestHandleEvents_DefendActiveBeforeBaseline(t *testing.T) {
 	notifier := &testutil.MockNotifier{}
 	p := newTestPoller(notifier)
 
-	current := testutil.CampaignWithNoDefend()
+	p.handleEvents(testutil.CampaignWithActiveDefend(), testutil.CampaignWithActiveDefend())
+	p.handleEvents(testutil.CampaignWithActiveDefend(), testutil.CampaignWithActiveDefend())
+
+	if notifier.Count() != 1 || notifier.First().Transition != domain.EventTransitionStarted {
+		t.Fatalf("expected a single started notification, got %d", notifier.Count())
+	}
+}
 
-	p.handleAttackEvents(current)
+// --- attack events ---
+
+// Case 1: no attack events anywhere — no notification
+func TestHandleEvents_AttackNoneAnywhere(t *testing.T) {
+	notifier := &testutil.MockNotifier{}
+	p := newTestPoller(notifier)
+
+	p.handleEvents(testutil.CampaignWithNoDefend(), testutil.CampaignWithNoDefend())
 
 	if notifier.Count() != 0 {
 		t.Errorf("expected 0 notifications, got %d", notifier.Count())
 	}
 }
 
-// Case 2: new active attack event — notify started
-func TestHandleAttackEvents_NewActive(t *testing.T) {
+// Case 2: new active attack event — notify started and record it as ongoing
+func TestHandleEvents_AttackNewActive(t *testing.T) {
 	notifier := &testutil.MockNotifier{}
 	p := newTestPoller(notifier)
 
 	current := testutil.CampaignWithActiveAttack()
-
-	p.handleAttackEvents(current)
+	p.handleEvents(current, testutil.CampaignWithNoDefend())
 
 	if notifier.Count() != 1 {
 		t.Fatalf("expected 1 notification, got %d", notifier.Count())
@@ -198,60 +232,67 @@ func TestHandleAttackEvents_NewActive(t *testing.T) {
 	if msg.Kind != domain.EventKindAttack {
 		t.Errorf("expected kind %s, got %s", domain.EventKindAttack, msg.Kind)
 	}
+	if ids := ongoingIDs(t, p, domain.EventKindAttack); len(ids) != 1 || ids[0] != current.AttackEvents[0].ID {
+		t.Errorf("expected attack %d to be ongoing, got %v", current.AttackEvents[0].ID, ids)
+	}
 }
 
 // Case 3: active attack event still ongoing — no notification
-func TestHandleAttackEvents_StillActive(t *testing.T) {
+func TestHandleEvents_AttackStillActive(t *testing.T) {
 	notifier := &testutil.MockNotifier{}
 	p := newTestPoller(notifier)
 
-	current := testutil.CampaignWithActiveAttack()
-	_ = p.events.SaveOngoingEvent(current.AttackEvents[0].ID, domain.EventKindAttack)
+	previous := testutil.CampaignWithActiveAttack()
+	_ = p.events.SaveOngoingEvent(previous.AttackEvents[0].ID, domain.EventKindAttack)
 
-	p.handleAttackEvents(current)
+	p.handleEvents(testutil.CampaignWithActiveAttack(), previous)
 
 	if notifier.Count() != 0 {
 		t.Errorf("expected 0 notifications, got %d", notifier.Count())
 	}
 }
 
-// Case 4: stored attack event ended with success — notify succeeded
-func TestHandleAttackEvents_EndedSucceeded(t *testing.T) {
+// Case 4: attack event ended with success — notify succeeded and forget it
+func TestHandleEvents_AttackEndedSucceeded(t *testing.T) {
 	notifier := &testutil.MockNotifier{}
 	p := newTestPoller(notifier)
 
-	current := testutil.CampaignWithEndedAttack()
-	_ = p.events.SaveOngoingEvent(current.AttackEvents[0].ID, domain.EventKindAttack)
+	previous := testutil.CampaignWithActiveAttack()
+	_ = p.events.SaveOngoingEvent(previous.AttackEvents[0].ID, domain.EventKindAttack)
 
-	p.handleAttackEvents(current)
+	p.handleEvents(testutil.CampaignWithEndedAttack(), previous)
 
 	if notifier.Count() != 1 {
 		t.Fatalf("expected 1 notification, got %d", notifier.Count())
 	}
-	msg := notifier.First()
-	if msg.Transition != domain.EventTransitionSucceeded {
+	if msg := notifier.First(); msg.Transition != domain.EventTransitionSucceeded {
 		t.Errorf("expected transition %s, got %s", domain.EventTransitionSucceeded, msg.Transition)
 	}
+	if ids := ongoingIDs(t, p, domain.EventKindAttack); len(ids) != 0 {
+		t.Errorf("expected no ongoing attack, got %v", ids)
+	}
 }
 
-// Case 5: stored attack event ended with fail — notify failed
-func TestHandleAttackEvents_EndedFailed(t *testing.T) {
+// Case 5: attack event ended with fail — notify failed
+func TestHandleEvents_AttackEndedFailed(t *testing.T) {
 	notifier := &testutil.MockNotifier{}
 	p := newTestPoller(notifier)
 
 	failed := testutil.AttackEventFailed()
+	active := failed
+	active.Status = domain.EventStatusActive
+	previous := testutil.CampaignWithNoDefend()
+	previous.AttackEvents = []domain.AttackEvent{active}
 	current := testutil.CampaignWithNoDefend()
 	current.AttackEvents = []domain.AttackEvent{failed}
+	_ = p.events.SaveOngoingEvent(active.ID, domain.EventKindAttack)
 
-	_ = p.events.SaveOngoingEvent(failed.ID, domain.EventKindAttack)
-
-	p.handleAttackEvents(current)
+	p.handleEvents(current, previous)
 
 	if notifier.Count() != 1 {
 		t.Fatalf("expected 1 notification, got %d", notifier.Count())
 	}
-	msg := notifier.First()
-	if msg.Transition != domain.EventTransitionFailed {
+	if msg := notifier.First(); msg.Transition != domain.EventTransitionFailed {
 		t.Errorf("expected transition %s, got %s", domain.EventTransitionFailed, msg.Transition)
 	}
 }
@@ -20,10 +20,13 @@ const (
 type RawOptions map[string]any
 
 // NotifierConfig represents a single notifier entry in the config file.
+// Events lists the event kinds the notifier receives; when empty it receives
+// domain.DefaultEventKinds. Player alerts are only sent to notifiers that list them.
 type NotifierConfig struct {
-	ID      string       `yaml:"id"`
-	Type    NotifierType `yaml:"type"`
-	Options RawOptions   `yaml:"options"`
+	ID      string             `yaml:"id"`
+	Type    NotifierType       `yaml:"type"`
+	Events  []domain.EventKind `yaml:"events"`
+	Options RawOptions         `yaml:"options"`
 }
 
 // WebhookOptions holds parsed options for the webhook notifier.
@@ -40,8 +43,10 @@ type WebhookOptions struct {
 
 // StdoutOptions holds parsed options for the stdout notifier.
 type StdoutOptions struct {
-	Timezone  string            `yaml:"timezone"`
-	Templates *domain.Templates `yaml:"templates"`
+	Timezone  string              `yaml:"timezone"`
+	Locale    domain.Locale       `yaml:"locale"`
+	Style     domain.MessageStyle `yaml:"style"`
+	Templates *domain.Templates   `yaml:"templates"`
 }
 
 // DiscordOptions holds parsed options for the discord notifier.
@@ -49,25 +54,119 @@ type StdoutOptions struct {
 // ChannelID and ChannelIDFile are mutually exclusive — exactly one must be set.
 // GuildID is optional — when set, slash commands are registered as guild commands
 // (instant propagation). When empty, commands are registered globally (up to 1h delay).
+// Embeds lists the event kinds sent as embeds; the rest use templates.
+// BoardChannelID is optional — when set, a pinned war status message in that
+// channel is edited after every poll.
+// Mentions route role, user and @everyone pings to matching events.
+// Routes send matching events to further channels over the same session;
+// channel_id is optional when at least one route is set.
+// Subscriptions enables /subscribe and /unsubscribe for event DMs.
+// ScheduledEvents creates guild scheduled events for defends and attacks and
+// requires GuildID. Threads opens a thread per defend and attack for progress
+// updates and the outcome. Presence shows war progress as the bot's status.
+// AdminRoles lists role IDs allowed to run /admin besides Manage Server.
+// WebhookURL and WebhookURLFile are mutually exclusive; either replaces the
+// token and channel, posting through an incoming webhook as Username and
+// AvatarURL, overridden per faction by FactionProfiles.
 type DiscordOptions struct {
-	Token         string            `yaml:"token"`
-	TokenFile     string            `yaml:"token_file"`
-	ChannelID     string            `yaml:"channel_id"`
-	ChannelIDFile string            `yaml:"channel_id_file"`
-	GuildID       string            `yaml:"guild_id"`
-	Templates     *domain.Templates `yaml:"templates"`
+	Token           string                           `yaml:"token"`
+	TokenFile       string                           `yaml:"token_file"`
+	ChannelID       string                           `yaml:"channel_id"`
+	ChannelIDFile   string                           `yaml:"channel_id_file"`
+	GuildID         string                           `yaml:"guild_id"`
+	Locale          domain.Locale                    `yaml:"locale"`
+	Style           domain.MessageStyle              `yaml:"style"`
+	Embeds          []domain.EventKind               `yaml:"embeds"`
+	BoardChannelID  string                           `yaml:"board_channel_id"`
+	Mentions        []DiscordMention                 `yaml:"mentions"`
+	Routes          []DiscordRoute                   `yaml:"routes"`
+	Subscriptions   bool                             `yaml:"subscriptions"`
+	ScheduledEvents bool                             `yaml:"scheduled_events"`
+	Threads         bool                             `yaml:"threads"`
+	Presence        *DiscordPresence                 `yaml:"presence"`
+	AdminRoles      []string                         `yaml:"admin_roles"`
+	WebhookURL      string                           `yaml:"webhook_url"`
+	WebhookURLFile  string                           `yaml:"webhook_url_file"`
+	Username        string                           `yaml:"username"`
+	AvatarURL       string                           `yaml:"avatar_url"`
+	FactionProfiles map[string]DiscordWebhookProfile `yaml:"faction_profiles"`
+	Templates       *domain.Templates                `yaml:"templates"`
+}
+
+// DiscordPresence sets the formats of the bot's status while a defend or
+// attack runs and otherwise. Empty formats use the locale's defaults. Interval
+// is the minimum time between updates, at least one minute.
+type DiscordPresence struct {
+	Defend   string        `yaml:"defend"`
+	Attack   string        `yaml:"attack"`
+	War      string        `yaml:"war"`
+	Interval time.Duration `yaml:"interval"`
+}
+
+// DiscordWebhookProfile is the name and avatar used for a faction's events in
+// webhook mode. Empty fields fall back to the notifier's username and avatar.
+type DiscordWebhookProfile struct {
+	Username  string `yaml:"username"`
+	AvatarURL string `yaml:"avatar_url"`
+}
+
+// DiscordRoute sends events matching its factions and event kinds to another
+// channel. Omitted filters match any event. Templates are merged over the
+// notifier's own.
+type DiscordRoute struct {
+	ChannelID     string             `yaml:"channel_id"`
+	ChannelIDFile string             `yaml:"channel_id_file"`
+	Factions      []string           `yaml:"factions"`
+	Events        []domain.EventKind `yaml:"events"`
+	Templates     *domain.Templates  `yaml:"templates"`
+}
+
+// DiscordMention pings roles, users or @everyone for events matching all of
+// its criteria: factions (bugs, cyborgs, illuminate), event kinds, and
+// super_earth for Super Earth defenses only. Omitted criteria match any event.
+type DiscordMention struct {
+	Factions   []string           `yaml:"factions"`
+	Events     []domain.EventKind `yaml:"events"`
+	SuperEarth bool               `yaml:"super_earth"`
+	Roles      []string           `yaml:"roles"`
+	Users      []string           `yaml:"users"`
+	Everyone   bool               `yaml:"everyone"`
 }
 
 // TelegramOptions holds parsed options for the telegram notifier.
 // Token and TokenFile are mutually exclusive — exactly one must be set.
 // ChatID and ChatIDFile are mutually exclusive — exactly one must be set.
+// Subscriptions enables /subscribe and /unsubscribe for other chats.
+// MessageThreadID sends events to a forum topic of the chat, overridden per
+// faction by FactionThreads.
+// AllowedChats and AllowedUsers restrict commands to these numeric chat and
+// user IDs, besides ChatID; when both are empty anyone may run commands.
+// Webhook, when set, receives updates on hellbot's HTTP server instead of
+// long polling.
 type TelegramOptions struct {
-	Token      string            `yaml:"token"`
-	TokenFile  string            `yaml:"token_file"`
-	ChatID     string            `yaml:"chat_id"`
-	ChatIDFile string            `yaml:"chat_id_file"`
-	Timezone   string            `yaml:"timezone"`
-	Templates  *domain.Templates `yaml:"templates"`
+	Token           string              `yaml:"token"`
+	TokenFile       string              `yaml:"token_file"`
+	ChatID          string              `yaml:"chat_id"`
+	ChatIDFile      string              `yaml:"chat_id_file"`
+	Timezone        string              `yaml:"timezone"`
+	Locale          domain.Locale       `yaml:"locale"`
+	Style           domain.MessageStyle `yaml:"style"`
+	Subscriptions   bool                `yaml:"subscriptions"`
+	MessageThreadID int                 `yaml:"message_thread_id"`
+	FactionThreads  map[string]int      `yaml:"faction_threads"`
+	AllowedChats    []string            `yaml:"allowed_chats"`
+	AllowedUsers    []string            `yaml:"allowed_users"`
+	Webhook         *TelegramWebhook    `yaml:"webhook"`
+	Templates       *domain.Templates   `yaml:"templates"`
+}
+
+// TelegramWebhook holds the public HTTPS URL Telegram posts updates to and
+// the secret token it sends with them. SecretToken and SecretTokenFile are
+// mutually exclusive — exactly one must be set.
+type TelegramWebhook struct {
+	URL             string `yaml:"url"`
+	SecretToken     string `yaml:"secret_token"`
+	SecretTokenFile string `yaml:"secret_token_file"`
 }
 
 // StoreType identifies the kind of backing store.
@@ -100,6 +199,13 @@ type StoreConfig struct {
 	Options RawOptions `yaml:"options"`
 }
 
+// HTTPConfig configures hellbot's HTTP server, which receives Telegram
+// webhook updates. It only starts when a notifier needs it.
+type HTTPConfig struct {
+	// Listen is the address to listen on (default: ":8080").
+	Listen string `yaml:"listen"`
+}
+
 // DevConfig holds development/testing options. These should never be set in production.
 type DevConfig struct {
 	// MockServer replaces the real Helldivers API fetcher with a built-in mock
@@ -111,20 +217,76 @@ type DevConfig struct {
 	APIURL string `yaml:"api_url"`
 }
 
+// PlayersConfig configures player population alerts. Alerts are disabled
+// unless at least one threshold or a change percentage is set.
+type PlayersConfig struct {
+	// Thresholds are absolute online counts; crossing one in either direction
+	// raises an alert.
+	Thresholds []int
+	// ThresholdHysteresis is the percentage below a threshold the count must
+	// fall to before a downward alert, and before the next upward one.
+	ThresholdHysteresis int
+	// ChangePercent raises a surge or drop alert when the online count changes
+	// by at least this percentage within ChangeWindow. Zero disables it.
+	ChangePercent int
+	ChangeWindow  time.Duration
+}
+
+// rawPlayersConfig mirrors PlayersConfig but keeps ChangeWindow as a string for YAML parsing.
+type rawPlayersConfig struct {
+	Thresholds          []int  `yaml:"thresholds"`
+	ThresholdHysteresis *int   `yaml:"threshold_hysteresis"`
+	ChangePercent       int    `yaml:"change_percent"`
+	ChangeWindow        string `yaml:"change_window"`
+}
+
+// MilestonesConfig lists the milestones announced for each cumulative
+// statistics counter. Counters are summed across factions and reset every war.
+type MilestonesConfig struct {
+	Kills            []int `yaml:"kills"`
+	Deaths           []int `yaml:"deaths"`
+	Accidentals      []int `yaml:"accidentals"`
+	Missions         []int `yaml:"missions"`
+	PlanetsLiberated []int `yaml:"planets_liberated"`
+}
+
+// TemplateValidation controls what happens when a notifier template references
+// an unknown placeholder or fails to parse.
+type TemplateValidation string
+
+const (
+	// TemplateValidationWarn records problems in Config.Warnings. Default.
+	TemplateValidationWarn TemplateValidation = "warn"
+	// TemplateValidationStrict makes Load fail.
+	TemplateValidationStrict TemplateValidation = "strict"
+)
+
 // Config is the top-level configuration structure.
+// Warnings holds non-fatal problems found by Load, for the caller to log.
 type Config struct {
-	PollInterval time.Duration
-	Timezone     string           `yaml:"timezone"`
-	Dev          DevConfig        `yaml:"dev"`
-	Store        StoreConfig      `yaml:"store"`
-	Notifiers    []NotifierConfig `yaml:"notifiers"`
+	PollInterval       time.Duration
+	TemplateValidation TemplateValidation `yaml:"template_validation"`
+	Warnings           []string
+	Timezone           string        `yaml:"timezone"`
+	Locale             domain.Locale `yaml:"locale"`
+	Dev                DevConfig     `yaml:"dev"`
+	Store              StoreConfig   `yaml:"store"`
+	HTTP               HTTPConfig    `yaml:"http"`
+	Players            PlayersConfig
+	Milestones         MilestonesConfig `yaml:"milestones"`
+	Notifiers          []NotifierConfig `yaml:"notifiers"`
 }
 
-// rawConfig mirrors Config but keeps PollInterval as a string for YAML parsing.
+// rawConfig mirrors Config but keeps durations as strings for YAML parsing.
 type rawConfig struct {
-	PollInterval string           `yaml:"poll_interval"`
-	Timezone     string           `yaml:"timezone"`
-	Dev          DevConfig        `yaml:"dev"`
-	Store        StoreConfig      `yaml:"store"`
-	Notifiers    []NotifierConfig `yaml:"notifiers"`
+	PollInterval       string             `yaml:"poll_interval"`
+	TemplateValidation TemplateValidation `yaml:"template_validation"`
+	Timezone           string             `yaml:"timezone"`
+	Locale             domain.Locale      `yaml:"locale"`
+	Dev                DevConfig          `yaml:"dev"`
+	Store              StoreConfig        `yaml:"store"`
+	HTTP               HTTPConfig         `yaml:"http"`
+	Players            rawPlayersConfig   `yaml:"players"`
+	Milestones         MilestonesConfig   `yaml:"milestones"`
+	Notifiers          []NotifierConfig   `yaml:"notifiers"`
 }
@@ -2,22 +2,35 @@ package config
 
 import (
 	"fmt"
+	"net/url"
 	"os"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/ametis70/hellbot/internal/domain"
 )
 
 const (
 	defaultPollInterval = 60 * time.Second
+	defaultChangeWindow = time.Hour
+	defaultHysteresis   = 5
 	defaultTimezone     = "UTC"
 	defaultConfigPath   = "config.yml"
+	defaultHTTPListen   = ":8080"
+	// minPresenceInterval keeps Discord presence updates within its limits.
+	minPresenceInterval = time.Minute
 )
 
 var envVarPattern = regexp.MustCompile(`\$\{([^}]+)\}`)
 
+// secretTokenPattern is the format Telegram accepts for webhook secret tokens.
+var secretTokenPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,256}$`)
+
 // resolveEnvVars replaces ${VAR} patterns with environment variable values.
 // If the variable is not set, the placeholder is left as-is.
 func resolveEnvVars(s string) string {
@@ -72,6 +85,23 @@ func parseTimezone(tz string) (*time.Location, error) {
 	return loc, nil
 }
 
+// validateStyle checks the notifier's message style. Empty means "template".
+func validateStyle(s domain.MessageStyle) error {
+	if !s.Valid() {
+		return fmt.Errorf("unknown style %q: must be %q or %q", s, domain.MessageStyleTemplate, domain.MessageStyleStructured)
+	}
+	return nil
+}
+
+// validateLocale checks that a locale has a message catalog. Empty is allowed
+// and means "inherit".
+func validateLocale(l domain.Locale) error {
+	if l != "" && !l.Valid() {
+		return fmt.Errorf("unknown locale %q", l)
+	}
+	return nil
+}
+
 // ResolveWebhookOptions decodes, validates, and resolves webhook notifier options.
 func ResolveWebhookOptions(raw RawOptions) (WebhookOptions, error) {
 	opts := WebhookOptions{}
@@ -120,6 +150,12 @@ func ResolveStdoutOptions(raw RawOptions) (StdoutOptions, error) {
 	if err := yaml.Unmarshal(data, &opts); err != nil {
 		return opts, fmt.Errorf("parsing stdout options: %w", err)
 	}
+	if err := validateLocale(opts.Locale); err != nil {
+		return opts, err
+	}
+	if err := validateStyle(opts.Style); err != nil {
+		return opts, err
+	}
 	return opts, nil
 }
 
@@ -137,6 +173,33 @@ func ResolveDiscordOptions(raw RawOptions) (DiscordOptions, error) {
 	if err := yaml.Unmarshal(data, &opts); err != nil {
 		return opts, fmt.Errorf("parsing discord options: %w", err)
 	}
+	if err := validateLocale(opts.Locale); err != nil {
+		return opts, err
+	}
+	if err := validateStyle(opts.Style); err != nil {
+		return opts, err
+	}
+	for _, k := range opts.Embeds {
+		if !k.Valid() {
+			return opts, fmt.Errorf("discord embeds: unknown event kind %q", k)
+		}
+	}
+	for i, m := range opts.Mentions {
+		if err := validateMention(m); err != nil {
+			return opts, fmt.Errorf("discord mentions[%d]: %w", i, err)
+		}
+	}
+
+	if opts.WebhookURL != "" || opts.WebhookURLFile != "" {
+		return resolveDiscordWebhook(opts)
+	}
+
+	if opts.ScheduledEvents && opts.GuildID == "" {
+		return opts, fmt.Errorf("discord: scheduled_events requires guild_id")
+	}
+	if p := opts.Presence; p != nil && p.Interval != 0 && p.Interval < minPresenceInterval {
+		return opts, fmt.Errorf("discord presence: interval %s must be at least %s", p.Interval, minPresenceInterval)
+	}
 
 	token, err := resolveValue("token", opts.Token, opts.TokenFile)
 	if err != nil {
@@ -145,16 +208,84 @@ func ResolveDiscordOptions(raw RawOptions) (DiscordOptions, error) {
 	opts.Token = token
 	opts.TokenFile = ""
 
-	channelID, err := resolveValue("channel_id", opts.ChannelID, opts.ChannelIDFile)
-	if err != nil {
-		return opts, fmt.Errorf("discord channel_id: %w", err)
+	// With routes, the main channel is optional.
+	if len(opts.Routes) == 0 || opts.ChannelID != "" || opts.ChannelIDFile != "" {
+		channelID, err := resolveValue("channel_id", opts.ChannelID, opts.ChannelIDFile)
+		if err != nil {
+			return opts, fmt.Errorf("discord channel_id: %w", err)
+		}
+		opts.ChannelID = channelID
+		opts.ChannelIDFile = ""
+	}
+
+	for i := range opts.Routes {
+		r := &opts.Routes[i]
+		channelID, err := resolveValue("channel_id", r.ChannelID, r.ChannelIDFile)
+		if err != nil {
+			return opts, fmt.Errorf("discord routes[%d]: %w", i, err)
+		}
+		r.ChannelID = channelID
+		r.ChannelIDFile = ""
+		for _, f := range r.Factions {
+			if _, ok := domain.ParseEnemy(f); !ok {
+				return opts, fmt.Errorf("discord routes[%d]: unknown faction %q", i, f)
+			}
+		}
+		for _, k := range r.Events {
+			if !k.Valid() {
+				return opts, fmt.Errorf("discord routes[%d]: unknown event kind %q", i, k)
+			}
+		}
 	}
-	opts.ChannelID = channelID
-	opts.ChannelIDFile = ""
 
 	return opts, nil
 }
 
+// resolveDiscordWebhook resolves the options of a Discord notifier in webhook
+// mode, which has no bot and so no channel or bot-only features.
+func resolveDiscordWebhook(opts DiscordOptions) (DiscordOptions, error) {
+	url, err := resolveValue("webhook_url", opts.WebhookURL, opts.WebhookURLFile)
+	if err != nil {
+		return opts, fmt.Errorf("discord webhook_url: %w", err)
+	}
+	opts.WebhookURL = url
+	opts.WebhookURLFile = ""
+
+	switch {
+	case opts.Token != "" || opts.TokenFile != "":
+		return opts, fmt.Errorf("discord: token and webhook_url are mutually exclusive")
+	case opts.ChannelID != "" || opts.ChannelIDFile != "":
+		return opts, fmt.Errorf("discord: channel_id is not used with webhook_url")
+	case len(opts.Routes) > 0 || opts.BoardChannelID != "" || opts.Subscriptions || opts.ScheduledEvents || opts.Threads || opts.Presence != nil || len(opts.AdminRoles) > 0:
+		return opts, fmt.Errorf("discord: routes, board_channel_id, subscriptions, scheduled_events, threads, presence and admin_roles need a bot token, not webhook_url")
+	}
+	for f := range opts.FactionProfiles {
+		if _, ok := domain.ParseEnemy(f); !ok {
+			return opts, fmt.Errorf("discord faction_profiles: unknown faction %q", f)
+		}
+	}
+	return opts, nil
+}
+
+// validateMention checks that a mention rule pings someone and only names
+// known factions and event kinds.
+func validateMention(m DiscordMention) error {
+	if len(m.Roles) == 0 && len(m.Users) == 0 && !m.Everyone {
+		return fmt.Errorf("at least one of roles, users or everyone is required")
+	}
+	for _, f := range m.Factions {
+		if _, ok := domain.ParseEnemy(f); !ok {
+			return fmt.Errorf("unknown faction %q", f)
+		}
+	}
+	for _, k := range m.Events {
+		if !k.Valid() {
+			return fmt.Errorf("unknown event kind %q", k)
+		}
+	}
+	return nil
+}
+
 // ResolveTelegramOptions decodes, validates, and resolves telegram notifier options.
 func ResolveTelegramOptions(raw RawOptions) (TelegramOptions, error) {
 	opts := TelegramOptions{}
@@ -169,6 +300,12 @@ func ResolveTelegramOptions(raw RawOptions) (TelegramOptions, error) {
 	if err := yaml.Unmarshal(data, &opts); err != nil {
 		return opts, fmt.Errorf("parsing telegram options: %w", err)
 	}
+	if err := validateLocale(opts.Locale); err != nil {
+		return opts, err
+	}
+	if err := validateStyle(opts.Style); err != nil {
+		return opts, err
+	}
 
 	token, err := resolveValue("token", opts.Token, opts.TokenFile)
 	if err != nil {
@@ -184,9 +321,64 @@ func ResolveTelegramOptions(raw RawOptions) (TelegramOptions, error) {
 	opts.ChatID = chatID
 	opts.ChatIDFile = ""
 
+	if opts.MessageThreadID < 0 {
+		return opts, fmt.Errorf("telegram message_thread_id must not be negative")
+	}
+	for f, id := range opts.FactionThreads {
+		if _, ok := domain.ParseEnemy(f); !ok {
+			return opts, fmt.Errorf("telegram faction_threads: unknown faction %q", f)
+		}
+		if id <= 0 {
+			return opts, fmt.Errorf("telegram faction_threads: %s thread must be positive", f)
+		}
+	}
+
+	for _, id := range slices.Concat(opts.AllowedChats, opts.AllowedUsers) {
+		if _, err := strconv.ParseInt(id, 10, 64); err != nil {
+			return opts, fmt.Errorf("telegram allowed_chats and allowed_users must be numeric IDs, got %q", id)
+		}
+	}
+
+	if opts.Webhook != nil {
+		if err := resolveTelegramWebhook(opts.Webhook); err != nil {
+			return opts, fmt.Errorf("telegram webhook: %w", err)
+		}
+	}
+
 	return opts, nil
 }
 
+// resolveTelegramWebhook checks the webhook URL, which Telegram only accepts
+// over HTTPS, and resolves the secret token.
+func resolveTelegramWebhook(w *TelegramWebhook) error {
+	w.URL = resolveEnvVars(w.URL)
+	u, err := url.Parse(w.URL)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("url %q must be an https URL", w.URL)
+	}
+
+	secret, err := resolveValue("secret_token", w.SecretToken, w.SecretTokenFile)
+	if err != nil {
+		return err
+	}
+	if !secretTokenPattern.MatchString(secret) {
+		return fmt.Errorf("secret_token must be 1-256 characters of A-Z, a-z, 0-9, _ and -")
+	}
+	w.SecretToken = secret
+	w.SecretTokenFile = ""
+	return nil
+}
+
+// webhookPath returns the path of a webhook URL, as served by hellbot's HTTP
+// server.
+func webhookPath(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Path == "" {
+		return "/"
+	}
+	return u.Path
+}
+
 // ResolveSQLiteStoreOptions decodes and validates SQLite store options.
 func ResolveSQLiteStoreOptions(raw RawOptions) (SQLiteStoreOptions, error) {
 	opts := SQLiteStoreOptions{
@@ -243,10 +435,14 @@ func Load(path string) (*Config, error) {
 	}
 
 	cfg := &Config{
-		Timezone:  raw.Timezone,
-		Dev:       raw.Dev,
-		Store:     raw.Store,
-		Notifiers: raw.Notifiers,
+		TemplateValidation: raw.TemplateValidation,
+		Timezone:           raw.Timezone,
+		Locale:             raw.Locale,
+		Dev:                raw.Dev,
+		Store:              raw.Store,
+		HTTP:               raw.HTTP,
+		Milestones:         raw.Milestones,
+		Notifiers:          raw.Notifiers,
 	}
 
 	// Parse poll interval
@@ -260,6 +456,20 @@ func Load(path string) (*Config, error) {
 		cfg.PollInterval = d
 	}
 
+	players, err := parsePlayersConfig(raw.Players)
+	if err != nil {
+		return nil, fmt.Errorf("players: %w", err)
+	}
+	cfg.Players = players
+
+	if err := validateMilestones(cfg.Milestones); err != nil {
+		return nil, fmt.Errorf("milestones: %w", err)
+	}
+
+	if cfg.HTTP.Listen == "" {
+		cfg.HTTP.Listen = defaultHTTPListen
+	}
+
 	// Apply default timezone
 	if cfg.Timezone == "" {
 		cfg.Timezone = defaultTimezone
@@ -270,6 +480,21 @@ func Load(path string) (*Config, error) {
 		return nil, err
 	}
 
+	switch cfg.TemplateValidation {
+	case "":
+		cfg.TemplateValidation = TemplateValidationWarn
+	case TemplateValidationWarn, TemplateValidationStrict:
+	default:
+		return nil, fmt.Errorf("invalid template_validation %q", cfg.TemplateValidation)
+	}
+
+	if cfg.Locale == "" {
+		cfg.Locale = domain.DefaultLocale
+	}
+	if err := validateLocale(cfg.Locale); err != nil {
+		return nil, err
+	}
+
 	// Validate store config
 	switch cfg.Store.Type {
 	case StoreTypeMemory, "":
@@ -288,6 +513,7 @@ func Load(path string) (*Config, error) {
 
 	// Validate all notifiers
 	ids := make(map[string]struct{})
+	webhookPaths := make(map[string]string)
 	for i, n := range cfg.Notifiers {
 		if n.ID == "" {
 			return nil, fmt.Errorf("notifier[%d]: id is required", i)
@@ -297,19 +523,45 @@ func Load(path string) (*Config, error) {
 		}
 		ids[n.ID] = struct{}{}
 
+		for _, k := range n.Events {
+			if !k.Valid() {
+				return nil, fmt.Errorf("notifier %q: unknown event kind %q", n.ID, k)
+			}
+		}
+
+		var templates *domain.Templates
+		var routeTemplates []*domain.Templates
+		var presence *DiscordPresence
 		switch n.Type {
 		case NotifierTypeStdout:
-			if _, err := ResolveStdoutOptions(n.Options); err != nil {
+			opts, err := ResolveStdoutOptions(n.Options)
+			if err != nil {
 				return nil, fmt.Errorf("notifier %q: %w", n.ID, err)
 			}
+			templates = opts.Templates
 		case NotifierTypeDiscord:
-			if _, err := ResolveDiscordOptions(n.Options); err != nil {
+			opts, err := ResolveDiscordOptions(n.Options)
+			if err != nil {
 				return nil, fmt.Errorf("notifier %q: %w", n.ID, err)
 			}
+			templates = opts.Templates
+			for _, r := range opts.Routes {
+				routeTemplates = append(routeTemplates, r.Templates)
+			}
+			presence = opts.Presence
 		case NotifierTypeTelegram:
-			if _, err := ResolveTelegramOptions(n.Options); err != nil {
+			opts, err := ResolveTelegramOptions(n.Options)
+			if err != nil {
 				return nil, fmt.Errorf("notifier %q: %w", n.ID, err)
 			}
+			if opts.Webhook != nil {
+				path := webhookPath(opts.Webhook.URL)
+				if other, dup := webhookPaths[path]; dup {
+					return nil, fmt.Errorf("notifier %q: webhook path %q is already used by notifier %q", n.ID, path, other)
+				}
+				webhookPaths[path] = n.ID
+			}
+			templates = opts.Templates
 		case NotifierTypeWebhook:
 			if _, err := ResolveWebhookOptions(n.Options); err != nil {
 				return nil, fmt.Errorf("notifier %q: %w", n.ID, err)
@@ -317,11 +569,104 @@ func Load(path string) (*Config, error) {
 		default:
 			return nil, fmt.Errorf("notifier %q: unknown type %q", n.ID, n.Type)
 		}
+
+		if templates != nil {
+			if err := cfg.checkTemplates(n.ID, "templates", domain.ValidateTemplates(*templates)); err != nil {
+				return nil, err
+			}
+		}
+		for j, t := range routeTemplates {
+			if t != nil {
+				if err := cfg.checkTemplates(n.ID, fmt.Sprintf("routes[%d].templates", j), domain.ValidateTemplates(*t)); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if presence != nil {
+			if err := cfg.checkTemplates(n.ID, "presence", domain.ValidatePresence(presence.Defend, presence.Attack, presence.War)); err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	return cfg, nil
 }
 
+// checkTemplates reports the problems found in a notifier's templates under
+// field. They are returned as an error in strict mode and appended to
+// cfg.Warnings otherwise.
+func (cfg *Config) checkTemplates(notifierID, field string, problems []domain.TemplateProblem) error {
+	if len(problems) == 0 {
+		return nil
+	}
+	msgs := make([]string, len(problems))
+	for i, p := range problems {
+		msgs[i] = fmt.Sprintf("notifier %q: %s.%s", notifierID, field, p)
+	}
+	if cfg.TemplateValidation == TemplateValidationStrict {
+		return fmt.Errorf("invalid templates: %s", strings.Join(msgs, "; "))
+	}
+	cfg.Warnings = append(cfg.Warnings, msgs...)
+	return nil
+}
+
+// parsePlayersConfig validates player alert rules and applies defaults.
+func parsePlayersConfig(raw rawPlayersConfig) (PlayersConfig, error) {
+	cfg := PlayersConfig{
+		Thresholds:          raw.Thresholds,
+		ThresholdHysteresis: defaultHysteresis,
+		ChangePercent:       raw.ChangePercent,
+		ChangeWindow:        defaultChangeWindow,
+	}
+	for _, t := range raw.Thresholds {
+		if t <= 0 {
+			return cfg, fmt.Errorf("threshold %d must be positive", t)
+		}
+	}
+	if h := raw.ThresholdHysteresis; h != nil {
+		if *h < 0 || *h >= 100 {
+			return cfg, fmt.Errorf("threshold_hysteresis %d must be between 0 and 99", *h)
+		}
+		cfg.ThresholdHysteresis = *h
+	}
+	if raw.ChangePercent < 0 {
+		return cfg, fmt.Errorf("change_percent %d must not be negative", raw.ChangePercent)
+	}
+	if raw.ChangeWindow != "" {
+		d, err := time.ParseDuration(raw.ChangeWindow)
+		if err != nil {
+			return cfg, fmt.Errorf("invalid change_window %q: %w", raw.ChangeWindow, err)
+		}
+		if d <= 0 {
+			return cfg, fmt.Errorf("change_window %q must be positive", raw.ChangeWindow)
+		}
+		cfg.ChangeWindow = d
+	}
+	return cfg, nil
+}
+
+// validateMilestones checks that every configured milestone is positive.
+func validateMilestones(m MilestonesConfig) error {
+	counters := []struct {
+		name   string
+		values []int
+	}{
+		{"kills", m.Kills},
+		{"deaths", m.Deaths},
+		{"accidentals", m.Accidentals},
+		{"missions", m.Missions},
+		{"planets_liberated", m.PlanetsLiberated},
+	}
+	for _, c := range counters {
+		for _, v := range c.values {
+			if v <= 0 {
+				return fmt.Errorf("%s: milestone %d must be positive", c.name, v)
+			}
+		}
+	}
+	return nil
+}
+
 // FindConfigPath returns the config file path using the following priority:
 // 1. Explicit path argument (from --config flag)
 // 2. HELLBOT_CONFIG environment variable
@@ -2,7 +2,11 @@ package config
 
 import (
 	"os"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/ametis70/hellbot/internal/domain"
 )
 
 // --- ResolveWebhookOptions ---
@@ -162,6 +166,145 @@ func TestResolveTelegramOptions_MissingChatID(t *testing.T) {
 	}
 }
 
+func TestResolveTelegramOptions_Webhook(t *testing.T) {
+	t.Setenv("TG_SECRET", "s3cret_token-1")
+	opts, err := ResolveTelegramOptions(RawOptions{"token": "tok", "chat_id": "-1", "webhook": map[string]any{
+		"url":          "https://bot.example.com/telegram",
+		"secret_token": "${TG_SECRET}",
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if opts.Webhook.URL != "https://bot.example.com/telegram" || opts.Webhook.SecretToken != "s3cret_token-1" {
+		t.Errorf("unexpected webhook %+v", opts.Webhook)
+	}
+
+	for name, webhook := range map[string]map[string]any{
+		"http url":       {"url": "http://bot.example.com/telegram", "secret_token": "abc"},
+		"missing url":    {"secret_token": "abc"},
+		"missing secret": {"url": "https://bot.example.com/telegram"},
+		"bad secret":     {"url": "https://bot.example.com/telegram", "secret_token": "not allowed!"},
+	} {
+		if _, err := ResolveTelegramOptions(RawOptions{"token": "tok", "chat_id": "-1", "webhook": webhook}); err == nil {
+			t.Errorf("%s: expected error, got nil", name)
+		}
+	}
+}
+
+func TestLoad_HTTPListen(t *testing.T) {
+	cfg, err := Load(writeConfig(t, `
+notifiers:
+  - id: "s"
+    type: stdout
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.HTTP.Listen != defaultHTTPListen {
+		t.Errorf("expected default listen %q, got %q", defaultHTTPListen, cfg.HTTP.Listen)
+	}
+
+	cfg, err = Load(writeConfig(t, `
+http:
+  listen: "127.0.0.1:9000"
+notifiers:
+  - id: "s"
+    type: stdout
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.HTTP.Listen != "127.0.0.1:9000" {
+		t.Errorf("unexpected listen %q", cfg.HTTP.Listen)
+	}
+}
+
+func TestLoad_DuplicateTelegramWebhookPath(t *testing.T) {
+	_, err := Load(writeConfig(t, `
+notifiers:
+  - id: "a"
+    type: telegram
+    options:
+      token: "tok-a"
+      chat_id: "-1"
+      webhook:
+        url: "https://a.example.com/telegram"
+        secret_token: "abc"
+  - id: "b"
+    type: telegram
+    options:
+      token: "tok-b"
+      chat_id: "-2"
+      webhook:
+        url: "https://b.example.com/telegram"
+        secret_token: "abc"
+`))
+	if err == nil || !strings.Contains(err.Error(), "already used") {
+		t.Errorf("expected a duplicate path error, got %v", err)
+	}
+}
+
+func TestResolveTelegramOptions_Subscriptions(t *testing.T) {
+	opts, err := ResolveTelegramOptions(RawOptions{"token": "tok", "chat_id": "-1", "subscriptions": true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !opts.Subscriptions {
+		t.Error("expected subscriptions to be enabled")
+	}
+}
+
+func TestResolveTelegramOptions_Threads(t *testing.T) {
+	opts, err := ResolveTelegramOptions(RawOptions{
+		"token":             "tok",
+		"chat_id":           "-1",
+		"message_thread_id": 5,
+		"faction_threads":   map[string]any{"bugs": 7, "illuminate": 9},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if opts.MessageThreadID != 5 || opts.FactionThreads["bugs"] != 7 || opts.FactionThreads["illuminate"] != 9 {
+		t.Errorf("unexpected threads %d %v", opts.MessageThreadID, opts.FactionThreads)
+	}
+
+	_, err = ResolveTelegramOptions(RawOptions{"token": "tok", "chat_id": "-1", "faction_threads": map[string]any{"automatons": 3}})
+	if err == nil || !strings.Contains(err.Error(), "unknown faction") {
+		t.Errorf("expected an unknown faction error, got %v", err)
+	}
+	_, err = ResolveTelegramOptions(RawOptions{"token": "tok", "chat_id": "-1", "faction_threads": map[string]any{"bugs": 0}})
+	if err == nil || !strings.Contains(err.Error(), "positive") {
+		t.Errorf("expected a positive thread error, got %v", err)
+	}
+	_, err = ResolveTelegramOptions(RawOptions{"token": "tok", "chat_id": "-1", "message_thread_id": -1})
+	if err == nil || !strings.Contains(err.Error(), "must not be negative") {
+		t.Errorf("expected a negative thread error, got %v", err)
+	}
+}
+
+func TestResolveTelegramOptions_AllowedChatsAndUsers(t *testing.T) {
+	opts, err := ResolveTelegramOptions(RawOptions{
+		"token":         "tok",
+		"chat_id":       "-1",
+		"allowed_chats": []any{-1001234567890, "-42"},
+		"allowed_users": []any{123456},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(opts.AllowedChats) != 2 || opts.AllowedChats[0] != "-1001234567890" || opts.AllowedChats[1] != "-42" {
+		t.Errorf("unexpected allowed_chats %v", opts.AllowedChats)
+	}
+	if len(opts.AllowedUsers) != 1 || opts.AllowedUsers[0] != "123456" {
+		t.Errorf("unexpected allowed_users %v", opts.AllowedUsers)
+	}
+
+	_, err = ResolveTelegramOptions(RawOptions{"token": "tok", "chat_id": "-1", "allowed_users": []any{"@someone"}})
+	if err == nil || !strings.Contains(err.Error(), "numeric") {
+		t.Errorf("expected a numeric ID error, got %v", err)
+	}
+}
+
 // --- ResolveSQLiteStoreOptions ---
 
 func TestResolveSQLiteStoreOptions_Defaults(t *testing.T) {
@@ -336,3 +479,416 @@ notifiers:
 
 // ensure os import is used
 var _ = os.Getenv
+
+func TestLoad_PlayersConfig(t *testing.T) {
+	path := writeConfig(t, `
+players:
+  thresholds: [5000, 10000]
+  change_percent: 30
+  change_window: 30m
+notifiers:
+  - id: "console"
+    type: stdout
+    events: [defend, players]
+`)
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cfg.Players.Thresholds) != 2 || cfg.Players.ChangePercent != 30 {
+		t.Errorf("unexpected players config: %+v", cfg.Players)
+	}
+	if cfg.Players.ChangeWindow != 30*time.Minute {
+		t.Errorf("expected 30m change window, got %s", cfg.Players.ChangeWindow)
+	}
+	if len(cfg.Notifiers[0].Events) != 2 || cfg.Notifiers[0].Events[1] != domain.EventKindPlayers {
+		t.Errorf("unexpected notifier events: %v", cfg.Notifiers[0].Events)
+	}
+}
+
+func TestLoad_PlayersDefaultWindow(t *testing.T) {
+	path := writeConfig(t, `
+players:
+  change_percent: 30
+`)
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Players.ChangeWindow != time.Hour {
+		t.Errorf("expected default 1h change window, got %s", cfg.Players.ChangeWindow)
+	}
+	if cfg.Players.ThresholdHysteresis != 5 {
+		t.Errorf("expected default 5%% hysteresis, got %d", cfg.Players.ThresholdHysteresis)
+	}
+}
+
+func TestLoad_PlayersHysteresisDisabled(t *testing.T) {
+	path := writeConfig(t, `
+players:
+  thresholds: [5000]
+  threshold_hysteresis: 0
+`)
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Players.ThresholdHysteresis != 0 {
+		t.Errorf("expected hysteresis 0, got %d", cfg.Players.ThresholdHysteresis)
+	}
+}
+
+func TestLoad_PlayersInvalid(t *testing.T) {
+	cases := map[string]string{
+		"negative threshold": "players:\n  thresholds: [-1]\n",
+		"negative percent":   "players:\n  change_percent: -5\n",
+		"hysteresis too big": "players:\n  threshold_hysteresis: 100\n",
+		"bad window":         "players:\n  change_window: soon\n",
+	}
+	for name, content := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Load(writeConfig(t, content)); err == nil {
+				t.Error("expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestLoad_UnknownEventKind(t *testing.T) {
+	path := writeConfig(t, `
+notifiers:
+  - id: "console"
+    type: stdout
+    events: [pizza]
+`)
+	if _, err := Load(path); err == nil {
+		t.Error("expected error for unknown event kind, got nil")
+	}
+}
+
+func TestLoad_Milestones(t *testing.T) {
+	path := writeConfig(t, `
+milestones:
+  kills: [1000000, 10000000]
+  planets_liberated: [100]
+`)
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cfg.Milestones.Kills) != 2 || len(cfg.Milestones.PlanetsLiberated) != 1 {
+		t.Errorf("unexpected milestones config: %+v", cfg.Milestones)
+	}
+}
+
+func TestLoad_MilestonesInvalid(t *testing.T) {
+	path := writeConfig(t, `
+milestones:
+  deaths: [0]
+`)
+	if _, err := Load(path); err == nil {
+		t.Error("expected error for non-positive milestone, got nil")
+	}
+}
+
+func TestLoad_MilestonesInvalid_ReportsFirstCounter(t *testing.T) {
+	path := writeConfig(t, `
+milestones:
+  planets_liberated: [-1]
+  missions: [0]
+  kills: [-5]
+`)
+	for range 10 {
+		_, err := Load(path)
+		if err == nil || !strings.Contains(err.Error(), "kills: milestone -5") {
+			t.Fatalf("expected the kills error first, got %v", err)
+		}
+	}
+}
+
+const templateTypoConfig = `
+notifiers:
+  - id: console
+    type: stdout
+    options:
+      templates:
+        defend_region_started: "{FACTION} on {REGON_NAME}"
+        war_won: "War {SEASON} won in {REGION_NAME}"
+`
+
+func TestLoad_TemplateValidation_WarnByDefault(t *testing.T) {
+	cfg, err := Load(writeConfig(t, templateTypoConfig))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.TemplateValidation != TemplateValidationWarn {
+		t.Errorf("expected default warn mode, got %q", cfg.TemplateValidation)
+	}
+	if len(cfg.Warnings) != 2 {
+		t.Fatalf("expected 2 warnings, got %v", cfg.Warnings)
+	}
+	if !strings.Contains(cfg.Warnings[0], `notifier "console": templates.defend_region_started: unknown placeholder {REGON_NAME}`) {
+		t.Errorf("unexpected warning: %s", cfg.Warnings[0])
+	}
+	if !strings.Contains(cfg.Warnings[1], "templates.war_won") {
+		t.Errorf("unexpected warning: %s", cfg.Warnings[1])
+	}
+}
+
+func TestLoad_TemplateValidation_Strict(t *testing.T) {
+	_, err := Load(writeConfig(t, "template_validation: strict\n"+templateTypoConfig))
+	if err == nil {
+		t.Fatal("expected error in strict mode, got nil")
+	}
+	if !strings.Contains(err.Error(), "{REGON_NAME}") || !strings.Contains(err.Error(), "war_won") {
+		t.Errorf("expected all problems in error, got %v", err)
+	}
+}
+
+func TestLoad_TemplateValidation_Invalid(t *testing.T) {
+	if _, err := Load(writeConfig(t, "template_validation: loose\n")); err == nil {
+		t.Error("expected error for unknown template_validation, got nil")
+	}
+}
+
+func TestLoad_LocaleDefault(t *testing.T) {
+	cfg, err := Load(writeConfig(t, ""))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Locale != domain.LocaleEnglish {
+		t.Errorf("expected default locale en, got %q", cfg.Locale)
+	}
+}
+
+func TestLoad_LocaleSpanish(t *testing.T) {
+	cfg, err := Load(writeConfig(t, "locale: es\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Locale != domain.LocaleSpanish {
+		t.Errorf("expected locale es, got %q", cfg.Locale)
+	}
+}
+
+func TestLoad_LocaleInvalid(t *testing.T) {
+	if _, err := Load(writeConfig(t, "locale: xx\n")); err == nil {
+		t.Error("expected error for unknown locale, got nil")
+	}
+}
+
+func TestResolveStdoutOptions_LocaleInvalid(t *testing.T) {
+	if _, err := ResolveStdoutOptions(RawOptions{"locale": "xx"}); err == nil {
+		t.Error("expected error for unknown notifier locale, got nil")
+	}
+}
+
+func TestResolveStdoutOptions_Style(t *testing.T) {
+	opts, err := ResolveStdoutOptions(RawOptions{"style": "structured"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if opts.Style != domain.MessageStyleStructured {
+		t.Errorf("expected structured style, got %q", opts.Style)
+	}
+	if _, err := ResolveStdoutOptions(RawOptions{"style": "fancy"}); err == nil {
+		t.Error("expected error for unknown style, got nil")
+	}
+}
+
+func TestResolveDiscordOptions_Embeds(t *testing.T) {
+	raw := RawOptions{"token": "tok", "channel_id": "123", "embeds": []any{"defend", "attack"}}
+	opts, err := ResolveDiscordOptions(raw)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(opts.Embeds) != 2 || opts.Embeds[0] != domain.EventKindDefend {
+		t.Errorf("unexpected embeds %v", opts.Embeds)
+	}
+
+	raw["embeds"] = []any{"defence"}
+	if _, err := ResolveDiscordOptions(raw); err == nil {
+		t.Error("expected error for unknown embed kind, got nil")
+	}
+}
+
+func TestResolveDiscordOptions_BoardChannel(t *testing.T) {
+	opts, err := ResolveDiscordOptions(RawOptions{"token": "tok", "channel_id": "123", "board_channel_id": "456"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if opts.BoardChannelID != "456" {
+		t.Errorf("expected board channel 456, got %q", opts.BoardChannelID)
+	}
+}
+
+func TestResolveDiscordOptions_Mentions(t *testing.T) {
+	raw := RawOptions{"token": "tok", "channel_id": "123", "mentions": []any{
+		map[string]any{"factions": []any{"bugs"}, "events": []any{"attack"}, "roles": []any{"111"}},
+		map[string]any{"super_earth": true, "everyone": true},
+	}}
+	opts, err := ResolveDiscordOptions(raw)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(opts.Mentions) != 2 || opts.Mentions[0].Roles[0] != "111" || !opts.Mentions[1].SuperEarth {
+		t.Errorf("unexpected mentions %+v", opts.Mentions)
+	}
+
+	for _, bad := range []map[string]any{
+		{"factions": []any{"bugs"}},
+		{"factions": []any{"automatons"}, "everyone": true},
+		{"events": []any{"defence"}, "everyone": true},
+	} {
+		raw["mentions"] = []any{bad}
+		if _, err := ResolveDiscordOptions(raw); err == nil {
+			t.Errorf("expected error for %v, got nil", bad)
+		}
+	}
+}
+
+func TestResolveDiscordOptions_Routes(t *testing.T) {
+	raw := RawOptions{"token": "tok", "routes": []any{
+		map[string]any{"channel_id": "111", "factions": []any{"bugs"}},
+		map[string]any{"channel_id": "222", "events": []any{"war"}},
+	}}
+	opts, err := ResolveDiscordOptions(raw)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if opts.ChannelID != "" || len(opts.Routes) != 2 || opts.Routes[1].ChannelID != "222" {
+		t.Errorf("unexpected options %+v", opts)
+	}
+
+	for _, bad := range []map[string]any{
+		{"factions": []any{"bugs"}},
+		{"channel_id": "111", "factions": []any{"automatons"}},
+		{"channel_id": "111", "events": []any{"defence"}},
+	} {
+		raw["routes"] = []any{bad}
+		if _, err := ResolveDiscordOptions(raw); err == nil {
+			t.Errorf("expected error for %v, got nil", bad)
+		}
+	}
+
+	if _, err := ResolveDiscordOptions(RawOptions{"token": "tok"}); err == nil {
+		t.Error("expected error without channel_id or routes, got nil")
+	}
+}
+
+func TestLoad_TemplateValidation_DiscordRoutes(t *testing.T) {
+	cfg, err := Load(writeConfig(t, `
+notifiers:
+  - id: discord
+    type: discord
+    options:
+      token: tok
+      routes:
+        - channel_id: "111"
+          templates:
+            war_won: "War {SEASON} won in {REGION_NAME}"
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cfg.Warnings) != 1 || !strings.Contains(cfg.Warnings[0], `notifier "discord": routes[0].templates.war_won`) {
+		t.Errorf("unexpected warnings %v", cfg.Warnings)
+	}
+}
+
+func TestLoad_TemplateValidation_DiscordPresence(t *testing.T) {
+	cfg, err := Load(writeConfig(t, `
+notifiers:
+  - id: discord
+    type: discord
+    options:
+      token: tok
+      channel_id: "111"
+      presence:
+        war: "War {SEASON}: {PLAYERS} online"
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cfg.Warnings) != 1 || !strings.Contains(cfg.Warnings[0], `notifier "discord": presence.war: placeholder {PLAYERS} is not available here`) {
+		t.Errorf("unexpected warnings %v", cfg.Warnings)
+	}
+}
+
+func TestResolveDiscordOptions_Webhook(t *testing.T) {
+	raw := RawOptions{
+		"webhook_url": "https://discord.com/api/webhooks/1/abc",
+		"username":    "Super Earth Command",
+		"faction_profiles": map[string]any{
+			"bugs": map[string]any{"username": "Bug Front", "avatar_url": "https://example.com/bugs.png"},
+		},
+	}
+	opts, err := ResolveDiscordOptions(raw)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if opts.WebhookURL != "https://discord.com/api/webhooks/1/abc" || opts.Username != "Super Earth Command" {
+		t.Errorf("unexpected options %+v", opts)
+	}
+	if opts.FactionProfiles["bugs"].Username != "Bug Front" {
+		t.Errorf("unexpected faction profiles %+v", opts.FactionProfiles)
+	}
+
+	for key, value := range map[string]any{
+		"token":            "tok",
+		"channel_id":       "123",
+		"board_channel_id": "123",
+		"subscriptions":    true,
+		"scheduled_events": true,
+		"threads":          true,
+		"presence":         map[string]any{},
+		"admin_roles":      []any{"123"},
+		"faction_profiles": map[string]any{"automatons": map[string]any{"username": "x"}},
+	} {
+		bad := RawOptions{"webhook_url": "https://discord.com/api/webhooks/1/abc", key: value}
+		if _, err := ResolveDiscordOptions(bad); err == nil {
+			t.Errorf("expected error with %s, got nil", key)
+		}
+	}
+}
+
+func TestResolveDiscordOptions_ScheduledEventsNeedGuild(t *testing.T) {
+	raw := RawOptions{"token": "tok", "channel_id": "123", "scheduled_events": true}
+	if _, err := ResolveDiscordOptions(raw); err == nil {
+		t.Error("expected error without guild_id, got nil")
+	}
+	raw["guild_id"] = "456"
+	if opts, err := ResolveDiscordOptions(raw); err != nil || !opts.ScheduledEvents {
+		t.Errorf("expected scheduled events with guild_id, got %v %+v", err, opts)
+	}
+}
+
+func TestResolveDiscordOptions_Presence(t *testing.T) {
+	raw := RawOptions{"token": "tok", "channel_id": "123", "presence": map[string]any{
+		"war":      "War {SEASON}",
+		"interval": "2m",
+	}}
+	opts, err := ResolveDiscordOptions(raw)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if opts.Presence == nil || opts.Presence.War != "War {SEASON}" || opts.Presence.Interval != 2*time.Minute {
+		t.Errorf("unexpected presence %+v", opts.Presence)
+	}
+
+	raw["presence"] = map[string]any{"interval": "10s"}
+	if _, err := ResolveDiscordOptions(raw); err == nil {
+		t.Error("expected error for an interval below one minute, got nil")
+	}
+}
+
+func TestResolveDiscordOptions_AdminRoles(t *testing.T) {
+	raw := RawOptions{"token": "tok", "channel_id": "123", "admin_roles": []any{"111", "222"}}
+	opts, err := ResolveDiscordOptions(raw)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(opts.AdminRoles) != 2 || opts.AdminRoles[0] != "111" || opts.AdminRoles[1] != "222" {
+		t.Errorf("unexpected admin roles %v", opts.AdminRoles)
+	}
+}
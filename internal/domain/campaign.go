@@ -106,3 +106,38 @@ type CampaignStatus struct {
 	AttackEvents   []AttackEvent
 	Statistics     []Statistics
 }
+
+// Season returns the current war number, taken from the first faction.
+// It returns 0 when no faction status is available.
+func (c *CampaignStatus) Season() int {
+	if len(c.FactionsStatus) == 0 {
+		return 0
+	}
+	return c.FactionsStatus[0].Season
+}
+
+// Sector returns the 1-based sector the faction's front line is in, as shown
+// by FormatStatus.
+func (f FactionStatus) Sector() int {
+	n, _, _ := f.sectorProgress()
+	return n + 1
+}
+
+// sectorProgress splits the faction's points into whole sectors earned and the
+// remainder within the current sector.
+// sectorsEarned = floor(points / pointsPerSector), capped at TotalRegions.
+func (f FactionStatus) sectorProgress() (earned, points, pointsMax int) {
+	if f.PointsMax <= 0 {
+		return 0, 0, 0
+	}
+	pointsPerSector := f.PointsMax / TotalRegions
+	if pointsPerSector <= 0 {
+		return 0, 0, 0
+	}
+	sectorsEarned := f.Points / pointsPerSector
+	earned = sectorsEarned
+	if earned > TotalRegions {
+		earned = TotalRegions
+	}
+	return earned, f.Points - sectorsEarned*pointsPerSector, pointsPerSector
+}
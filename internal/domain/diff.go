@@ -0,0 +1,272 @@
+package domain
+
+import "time"
+
+// ChangeKind identifies the type of a Change.
+type ChangeKind string
+
+const (
+	ChangeDefendStarted ChangeKind = "defend_started"
+	ChangeDefendEnded   ChangeKind = "defend_ended"
+	ChangeAttackStarted ChangeKind = "attack_started"
+	ChangeAttackEnded   ChangeKind = "attack_ended"
+	ChangeFactionStatus ChangeKind = "faction_status"
+	ChangeSector        ChangeKind = "sector"
+	ChangeSeason        ChangeKind = "season"
+	ChangeStatistics    ChangeKind = "statistics"
+)
+
+// FactionChange describes a faction moving between active, defeated and hidden.
+type FactionChange struct {
+	Enemy Enemy
+	From  FactionStatusKind
+	To    FactionStatusKind
+}
+
+// SectorChange describes a faction's front line moving to another sector.
+// Sectors are numbered 1–11 as in FormatStatus.
+type SectorChange struct {
+	Enemy Enemy
+	From  int
+	To    int
+}
+
+// SeasonChange describes the end of a war. Won is true when every non-hidden
+// faction was defeated in the final snapshot of the previous season.
+// Duration is the previous season's length from its statistics.
+type SeasonChange struct {
+	From     int
+	To       int
+	Won      bool
+	Duration time.Duration
+}
+
+// StatisticsChange holds the summed statistics before and after, and their
+// difference. Enemy and Season are not meaningful on the summed values.
+type StatisticsChange struct {
+	From  Statistics
+	To    Statistics
+	Delta Statistics
+}
+
+// Change is a single typed difference between two campaign snapshots.
+// Exactly one of the pointer fields is set, matching Kind.
+type Change struct {
+	Kind        ChangeKind
+	DefendEvent *DefendEvent
+	AttackEvent *AttackEvent
+	Faction     *FactionChange
+	Sector      *SectorChange
+	Season      *SeasonChange
+	Statistics  *StatisticsChange
+}
+
+// Diff compares two campaign snapshots and returns the changes between them,
+// without touching any store. A nil previous yields no changes.
+//
+// Ended events carry their final status. A defend event replaced by a new one
+// or gone between polls is reported as ended with its last seen status. Attack
+// events that disappear without a final status are not reported.
+func Diff(previous, current *CampaignStatus) []Change {
+	if previous == nil || current == nil {
+		return nil
+	}
+
+	var changes []Change
+	changes = append(changes, diffDefend(previous.DefendEvent, current.DefendEvent)...)
+	changes = append(changes, diffAttacks(previous.AttackEvents, current.AttackEvents)...)
+	changes = append(changes, diffFactions(previous.FactionsStatus, current.FactionsStatus)...)
+
+	if season := diffSeason(previous, current); season != nil {
+		return append(changes, Change{Kind: ChangeSeason, Season: season})
+	}
+
+	if stats := diffStatistics(previous, current); stats != nil {
+		changes = append(changes, Change{Kind: ChangeStatistics, Statistics: stats})
+	}
+	return changes
+}
+
+// EventMessage converts an event or season change into the notification it
+// corresponds to. It returns false for changes that have no notification.
+func (c Change) EventMessage() (EventMessage, bool) {
+	switch c.Kind {
+	case ChangeDefendStarted:
+		return EventMessage{Kind: EventKindDefend, Transition: EventTransitionStarted, DefendEvent: c.DefendEvent}, true
+	case ChangeDefendEnded:
+		return EventMessage{Kind: EventKindDefend, Transition: outcome(c.DefendEvent.Status), DefendEvent: c.DefendEvent}, true
+	case ChangeAttackStarted:
+		return EventMessage{Kind: EventKindAttack, Transition: EventTransitionStarted, AttackEvent: c.AttackEvent}, true
+	case ChangeAttackEnded:
+		return EventMessage{Kind: EventKindAttack, Transition: outcome(c.AttackEvent.Status), AttackEvent: c.AttackEvent}, true
+	case ChangeSeason:
+		transition := EventTransitionFailed
+		if c.Season.Won {
+			transition = EventTransitionSucceeded
+		}
+		return EventMessage{Kind: EventKindWar, Transition: transition, WarEvent: &WarEvent{
+			Season:   c.Season.From,
+			Duration: c.Season.Duration,
+		}}, true
+	}
+	return EventMessage{}, false
+}
+
+func outcome(status EventStatusKind) EventTransition {
+	if status == EventStatusSuccess {
+		return EventTransitionSucceeded
+	}
+	return EventTransitionFailed
+}
+
+func diffDefend(prev, cur *DefendEvent) []Change {
+	prevActive := prev != nil && prev.Status == EventStatusActive
+	curActive := cur != nil && cur.Status == EventStatusActive
+
+	var changes []Change
+	switch {
+	case prevActive && cur != nil && cur.ID == prev.ID:
+		if !curActive {
+			changes = append(changes, Change{Kind: ChangeDefendEnded, DefendEvent: cur})
+		}
+		return changes
+	case prevActive:
+		// Replaced or gone between polls: report the old one with its last
+		// known status.
+		changes = append(changes, Change{Kind: ChangeDefendEnded, DefendEvent: prev})
+	}
+
+	if curActive && (prev == nil || prev.ID != cur.ID) {
+		changes = append(changes, Change{Kind: ChangeDefendStarted, DefendEvent: cur})
+	}
+	return changes
+}
+
+func diffAttacks(prev, cur []AttackEvent) []Change {
+	prevByID := make(map[int]AttackEvent, len(prev))
+	for _, e := range prev {
+		prevByID[e.ID] = e
+	}
+
+	var changes []Change
+	for _, e := range cur {
+		attack := e
+		p, seen := prevByID[e.ID]
+		wasActive := seen && p.Status == EventStatusActive
+		switch {
+		case e.Status == EventStatusActive && !wasActive:
+			changes = append(changes, Change{Kind: ChangeAttackStarted, AttackEvent: &attack})
+		case e.Status != EventStatusActive && wasActive:
+			changes = append(changes, Change{Kind: ChangeAttackEnded, AttackEvent: &attack})
+		}
+	}
+	return changes
+}
+
+// diffFactions compares factions by position, which is how the API orders
+// them (index = Enemy).
+func diffFactions(prev, cur []FactionStatus) []Change {
+	var changes []Change
+	for i, f := range cur {
+		if i >= len(prev) || prev[i].Season != f.Season {
+			continue
+		}
+		p := prev[i]
+		if p.Status != f.Status {
+			changes = append(changes, Change{Kind: ChangeFactionStatus, Faction: &FactionChange{
+				Enemy: f.Enemy,
+				From:  p.Status,
+				To:    f.Status,
+			}})
+		}
+		if from, to := p.Sector(), f.Sector(); from != to {
+			changes = append(changes, Change{Kind: ChangeSector, Sector: &SectorChange{
+				Enemy: f.Enemy,
+				From:  from,
+				To:    to,
+			}})
+		}
+	}
+	return changes
+}
+
+func diffSeason(prev, cur *CampaignStatus) *SeasonChange {
+	if len(prev.FactionsStatus) == 0 || len(cur.FactionsStatus) == 0 {
+		return nil
+	}
+	if prev.Season() == cur.Season() {
+		return nil
+	}
+
+	// War is won if all non-hidden factions were defeated.
+	won := true
+	for _, f := range prev.FactionsStatus {
+		if f.Status == FactionStatusHidden {
+			continue
+		}
+		if f.Status != FactionStatusDefeated {
+			won = false
+			break
+		}
+	}
+	return &SeasonChange{
+		From:     prev.Season(),
+		To:       cur.Season(),
+		Won:      won,
+		Duration: time.Duration(SumStatistics(prev).SeasonDuration) * time.Second,
+	}
+}
+
+func diffStatistics(prev, cur *CampaignStatus) *StatisticsChange {
+	from := SumStatistics(prev)
+	to := SumStatistics(cur)
+	delta := Statistics{
+		Players:                to.Players - from.Players,
+		TotalUniquePlayers:     to.TotalUniquePlayers - from.TotalUniquePlayers,
+		Missions:               to.Missions - from.Missions,
+		SuccessfulMissions:     to.SuccessfulMissions - from.SuccessfulMissions,
+		TotalMissionDifficulty: to.TotalMissionDifficulty - from.TotalMissionDifficulty,
+		CompletedPlanets:       to.CompletedPlanets - from.CompletedPlanets,
+		DefendEvents:           to.DefendEvents - from.DefendEvents,
+		SuccessfulDefendEvents: to.SuccessfulDefendEvents - from.SuccessfulDefendEvents,
+		AttackEvents:           to.AttackEvents - from.AttackEvents,
+		SuccessfulAttackEvents: to.SuccessfulAttackEvents - from.SuccessfulAttackEvents,
+		Deaths:                 to.Deaths - from.Deaths,
+		Kills:                  to.Kills - from.Kills,
+		Accidentals:            to.Accidentals - from.Accidentals,
+		Shots:                  to.Shots - from.Shots,
+		Hits:                   to.Hits - from.Hits,
+	}
+	if delta == (Statistics{}) {
+		return nil
+	}
+	return &StatisticsChange{From: from, To: to, Delta: delta}
+}
+
+// SumStatistics returns the statistics summed across all factions.
+// Season and SeasonDuration are taken from the first entry; Enemy is left zero.
+func SumStatistics(c *CampaignStatus) Statistics {
+	var sum Statistics
+	for i, s := range c.Statistics {
+		if i == 0 {
+			sum.Season = s.Season
+			sum.SeasonDuration = s.SeasonDuration
+		}
+		sum.Players += s.Players
+		sum.TotalUniquePlayers += s.TotalUniquePlayers
+		sum.Missions += s.Missions
+		sum.SuccessfulMissions += s.SuccessfulMissions
+		sum.TotalMissionDifficulty += s.TotalMissionDifficulty
+		sum.CompletedPlanets += s.CompletedPlanets
+		sum.DefendEvents += s.DefendEvents
+		sum.SuccessfulDefendEvents += s.SuccessfulDefendEvents
+		sum.AttackEvents += s.AttackEvents
+		sum.SuccessfulAttackEvents += s.SuccessfulAttackEvents
+		sum.Deaths += s.Deaths
+		sum.Kills += s.Kills
+		sum.Accidentals += s.Accidentals
+		sum.Shots += s.Shots
+		sum.Hits += s.Hits
+	}
+	return sum
+}
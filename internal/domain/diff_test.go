@@ -0,0 +1,197 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ametis70/hellbot/internal/domain"
+	"github.com/ametis70/hellbot/internal/testutil"
+)
+
+func kinds(changes []domain.Change) []domain.ChangeKind {
+	out := make([]domain.ChangeKind, len(changes))
+	for i, c := range changes {
+		out[i] = c.Kind
+	}
+	return out
+}
+
+func findChange(changes []domain.Change, kind domain.ChangeKind) *domain.Change {
+	for i := range changes {
+		if changes[i].Kind == kind {
+			return &changes[i]
+		}
+	}
+	return nil
+}
+
+func TestDiff_NilPrevious(t *testing.T) {
+	if changes := domain.Diff(nil, testutil.CampaignWithActiveDefend()); changes != nil {
+		t.Errorf("expected no changes, got %v", kinds(changes))
+	}
+}
+
+func TestDiff_Unchanged(t *testing.T) {
+	c := testutil.CampaignWithActiveDefend()
+	if changes := domain.Diff(c, testutil.CampaignWithActiveDefend()); len(changes) != 0 {
+		t.Errorf("expected no changes, got %v", kinds(changes))
+	}
+}
+
+func TestDiff_DefendStarted(t *testing.T) {
+	changes := domain.Diff(testutil.CampaignWithNoDefend(), testutil.CampaignWithActiveDefend())
+	c := findChange(changes, domain.ChangeDefendStarted)
+	if c == nil {
+		t.Fatalf("expected defend_started, got %v", kinds(changes))
+	}
+	msg, ok := c.EventMessage()
+	if !ok || msg.Kind != domain.EventKindDefend || msg.Transition != domain.EventTransitionStarted {
+		t.Errorf("unexpected event message: %+v", msg)
+	}
+}
+
+func TestDiff_DefendEnded(t *testing.T) {
+	changes := domain.Diff(testutil.CampaignWithActiveDefend(), testutil.CampaignWithSucceededDefend())
+	c := findChange(changes, domain.ChangeDefendEnded)
+	if c == nil {
+		t.Fatalf("expected defend_ended, got %v", kinds(changes))
+	}
+	msg, _ := c.EventMessage()
+	if msg.Transition != domain.EventTransitionSucceeded {
+		t.Errorf("expected succeeded, got %s", msg.Transition)
+	}
+}
+
+func TestDiff_DefendReplaced(t *testing.T) {
+	current := testutil.CampaignWithActiveDefend()
+	current.DefendEvent = testutil.DefendEventNewActive()
+	changes := domain.Diff(testutil.CampaignWithActiveDefend(), current)
+
+	ended := findChange(changes, domain.ChangeDefendEnded)
+	if ended == nil || ended.DefendEvent.ID != 5080 {
+		t.Fatalf("expected old defend event to end, got %v", kinds(changes))
+	}
+	started := findChange(changes, domain.ChangeDefendStarted)
+	if started == nil || started.DefendEvent.ID != 5081 {
+		t.Fatalf("expected new defend event to start, got %v", kinds(changes))
+	}
+}
+
+func TestDiff_DefendGone(t *testing.T) {
+	changes := domain.Diff(testutil.CampaignWithActiveDefend(), testutil.CampaignWithNoDefend())
+	c := findChange(changes, domain.ChangeDefendEnded)
+	if c == nil || c.DefendEvent.ID != 5080 {
+		t.Fatalf("expected the vanished defend event to end, got %v", kinds(changes))
+	}
+	if findChange(changes, domain.ChangeDefendStarted) != nil {
+		t.Errorf("expected no defend_started, got %v", kinds(changes))
+	}
+}
+
+func TestDiff_AttackStartedAndEnded(t *testing.T) {
+	changes := domain.Diff(testutil.CampaignWithNoDefend(), testutil.CampaignWithActiveAttack())
+	if c := findChange(changes, domain.ChangeAttackStarted); c == nil || c.AttackEvent.ID != 924 {
+		t.Fatalf("expected attack_started, got %v", kinds(changes))
+	}
+
+	changes = domain.Diff(testutil.CampaignWithActiveAttack(), testutil.CampaignWithEndedAttack())
+	c := findChange(changes, domain.ChangeAttackEnded)
+	if c == nil {
+		t.Fatalf("expected attack_ended, got %v", kinds(changes))
+	}
+	msg, _ := c.EventMessage()
+	if msg.Transition != domain.EventTransitionSucceeded {
+		t.Errorf("expected succeeded, got %s", msg.Transition)
+	}
+}
+
+func TestDiff_FactionStatusAndSector(t *testing.T) {
+	previous := testutil.CampaignWithNoDefend()
+	current := testutil.CampaignWithNoDefend()
+	current.FactionsStatus[1].Status = domain.FactionStatusDefeated
+	current.FactionsStatus[2].Points = current.FactionsStatus[2].PointsMax / 2
+
+	changes := domain.Diff(previous, current)
+	f := findChange(changes, domain.ChangeFactionStatus)
+	if f == nil || f.Faction.From != domain.FactionStatusActive || f.Faction.To != domain.FactionStatusDefeated {
+		t.Fatalf("expected faction status change, got %v", kinds(changes))
+	}
+	s := findChange(changes, domain.ChangeSector)
+	if s == nil {
+		t.Fatalf("expected sector change, got %v", kinds(changes))
+	}
+	if s.Sector.From != previous.FactionsStatus[2].Sector() || s.Sector.To != 6 {
+		t.Errorf("unexpected sector change: %+v", s.Sector)
+	}
+}
+
+func TestDiff_Season(t *testing.T) {
+	previous := testutil.CampaignWithNoDefend()
+	current := testutil.CampaignWithNoDefend()
+	for i := range current.FactionsStatus {
+		current.FactionsStatus[i].Season = 160
+		current.FactionsStatus[i].Status = domain.FactionStatusActive
+	}
+
+	changes := domain.Diff(previous, current)
+	c := findChange(changes, domain.ChangeSeason)
+	if c == nil {
+		t.Fatalf("expected season change, got %v", kinds(changes))
+	}
+	if c.Season.From != 159 || c.Season.To != 160 || c.Season.Won {
+		t.Errorf("unexpected season change: %+v", c.Season)
+	}
+	if findChange(changes, domain.ChangeFactionStatus) != nil {
+		t.Error("expected faction changes to be suppressed across seasons")
+	}
+	msg, ok := c.EventMessage()
+	if !ok || msg.Kind != domain.EventKindWar || msg.Transition != domain.EventTransitionFailed || msg.WarEvent.Season != 159 {
+		t.Errorf("unexpected event message: %+v", msg)
+	}
+}
+
+func TestDiff_SeasonDuration(t *testing.T) {
+	previous := campaignWithStats(159, domain.Statistics{SeasonDuration: 86400})
+	current := campaignWithStats(160, domain.Statistics{SeasonDuration: 60})
+
+	c := findChange(domain.Diff(previous, current), domain.ChangeSeason)
+	if c == nil {
+		t.Fatal("expected season change")
+	}
+	msg, _ := c.EventMessage()
+	if msg.WarEvent.Duration != 24*time.Hour {
+		t.Errorf("expected war duration 24h, got %s", msg.WarEvent.Duration)
+	}
+}
+
+func TestDiff_SeasonWon(t *testing.T) {
+	previous := testutil.CampaignWithNoDefend()
+	for i := range previous.FactionsStatus {
+		previous.FactionsStatus[i].Status = domain.FactionStatusDefeated
+	}
+	current := testutil.CampaignWithNoDefend()
+	for i := range current.FactionsStatus {
+		current.FactionsStatus[i].Season = 160
+	}
+
+	c := findChange(domain.Diff(previous, current), domain.ChangeSeason)
+	if c == nil || !c.Season.Won {
+		t.Fatalf("expected won season change, got %+v", c)
+	}
+}
+
+func TestDiff_Statistics(t *testing.T) {
+	previous := campaignWithStats(159, domain.Statistics{Kills: 10, Deaths: 2}, domain.Statistics{Kills: 5})
+	current := campaignWithStats(159, domain.Statistics{Kills: 15, Deaths: 2}, domain.Statistics{Kills: 7})
+
+	c := findChange(domain.Diff(previous, current), domain.ChangeStatistics)
+	if c == nil {
+		t.Fatal("expected statistics change")
+	}
+	if c.Statistics.Delta.Kills != 7 || c.Statistics.Delta.Deaths != 0 || c.Statistics.To.Kills != 22 {
+		t.Errorf("unexpected statistics change: %+v", c.Statistics)
+	}
+	if _, ok := c.EventMessage(); ok {
+		t.Error("expected statistics change to have no event message")
+	}
+}
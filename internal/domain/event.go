@@ -1,19 +1,39 @@
 package domain
 
+import "time"
+
 type EventKind string
 
 const (
-	EventKindDefend EventKind = "defend"
-	EventKindAttack EventKind = "attack"
-	EventKindWar    EventKind = "war"
+	EventKindDefend    EventKind = "defend"
+	EventKindAttack    EventKind = "attack"
+	EventKindWar       EventKind = "war"
+	EventKindPlayers   EventKind = "players"
+	EventKindMilestone EventKind = "milestone"
 )
 
+// DefaultEventKinds are delivered to every notifier unless its config lists
+// the kinds it wants. Player alerts and milestones are opt-in.
+var DefaultEventKinds = []EventKind{EventKindDefend, EventKindAttack, EventKindWar}
+
+// Valid reports whether k is a known event kind.
+func (k EventKind) Valid() bool {
+	switch k {
+	case EventKindDefend, EventKindAttack, EventKindWar, EventKindPlayers, EventKindMilestone:
+		return true
+	}
+	return false
+}
+
 type EventTransition string
 
 const (
 	EventTransitionStarted   EventTransition = "started"
 	EventTransitionSucceeded EventTransition = "succeeded"
 	EventTransitionFailed    EventTransition = "failed"
+	EventTransitionRose      EventTransition = "rose"
+	EventTransitionFell      EventTransition = "fell"
+	EventTransitionReached   EventTransition = "reached"
 )
 
 type OngoingEvent struct {
@@ -21,14 +41,31 @@ type OngoingEvent struct {
 	Kind EventKind
 }
 
+// WarEvent describes the end of a war. Duration is the length of the war as
+// reported by the API statistics, or zero when unknown.
 type WarEvent struct {
-	Season int
+	Season   int
+	Duration time.Duration
 }
 
 type EventMessage struct {
-	Kind        EventKind
-	Transition  EventTransition
-	DefendEvent *DefendEvent
-	AttackEvent *AttackEvent
-	WarEvent    *WarEvent
+	Kind           EventKind
+	Transition     EventTransition
+	DefendEvent    *DefendEvent
+	AttackEvent    *AttackEvent
+	WarEvent       *WarEvent
+	PlayersEvent   *PlayersEvent
+	MilestoneEvent *MilestoneEvent
+}
+
+// Enemy returns the faction of a defend or attack event. ok is false for
+// events that are not tied to a faction.
+func (m EventMessage) Enemy() (e Enemy, ok bool) {
+	switch {
+	case m.DefendEvent != nil:
+		return m.DefendEvent.Enemy, true
+	case m.AttackEvent != nil:
+		return m.AttackEvent.Enemy, true
+	}
+	return 0, false
 }
@@ -0,0 +1,456 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Locale identifies a message catalog, e.g. "en" or "es".
+type Locale string
+
+const (
+	LocaleEnglish Locale = "en"
+	LocaleSpanish Locale = "es"
+)
+
+// DefaultLocale is used when no locale is configured.
+const DefaultLocale = LocaleEnglish
+
+// Valid reports whether a catalog exists for the locale.
+func (l Locale) Valid() bool {
+	_, ok := catalogs[l]
+	return ok
+}
+
+// Catalog holds the translated strings used by status output and by the
+// variables substituted into templates. Format strings take the same
+// arguments in every catalog.
+type Catalog struct {
+	Locale Locale
+	// ThousandsSeparator is inserted every three digits by FormatInt.
+	ThousandsSeparator string
+
+	Factions map[Enemy]string
+	Counters map[MilestoneCounter]string
+	// TheFaction wraps a faction name, e.g. "The %s".
+	TheFaction string
+	// Ended replaces a relative time once it has passed.
+	Ended string
+
+	// Status board (FormatStatus). StatusTitle takes the war number,
+	// FactionActive the TheFaction phrase and DefendingRegion a region name.
+	// EventSuperEarth and EventAttack take a faction name and a relative time;
+	// EventRegion takes a faction name, region number, region name and relative time.
+	StatusTitle         string
+	FactionActive       string
+	FactionDefeated     string
+	FactionHidden       string
+	Sector              string
+	Points              string
+	DefendingSuperEarth string
+	DefendingRegion     string
+	AttackingHomeworld  string
+	ActiveEvents        string
+	EventSuperEarth     string
+	EventRegion         string
+	EventAttack         string
+
+	// Statistics (FormatStatistics). StatisticsTitle takes the war number and
+	// Successful a formatted total, formatted successes and a percentage.
+	StatisticsTitle  string
+	NoStatistics     string
+	PlayersOnline    string
+	TotalPlayers     string
+	Kills            string
+	Deaths           string
+	Accidentals      string
+	ShotsFired       string
+	Accuracy         string
+	Missions         string
+	DefendEvents     string
+	AttackEvents     string
+	PlanetsLiberated string
+	Successful       string
+
+	// Structured messages (BuildMessage). Titles are plain-text event
+	// templates used as message titles; FooterWar takes the war number.
+	Titles        Templates
+	FieldRegion   string
+	FieldCapital  string
+	FieldEnds     string
+	FieldProgress string
+	FieldPlayers  string
+	FieldPrevious string
+	FieldDuration string
+	FieldTotal    string
+	FooterWar     string
+
+	// Region details (RegionMessage). RegionTitle takes a region name and
+	// the TheFaction phrase.
+	RegionTitle     string
+	FieldSector     string
+	FieldState      string
+	RegionLiberated string
+	RegionFront     string
+	RegionEnemy     string
+	RegionDefending string
+	RegionAttacking string
+
+	// Status keyboard buttons (Telegram). The faction buttons use Factions.
+	ButtonAll        string
+	ButtonStatistics string
+
+	// Command replies. AdminOnly takes a command name. Subscribed takes the
+	// SubscribedKinds or AllEvents phrase and the SubscribedFaction or
+	// AllFactions phrase; SubscribedKinds takes event kinds,
+	// SubscribedFaction a faction name and SubscribeUnknown the word that is
+	// not a faction or an event kind. UnsubscribedDM is Discord's, whose
+	// subscribers get DMs. RegionUnknown takes the region given.
+	HelpTitle         string
+	HelpAdminOnly     string
+	PermissionsFailed string
+	AdminOnly         string
+	SubscribeUnknown  string
+	SubscribeUsage    string
+	SubscribeFailed   string
+	Subscribed        string
+	SubscribedKinds   string
+	SubscribedFaction string
+	AllEvents         string
+	AllFactions       string
+	UnsubscribeFailed string
+	Unsubscribed      string
+	UnsubscribedDM    string
+	TestMessage       string
+	StatusFailed      string
+	StatisticsFailed  string
+	RegionUnknown     string
+	RegionFailed      string
+
+	// Admin command replies (Discord). Muted takes a Discord timestamp and
+	// MuteInvalidDuration the duration given.
+	AdminRequired       string
+	MuteInvalidDuration string
+	MuteFailed          string
+	Muted               string
+	UnmuteFailed        string
+	Unmuted             string
+	PollUnavailable     string
+	PollRequested       string
+	BoardUnavailable    string
+	RepostFailed        string
+	Reposted            string
+	UnknownAdminCommand string
+}
+
+var english = &Catalog{
+	Locale:             LocaleEnglish,
+	ThousandsSeparator: ",",
+	Factions: map[Enemy]string{
+		EnemyBug:        "Bugs",
+		EnemyCyborg:     "Cyborgs",
+		EnemyIlluminate: "Illuminate",
+	},
+	Counters: map[MilestoneCounter]string{
+		MilestoneKills:            "kills",
+		MilestoneDeaths:           "deaths",
+		MilestoneAccidentals:      "accidental kills",
+		MilestoneMissions:         "missions",
+		MilestonePlanetsLiberated: "planets liberated",
+	},
+	TheFaction: "The %s",
+	Ended:      "ended",
+
+	StatusTitle:         "War %d — Status",
+	FactionActive:       "%s (active)",
+	FactionDefeated:     "defeated",
+	FactionHidden:       "hidden",
+	Sector:              "Sector",
+	Points:              "pts",
+	DefendingSuperEarth: "⚔️ defending Super Earth",
+	DefendingRegion:     "⚔️ defending %s",
+	AttackingHomeworld:  "🚀 attacking homeworld",
+	ActiveEvents:        "Active events:",
+	EventSuperEarth:     "⚔️  The %s are attacking Super Earth — ends %s",
+	EventRegion:         "⚔️  The %s are attacking sector %d: %s — ends %s",
+	EventAttack:         "🚀 Attacking %s homeworld — ends %s",
+
+	StatisticsTitle:  "War %d — Statistics",
+	NoStatistics:     "No statistics available.",
+	PlayersOnline:    "Players online:",
+	TotalPlayers:     "Total players:",
+	Kills:            "Kills:",
+	Deaths:           "Deaths:",
+	Accidentals:      "Accidentals:",
+	ShotsFired:       "Shots fired:",
+	Accuracy:         "Accuracy:",
+	Missions:         "Missions:",
+	DefendEvents:     "Defend events:",
+	AttackEvents:     "Attack events:",
+	PlanetsLiberated: "Planets liberated:",
+	Successful:       "%s (%s successful, %d%%)",
+
+	Titles: Templates{
+		DefendRegionStarted:       "⚔️ The {FACTION} are attacking {REGION_NAME}!",
+		DefendSuperEarthStarted:   "🚨 The {FACTION} are attacking Super Earth!",
+		DefendRegionSucceeded:     "✅ {REGION_NAME} has been defended against the {FACTION}!",
+		DefendSuperEarthSucceeded: "✅ Super Earth has been defended against the {FACTION}!",
+		DefendRegionFailed:        "❌ {REGION_NAME} has fallen to the {FACTION}.",
+		DefendSuperEarthFailed:    "❌ Super Earth has fallen to the {FACTION}.",
+		AttackHomeworldStarted:    "🚀 An attack against the {FACTION} homeworld has started!",
+		AttackSucceeded:           "✅ Attack succeeded! The {FACTION} were defeated.",
+		AttackFailed:              "❌ Attack failed! The {FACTION} defended their homeworld.",
+		WarWon:                    "🏆 Managed Democracy prevails! War {SEASON} is won.",
+		WarLost:                   "💀 War {SEASON} is lost. Super Earth has fallen.",
+		PlayersAboveThreshold:     "📈 Over {THRESHOLD} helldivers are online!",
+		PlayersBelowThreshold:     "📉 Under {THRESHOLD} helldivers remain online.",
+		PlayersSurge:              "📈 Helldivers are rallying! Up {CHANGE_PERCENT}% in {WINDOW}.",
+		PlayersDrop:               "📉 Helldiver numbers are dropping. Down {CHANGE_PERCENT}% in {WINDOW}.",
+		MilestoneReached:          "🎖️ Milestone reached: {MILESTONE} {COUNTER}!",
+	},
+	FieldRegion:   "Region",
+	FieldCapital:  "Capital",
+	FieldEnds:     "Ends",
+	FieldProgress: "Progress",
+	FieldPlayers:  "Players online",
+	FieldPrevious: "Previously",
+	FieldDuration: "Duration",
+	FieldTotal:    "Total",
+	FooterWar:     "War %d",
+
+	RegionTitle:     "%s — %s",
+	FieldSector:     "Sector",
+	FieldState:      "State",
+	RegionLiberated: "✅ Liberated",
+	RegionFront:     "🎯 Front line",
+	RegionEnemy:     "🛑 Enemy territory",
+	RegionDefending: "⚔️ Under attack",
+	RegionAttacking: "🚀 Helldivers are attacking",
+
+	ButtonAll:        "All",
+	ButtonStatistics: "📊 Statistics",
+
+	HelpTitle:         "Commands",
+	HelpAdminOnly:     "admins only",
+	PermissionsFailed: "⚠️ Could not check your permissions.",
+	AdminOnly:         "⛔ Only chat administrators can use /%s.",
+	SubscribeUnknown:  "⚠️ Unknown faction or event %q.",
+	SubscribeUsage:    "Usage:",
+	SubscribeFailed:   "⚠️ Could not save subscription.",
+	Subscribed:        "🔔 Subscribed to %s for %s. Use /unsubscribe to stop.",
+	SubscribedKinds:   "%s events",
+	SubscribedFaction: "the %s",
+	AllEvents:         "all events",
+	AllFactions:       "all factions",
+	UnsubscribeFailed: "⚠️ Could not remove subscription.",
+	Unsubscribed:      "🔕 Unsubscribed. This chat will no longer receive events.",
+	UnsubscribedDM:    "🔕 Unsubscribed. You will no longer receive DMs.",
+	TestMessage:       "✅ hellbot is connected and can send messages to this chat.",
+	StatusFailed:      "⚠️ Could not retrieve war status.",
+	StatisticsFailed:  "⚠️ Could not retrieve statistics.",
+	RegionUnknown:     "⚠️ Unknown region %q.",
+	RegionFailed:      "⚠️ Could not retrieve the region.",
+
+	AdminRequired:       "⛔ You need the Manage Server permission or an admin role to use this command.",
+	MuteInvalidDuration: "⚠️ Invalid duration %q. Use e.g. 30m or 2h.",
+	MuteFailed:          "⚠️ Could not mute notifications.",
+	Muted:               "🔇 Notifications muted until %s.",
+	UnmuteFailed:        "⚠️ Could not resume notifications.",
+	Unmuted:             "🔊 Notifications resumed.",
+	PollUnavailable:     "⚠️ Polling cannot be triggered from commands.",
+	PollRequested:       "🔄 Poll requested.",
+	BoardUnavailable:    "⚠️ No war board is configured.",
+	RepostFailed:        "⚠️ Could not re-post the war board.",
+	Reposted:            "📌 War board re-posted.",
+	UnknownAdminCommand: "⚠️ Unknown admin command.",
+}
+
+var spanish = &Catalog{
+	Locale:             LocaleSpanish,
+	ThousandsSeparator: ".",
+	Factions: map[Enemy]string{
+		EnemyBug:        "Insectos",
+		EnemyCyborg:     "Cyborgs",
+		EnemyIlluminate: "Iluminados",
+	},
+	Counters: map[MilestoneCounter]string{
+		MilestoneKills:            "bajas",
+		MilestoneDeaths:           "muertes",
+		MilestoneAccidentals:      "bajas accidentales",
+		MilestoneMissions:         "misiones",
+		MilestonePlanetsLiberated: "planetas liberados",
+	},
+	TheFaction: "Los %s",
+	Ended:      "finalizado",
+
+	StatusTitle:         "Guerra %d — Estado",
+	FactionActive:       "%s (activos)",
+	FactionDefeated:     "derrotados",
+	FactionHidden:       "ocultos",
+	Sector:              "Sector",
+	Points:              "pts",
+	DefendingSuperEarth: "⚔️ defendiendo la Súper Tierra",
+	DefendingRegion:     "⚔️ defendiendo %s",
+	AttackingHomeworld:  "🚀 atacando su planeta natal",
+	ActiveEvents:        "Eventos activos:",
+	EventSuperEarth:     "⚔️  Los %s atacan la Súper Tierra — fin: %s",
+	EventRegion:         "⚔️  Los %s atacan el sector %d: %s — fin: %s",
+	EventAttack:         "🚀 Atacando el planeta natal de los %s — fin: %s",
+
+	StatisticsTitle:  "Guerra %d — Estadísticas",
+	NoStatistics:     "No hay estadísticas disponibles.",
+	PlayersOnline:    "Jugadores en línea:",
+	TotalPlayers:     "Jugadores totales:",
+	Kills:            "Bajas:",
+	Deaths:           "Muertes:",
+	Accidentals:      "Accidentales:",
+	ShotsFired:       "Disparos:",
+	Accuracy:         "Precisión:",
+	Missions:         "Misiones:",
+	DefendEvents:     "Defensas:",
+	AttackEvents:     "Ataques:",
+	PlanetsLiberated: "Planetas liberados:",
+	Successful:       "%s (%s con éxito, %d%%)",
+
+	Titles: Templates{
+		DefendRegionStarted:       "⚔️ ¡Los {FACTION} atacan {REGION_NAME}!",
+		DefendSuperEarthStarted:   "🚨 ¡Los {FACTION} atacan la Súper Tierra!",
+		DefendRegionSucceeded:     "✅ ¡{REGION_NAME} resistió el ataque de los {FACTION}!",
+		DefendSuperEarthSucceeded: "✅ ¡La Súper Tierra resistió el ataque de los {FACTION}!",
+		DefendRegionFailed:        "❌ {REGION_NAME} cayó ante los {FACTION}.",
+		DefendSuperEarthFailed:    "❌ La Súper Tierra cayó ante los {FACTION}.",
+		AttackHomeworldStarted:    "🚀 ¡Comenzó el ataque al planeta natal de los {FACTION}!",
+		AttackSucceeded:           "✅ ¡Ataque exitoso! Los {FACTION} fueron derrotados.",
+		AttackFailed:              "❌ ¡Ataque fallido! Los {FACTION} defendieron su planeta natal.",
+		WarWon:                    "🏆 ¡La Democracia Gestionada prevalece! Ganamos la Guerra {SEASON}.",
+		WarLost:                   "💀 Perdimos la Guerra {SEASON}. La Súper Tierra ha caído.",
+		PlayersAboveThreshold:     "📈 ¡Más de {THRESHOLD} helldivers en línea!",
+		PlayersBelowThreshold:     "📉 Quedan menos de {THRESHOLD} helldivers en línea.",
+		PlayersSurge:              "📈 ¡Los helldivers se reagrupan! {CHANGE_PERCENT}% más en {WINDOW}.",
+		PlayersDrop:               "📉 Cada vez hay menos helldivers. {CHANGE_PERCENT}% menos en {WINDOW}.",
+		MilestoneReached:          "🎖️ Hito alcanzado: ¡{MILESTONE} {COUNTER}!",
+	},
+	FieldRegion:   "Región",
+	FieldCapital:  "Capital",
+	FieldEnds:     "Fin",
+	FieldProgress: "Progreso",
+	FieldPlayers:  "Jugadores en línea",
+	FieldPrevious: "Antes",
+	FieldDuration: "Duración",
+	FieldTotal:    "Total",
+	FooterWar:     "Guerra %d",
+
+	RegionTitle:     "%s — %s",
+	FieldSector:     "Sector",
+	FieldState:      "Estado",
+	RegionLiberated: "✅ Liberada",
+	RegionFront:     "🎯 Frente de batalla",
+	RegionEnemy:     "🛑 Territorio enemigo",
+	RegionDefending: "⚔️ Bajo ataque",
+	RegionAttacking: "🚀 Los helldivers atacan",
+
+	ButtonAll:        "Todas",
+	ButtonStatistics: "📊 Estadísticas",
+
+	HelpTitle:         "Comandos",
+	HelpAdminOnly:     "solo administradores",
+	PermissionsFailed: "⚠️ No se pudieron comprobar tus permisos.",
+	AdminOnly:         "⛔ Solo los administradores del chat pueden usar /%s.",
+	SubscribeUnknown:  "⚠️ Facción o evento desconocido: %q.",
+	SubscribeUsage:    "Uso:",
+	SubscribeFailed:   "⚠️ No se pudo guardar la suscripción.",
+	Subscribed:        "🔔 Suscrito a %s de %s. Usa /unsubscribe para dejar de recibirlos.",
+	SubscribedKinds:   "eventos de tipo %s",
+	SubscribedFaction: "los %s",
+	AllEvents:         "todos los eventos",
+	AllFactions:       "todas las facciones",
+	UnsubscribeFailed: "⚠️ No se pudo eliminar la suscripción.",
+	Unsubscribed:      "🔕 Suscripción cancelada. Este chat ya no recibirá eventos.",
+	UnsubscribedDM:    "🔕 Suscripción cancelada. Ya no recibirás MD.",
+	TestMessage:       "✅ hellbot está conectado y puede enviar mensajes a este chat.",
+	StatusFailed:      "⚠️ No se pudo obtener el estado de la guerra.",
+	StatisticsFailed:  "⚠️ No se pudieron obtener las estadísticas.",
+	RegionUnknown:     "⚠️ Región desconocida: %q.",
+	RegionFailed:      "⚠️ No se pudo obtener la región.",
+
+	AdminRequired:       "⛔ Necesitas el permiso Gestionar servidor o un rol de administrador para usar este comando.",
+	MuteInvalidDuration: "⚠️ Duración no válida: %q. Usa, p. ej., 30m o 2h.",
+	MuteFailed:          "⚠️ No se pudieron silenciar las notificaciones.",
+	Muted:               "🔇 Notificaciones silenciadas hasta %s.",
+	UnmuteFailed:        "⚠️ No se pudieron reanudar las notificaciones.",
+	Unmuted:             "🔊 Notificaciones reanudadas.",
+	PollUnavailable:     "⚠️ No se puede consultar la guerra desde los comandos.",
+	PollRequested:       "🔄 Consulta solicitada.",
+	BoardUnavailable:    "⚠️ No hay un tablero de guerra configurado.",
+	RepostFailed:        "⚠️ No se pudo volver a publicar el tablero de guerra.",
+	Reposted:            "📌 Tablero de guerra publicado de nuevo.",
+	UnknownAdminCommand: "⚠️ Comando de administración desconocido.",
+}
+
+var catalogs = map[Locale]*Catalog{
+	LocaleEnglish: english,
+	LocaleSpanish: spanish,
+}
+
+// CatalogFor returns the catalog for the locale, falling back to English.
+func CatalogFor(l Locale) *Catalog {
+	if c, ok := catalogs[l]; ok {
+		return c
+	}
+	return english
+}
+
+// FactionName returns the translated faction name.
+func (c *Catalog) FactionName(e Enemy) string {
+	if name, ok := c.Factions[e]; ok {
+		return name
+	}
+	return e.String()
+}
+
+// SubscribedMessage confirms sub, naming its event kinds and factions.
+func (c *Catalog) SubscribedMessage(sub Subscription) string {
+	kinds := c.AllEvents
+	if len(sub.Kinds) > 0 {
+		names := make([]string, len(sub.Kinds))
+		for i, k := range sub.Kinds {
+			names[i] = string(k)
+		}
+		kinds = fmt.Sprintf(c.SubscribedKinds, strings.Join(names, ", "))
+	}
+	factions := c.AllFactions
+	if len(sub.Factions) > 0 {
+		names := make([]string, len(sub.Factions))
+		for i, e := range sub.Factions {
+			names[i] = fmt.Sprintf(c.SubscribedFaction, c.FactionName(e))
+		}
+		factions = strings.Join(names, ", ")
+	}
+	return fmt.Sprintf(c.Subscribed, kinds, factions)
+}
+
+// CounterLabel returns the translated name of a milestone counter.
+func (c *Catalog) CounterLabel(m MilestoneCounter) string {
+	if label, ok := c.Counters[m]; ok {
+		return label
+	}
+	return string(m)
+}
+
+// FormatInt formats n with the catalog's thousands separator.
+func (c *Catalog) FormatInt(n int) string {
+	sign := ""
+	if n < 0 {
+		sign = "-"
+		n = -n
+	}
+	s := fmt.Sprintf("%d", n)
+	out := make([]byte, 0, len(s)+len(s)/3*len(c.ThousandsSeparator))
+	for i := range len(s) {
+		if i > 0 && (len(s)-i)%3 == 0 {
+			out = append(out, c.ThousandsSeparator...)
+		}
+		out = append(out, s[i])
+	}
+	return sign + string(out)
+}
@@ -0,0 +1,133 @@
+package domain_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ametis70/hellbot/internal/domain"
+)
+
+func TestLocale_Valid(t *testing.T) {
+	for _, l := range []domain.Locale{domain.LocaleEnglish, domain.LocaleSpanish} {
+		if !l.Valid() {
+			t.Errorf("expected %q to be valid", l)
+		}
+	}
+	if domain.Locale("xx").Valid() {
+		t.Error("expected unknown locale to be invalid")
+	}
+}
+
+func TestCatalogFor_FallsBackToEnglish(t *testing.T) {
+	if got := domain.CatalogFor("xx").Locale; got != domain.LocaleEnglish {
+		t.Errorf("expected english fallback, got %q", got)
+	}
+	if got := domain.CatalogFor(domain.LocaleSpanish).Locale; got != domain.LocaleSpanish {
+		t.Errorf("expected spanish catalog, got %q", got)
+	}
+}
+
+func TestCatalog_FormatInt(t *testing.T) {
+	cases := []struct {
+		locale domain.Locale
+		n      int
+		want   string
+	}{
+		{domain.LocaleEnglish, 999, "999"},
+		{domain.LocaleEnglish, 1234567, "1,234,567"},
+		{domain.LocaleSpanish, 1234567, "1.234.567"},
+		{domain.LocaleSpanish, -1000, "-1.000"},
+	}
+	for _, c := range cases {
+		if got := domain.CatalogFor(c.locale).FormatInt(c.n); got != c.want {
+			t.Errorf("%s FormatInt(%d) = %q, want %q", c.locale, c.n, got, c.want)
+		}
+	}
+}
+
+func TestCatalog_FactionName(t *testing.T) {
+	es := domain.CatalogFor(domain.LocaleSpanish)
+	if got := es.FactionName(domain.EnemyBug); got != "Insectos" {
+		t.Errorf("expected Insectos, got %q", got)
+	}
+	if got := es.CounterLabel(domain.MilestoneKills); got != "bajas" {
+		t.Errorf("expected bajas, got %q", got)
+	}
+}
+
+func TestCatalog_SubscribedMessage(t *testing.T) {
+	es := domain.CatalogFor(domain.LocaleSpanish)
+	sub := domain.Subscription{Factions: []domain.Enemy{domain.EnemyBug}, Kinds: []domain.EventKind{domain.EventKindAttack}}
+	if got := es.SubscribedMessage(sub); !strings.Contains(got, "eventos de tipo attack de los Insectos") {
+		t.Errorf("unexpected confirmation %q", got)
+	}
+	if got := domain.CatalogFor(domain.LocaleEnglish).SubscribedMessage(domain.Subscription{}); !strings.Contains(got, "all events for all factions") {
+		t.Errorf("unexpected confirmation %q", got)
+	}
+}
+
+func TestCatalog_FormatStatus_Spanish(t *testing.T) {
+	c := &domain.CampaignStatus{
+		FactionsStatus: []domain.FactionStatus{
+			{Season: 42, Enemy: domain.EnemyBug, Status: domain.FactionStatusActive, Points: 100, PointsMax: 1000},
+			{Season: 42, Enemy: domain.EnemyCyborg, Status: domain.FactionStatusDefeated},
+		},
+		DefendEvent: &domain.DefendEvent{
+			Region:  0,
+			Enemy:   domain.EnemyBug,
+			EndTime: time.Now().Add(time.Hour),
+			Status:  domain.EventStatusActive,
+		},
+	}
+	out := domain.CatalogFor(domain.LocaleSpanish).FormatStatus(c, nil)
+	for _, want := range []string{"Guerra 42 — Estado", "Los Insectos (activos)", "derrotados", "Súper Tierra"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestCatalog_FormatStatistics_Spanish(t *testing.T) {
+	c := &domain.CampaignStatus{
+		FactionsStatus: []domain.FactionStatus{{Season: 7, Enemy: domain.EnemyBug}},
+		Statistics: []domain.Statistics{
+			{Season: 7, Enemy: domain.EnemyBug, Kills: 1234567, Players: 1500},
+		},
+	}
+	out := domain.CatalogFor(domain.LocaleSpanish).FormatStatistics(c)
+	for _, want := range []string{"Guerra 7 — Estadísticas", "Bajas:", "1.234.567"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestCatalog_RenderEvent_Spanish(t *testing.T) {
+	es := domain.CatalogFor(domain.LocaleSpanish)
+	msg := domain.EventMessage{
+		Kind:           domain.EventKindMilestone,
+		Transition:     domain.EventTransitionReached,
+		MilestoneEvent: &domain.MilestoneEvent{Season: 3, Counter: domain.MilestoneKills, Milestone: 1000000, Value: 1000412},
+	}
+	got, err := es.RenderEvent(domain.Templates{MilestoneReached: "{MILESTONE} {COUNTER} ({VALUE})"}, msg, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "1.000.000 bajas (1.000.412)"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	defend := domain.EventMessage{
+		Kind:        domain.EventKindDefend,
+		Transition:  domain.EventTransitionStarted,
+		DefendEvent: &domain.DefendEvent{Enemy: domain.EnemyIlluminate, Region: 0},
+	}
+	got, err = es.RenderEvent(domain.Templates{DefendSuperEarthStarted: "{FACTION}"}, defend, func(time.Time) string { return "" })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "Iluminados" {
+		t.Errorf("expected localized faction, got %q", got)
+	}
+}
@@ -0,0 +1,196 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// Severity ranks how urgent a message is. Renderers map it to colours.
+type Severity string
+
+const (
+	SeverityInfo     Severity = "info"
+	SeveritySuccess  Severity = "success"
+	SeverityWarning  Severity = "warning"
+	SeverityCritical Severity = "critical"
+)
+
+// MessageStyle selects how a notifier renders event notifications.
+type MessageStyle string
+
+const (
+	// MessageStyleTemplate renders the notifier's templates as-is. Default.
+	MessageStyleTemplate MessageStyle = "template"
+	// MessageStyleStructured builds a Message and renders it natively for the platform.
+	MessageStyleStructured MessageStyle = "structured"
+)
+
+// Valid reports whether s is a known style. The empty string is the default.
+func (s MessageStyle) Valid() bool {
+	switch s {
+	case "", MessageStyleTemplate, MessageStyleStructured:
+		return true
+	}
+	return false
+}
+
+// Style is the emphasis applied to a Span.
+type Style int
+
+const (
+	StylePlain Style = iota
+	StyleBold
+	StyleItalic
+	StyleCode
+)
+
+// Span is a run of text with a single style. A span with a non-zero Time is a
+// timestamp: platforms with native timestamps render Time, others render Text
+// for relative spans and format Time for absolute ones.
+type Span struct {
+	Text     string
+	Style    Style
+	Time     time.Time
+	Relative bool
+}
+
+// Text returns a plain span.
+func Text(s string) Span { return Span{Text: s} }
+
+// Bold returns a bold span.
+func Bold(s string) Span { return Span{Text: s, Style: StyleBold} }
+
+// Italic returns an italic span.
+func Italic(s string) Span { return Span{Text: s, Style: StyleItalic} }
+
+// Code returns a monospace span.
+func Code(s string) Span { return Span{Text: s, Style: StyleCode} }
+
+// Timestamp returns a span showing t as an absolute time.
+func Timestamp(t time.Time) Span { return Span{Time: t} }
+
+// IsTime reports whether the span is a timestamp.
+func (s Span) IsTime() bool { return !s.Time.IsZero() }
+
+// Line is one line of spans.
+type Line []Span
+
+// Field is a named value shown below the body. Inline fields may be laid out
+// side by side where the platform supports it.
+type Field struct {
+	Name   string
+	Value  []Line
+	Inline bool
+}
+
+// Message is a platform-neutral notification. Notifiers render it natively:
+// plain text, Telegram MarkdownV2 or a Discord embed.
+type Message struct {
+	Severity  Severity
+	Title     string
+	Body      []Line
+	Fields    []Field
+	Footer    string
+	Timestamp time.Time
+}
+
+// Markup renders spans for a text platform. Each function receives raw text
+// and is responsible for escaping it.
+type Markup struct {
+	Text   func(string) string
+	Bold   func(string) string
+	Italic func(string) string
+	Code   func(string) string
+	Time   func(Span) string
+}
+
+// PlainMarkup renders spans without any markup. Absolute timestamps use formatTime.
+func PlainMarkup(formatTime func(time.Time) string) Markup {
+	id := func(s string) string { return s }
+	return Markup{
+		Text:   id,
+		Bold:   id,
+		Italic: id,
+		Code:   id,
+		Time: func(s Span) string {
+			if s.Relative {
+				return s.Text
+			}
+			return formatTime(s.Time)
+		},
+	}
+}
+
+// Format renders the line with mk.
+func (l Line) Format(mk Markup) string {
+	var sb strings.Builder
+	for _, s := range l {
+		switch {
+		case s.IsTime():
+			sb.WriteString(mk.Time(s))
+		case s.Style == StyleBold:
+			sb.WriteString(mk.Bold(s.Text))
+		case s.Style == StyleItalic:
+			sb.WriteString(mk.Italic(s.Text))
+		case s.Style == StyleCode:
+			sb.WriteString(mk.Code(s.Text))
+		default:
+			sb.WriteString(mk.Text(s.Text))
+		}
+	}
+	return sb.String()
+}
+
+// FormatLines renders each line with mk and joins them with newlines.
+func FormatLines(lines []Line, mk Markup) string {
+	out := make([]string, len(lines))
+	for i, l := range lines {
+		out[i] = l.Format(mk)
+	}
+	return strings.Join(out, "\n")
+}
+
+// Format lays the message out as text: a bold title, the body, one block per
+// field and an italic footer, separated by blank lines. Single-line fields
+// render as "Name: value"; longer ones put the value on indented lines.
+func (m Message) Format(mk Markup) string {
+	var blocks []string
+	if m.Title != "" {
+		blocks = append(blocks, mk.Bold(m.Title))
+	}
+	if len(m.Body) > 0 {
+		blocks = append(blocks, FormatLines(m.Body, mk))
+	}
+	if len(m.Fields) > 0 {
+		var fields []string
+		for _, f := range m.Fields {
+			if len(f.Value) == 1 {
+				fields = append(fields, mk.Bold(f.Name+":")+mk.Text(" ")+f.Value[0].Format(mk))
+				continue
+			}
+			lines := []string{mk.Bold(f.Name + ":")}
+			for _, l := range f.Value {
+				lines = append(lines, mk.Text("  ")+l.Format(mk))
+			}
+			fields = append(fields, strings.Join(lines, "\n"))
+		}
+		sep := "\n"
+		if !allSingleLine(m.Fields) {
+			sep = "\n\n"
+		}
+		blocks = append(blocks, strings.Join(fields, sep))
+	}
+	if m.Footer != "" {
+		blocks = append(blocks, mk.Italic(m.Footer))
+	}
+	return strings.Join(blocks, "\n\n")
+}
+
+func allSingleLine(fields []Field) bool {
+	for _, f := range fields {
+		if len(f.Value) != 1 {
+			return false
+		}
+	}
+	return true
+}
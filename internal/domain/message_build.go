@@ -0,0 +1,214 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// StatusMessage returns the war status as a structured message with one
+// field per faction and a field listing active events.
+// If filter is non-nil, only the matching faction is shown.
+func (cat *Catalog) StatusMessage(c *CampaignStatus, filter *Enemy) Message {
+	m := Message{
+		Severity:  SeverityInfo,
+		Title:     fmt.Sprintf(cat.StatusTitle, c.Season()),
+		Timestamp: c.Time,
+	}
+	for _, f := range c.FactionsStatus {
+		if filter != nil && f.Enemy != *filter {
+			continue
+		}
+		name := fmt.Sprintf(cat.TheFaction, cat.FactionName(f.Enemy))
+		switch f.Status {
+		case FactionStatusDefeated:
+			m.Fields = append(m.Fields, Field{Name: name, Value: []Line{{Text(cat.FactionDefeated)}}, Inline: true})
+		case FactionStatusHidden:
+			m.Fields = append(m.Fields, Field{Name: name, Value: []Line{{Text(cat.FactionHidden)}}, Inline: true})
+		default:
+			m.Fields = append(m.Fields, Field{
+				Name:   fmt.Sprintf(cat.FactionActive, name),
+				Value:  cat.factionProgress(f, c),
+				Inline: true,
+			})
+		}
+	}
+	if events := cat.activeEvents(c, filter); len(events) > 0 {
+		m.Fields = append(m.Fields, Field{Name: strings.TrimSuffix(cat.ActiveEvents, ":"), Value: events})
+	}
+	return m
+}
+
+// StatisticsMessage returns the summed statistics as a structured message
+// with one inline field per counter.
+func (cat *Catalog) StatisticsMessage(c *CampaignStatus) Message {
+	m := Message{
+		Severity:  SeverityInfo,
+		Title:     fmt.Sprintf(cat.StatisticsTitle, c.Season()),
+		Timestamp: c.Time,
+	}
+	if len(c.Statistics) == 0 {
+		m.Body = []Line{{Text(cat.NoStatistics)}}
+		return m
+	}
+	for _, r := range cat.statisticsRows(c) {
+		m.Fields = append(m.Fields, Field{
+			Name:   strings.TrimSuffix(r[0], ":"),
+			Value:  []Line{{Text(r[1])}},
+			Inline: true,
+		})
+	}
+	return m
+}
+
+// RegionMessage returns the state of one of a faction's regions: liberated,
+// the front line with its progress, enemy territory, or under attack with the
+// event's end time.
+func (cat *Catalog) RegionMessage(c *CampaignStatus, enemy Enemy, number int) Message {
+	region := GetRegion(enemy, number)
+	m := Message{
+		Severity:  SeverityInfo,
+		Title:     fmt.Sprintf(cat.RegionTitle, region.Name, fmt.Sprintf(cat.TheFaction, cat.FactionName(enemy))),
+		Timestamp: c.Time,
+	}
+	if region.Capital != "" {
+		m.Fields = append(m.Fields, Field{Name: cat.FieldCapital, Value: []Line{{Text(region.Capital)}}, Inline: true})
+	}
+	m.Fields = append(m.Fields, Field{Name: cat.FieldSector, Value: []Line{{Text(fmt.Sprintf("%d/%d", number, HomeWorldRegion))}}, Inline: true})
+
+	var f FactionStatus
+	for _, fs := range c.FactionsStatus {
+		if fs.Enemy == enemy {
+			f = fs
+		}
+	}
+	if f.Season > 0 {
+		m.Footer = fmt.Sprintf(cat.FooterWar, f.Season)
+	}
+
+	state, end := cat.RegionEnemy, time.Time{}
+	switch {
+	case c.DefendEvent != nil && c.DefendEvent.Status == EventStatusActive && c.DefendEvent.Enemy == enemy && c.DefendEvent.Region == number:
+		state, end = cat.RegionDefending, c.DefendEvent.EndTime
+	case f.Status == FactionStatusDefeated || number < f.Sector():
+		state = cat.RegionLiberated
+	case number == f.Sector():
+		state = cat.RegionFront
+		for _, e := range c.AttackEvents {
+			if e.Status == EventStatusActive && e.Enemy == enemy && IsHomeworld(number) {
+				state, end = cat.RegionAttacking, e.EndTime
+			}
+		}
+	}
+	m.Fields = append(m.Fields, Field{Name: cat.FieldState, Value: []Line{{Text(state)}}, Inline: true})
+	if !end.IsZero() {
+		m.Fields = append(m.Fields, Field{Name: cat.FieldEnds, Value: []Line{{Timestamp(end), Text(" ("), cat.relativeSpan(end), Text(")")}}, Inline: true})
+	}
+	if state == cat.RegionFront {
+		_, points, pointsMax := f.sectorProgress()
+		p := pct(points, pointsMax)
+		m.Fields = append(m.Fields, Field{Name: cat.FieldProgress, Value: []Line{
+			{Code(fmt.Sprintf("%s %3d%%", progressBar(p, 10), p))},
+			{Text(fmt.Sprintf("%s / %s %s", cat.FormatInt(points), cat.FormatInt(pointsMax), cat.Points))},
+		}})
+	}
+	return m
+}
+
+// BuildMessage returns an event notification as a structured message. The
+// title is the event's template rendered as plain text, so templates is
+// usually the catalog's Titles merged with user overrides. Fields carry the
+// event details: region, end time, progress, player counts and so on.
+func (cat *Catalog) BuildMessage(templates Templates, msg EventMessage, formatTime func(time.Time) string) (Message, error) {
+	title, err := cat.RenderEvent(templates, msg, formatTime)
+	if err != nil {
+		return Message{}, err
+	}
+
+	m := Message{Title: title, Severity: severityOf(msg)}
+	switch {
+	case msg.DefendEvent != nil:
+		e := msg.DefendEvent
+		if !IsSuperEarth(e.Region) {
+			region := GetRegion(e.Enemy, e.Region)
+			m.Fields = append(m.Fields, cat.regionFields(region, fmt.Sprintf("%s (%d/%d)", region.Name, e.Region, TotalRegions))...)
+		}
+		m.Fields = append(m.Fields, cat.eventFields(msg.Transition, e.EndTime, e.Points, e.PointsMax)...)
+		m.Footer = fmt.Sprintf(cat.FooterWar, e.Season)
+		if msg.Transition == EventTransitionStarted {
+			m.Timestamp = e.StartTime
+		}
+
+	case msg.AttackEvent != nil:
+		e := msg.AttackEvent
+		region := GetRegion(e.Enemy, HomeWorldRegion)
+		m.Fields = append(m.Fields, cat.regionFields(region, region.Name)...)
+		m.Fields = append(m.Fields, cat.eventFields(msg.Transition, e.EndTime, e.Points, e.PointsMax)...)
+		m.Footer = fmt.Sprintf(cat.FooterWar, e.Season)
+		if msg.Transition == EventTransitionStarted {
+			m.Timestamp = e.StartTime
+		}
+
+	case msg.WarEvent != nil:
+		if msg.WarEvent.Duration > 0 {
+			m.Fields = append(m.Fields, Field{Name: cat.FieldDuration, Value: []Line{{Text(formatDuration(msg.WarEvent.Duration))}}, Inline: true})
+		}
+
+	case msg.PlayersEvent != nil:
+		e := msg.PlayersEvent
+		m.Fields = append(m.Fields,
+			Field{Name: cat.FieldPlayers, Value: []Line{{Bold(cat.FormatInt(e.Players))}}, Inline: true},
+			Field{Name: cat.FieldPrevious, Value: []Line{{Text(cat.FormatInt(e.Previous))}}, Inline: true},
+		)
+		m.Footer = fmt.Sprintf(cat.FooterWar, e.Season)
+
+	case msg.MilestoneEvent != nil:
+		e := msg.MilestoneEvent
+		m.Fields = append(m.Fields, Field{Name: cat.FieldTotal, Value: []Line{{Bold(cat.FormatInt(e.Value))}}, Inline: true})
+		m.Footer = fmt.Sprintf(cat.FooterWar, e.Season)
+	}
+	return m, nil
+}
+
+// regionFields returns the region and capital fields. name is the region as
+// displayed, e.g. with its number.
+func (cat *Catalog) regionFields(region Region, name string) []Field {
+	fields := []Field{{Name: cat.FieldRegion, Value: []Line{{Text(name)}}, Inline: true}}
+	if region.Capital != "" {
+		fields = append(fields, Field{Name: cat.FieldCapital, Value: []Line{{Text(region.Capital)}}, Inline: true})
+	}
+	return fields
+}
+
+// eventFields returns the end time of a started event and the event's
+// progress when it has points.
+func (cat *Catalog) eventFields(t EventTransition, end time.Time, points, pointsMax int) []Field {
+	var fields []Field
+	if t == EventTransitionStarted {
+		fields = append(fields, Field{Name: cat.FieldEnds, Value: []Line{{Timestamp(end), Text(" ("), cat.relativeSpan(end), Text(")")}}, Inline: true})
+	}
+	if pointsMax > 0 {
+		p := min(pct(points, pointsMax), 100)
+		fields = append(fields, Field{Name: cat.FieldProgress, Value: []Line{{Code(fmt.Sprintf("%s %3d%%", progressBar(p, 10), p))}}, Inline: true})
+	}
+	return fields
+}
+
+// severityOf maps an event to a severity: Super Earth defenses and losses are
+// critical, other defenses are warnings and victories are successes.
+func severityOf(msg EventMessage) Severity {
+	switch msg.Transition {
+	case EventTransitionSucceeded, EventTransitionReached:
+		return SeveritySuccess
+	case EventTransitionFailed:
+		return SeverityCritical
+	case EventTransitionStarted:
+		if msg.DefendEvent != nil {
+			if IsSuperEarth(msg.DefendEvent.Region) {
+				return SeverityCritical
+			}
+			return SeverityWarning
+		}
+	}
+	return SeverityInfo
+}
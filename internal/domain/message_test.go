@@ -0,0 +1,298 @@
+package domain_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ametis70/hellbot/internal/domain"
+	"github.com/ametis70/hellbot/internal/testutil"
+)
+
+func utcFormatter(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+func findField(m domain.Message, name string) (domain.Field, bool) {
+	for _, f := range m.Fields {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return domain.Field{}, false
+}
+
+func TestMessage_FormatPlain(t *testing.T) {
+	m := domain.Message{
+		Title: "Title",
+		Body:  []domain.Line{{domain.Text("Hello "), domain.Bold("world")}},
+		Fields: []domain.Field{
+			{Name: "Ends", Value: []domain.Line{{domain.Timestamp(time.Unix(0, 0))}}},
+			{Name: "Score", Value: []domain.Line{{domain.Code("42")}}},
+		},
+		Footer: "War 1",
+	}
+	want := "Title\n\nHello world\n\nEnds: 1970-01-01T00:00:00Z\nScore: 42\n\nWar 1"
+	if got := m.Format(domain.PlainMarkup(utcFormatter)); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestMessage_FormatMultiLineField(t *testing.T) {
+	m := domain.Message{
+		Fields: []domain.Field{
+			{Name: "A", Value: []domain.Line{{domain.Text("one")}, {domain.Text("two")}}},
+			{Name: "B", Value: []domain.Line{{domain.Text("three")}}},
+		},
+	}
+	want := "A:\n  one\n  two\n\nB: three"
+	if got := m.Format(domain.PlainMarkup(utcFormatter)); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestMessage_FormatMarkup(t *testing.T) {
+	mk := domain.PlainMarkup(utcFormatter)
+	mk.Bold = func(s string) string { return "<b>" + s + "</b>" }
+	mk.Italic = func(s string) string { return "<i>" + s + "</i>" }
+	m := domain.Message{Title: "T", Footer: "F"}
+	if got := m.Format(mk); got != "<b>T</b>\n\n<i>F</i>" {
+		t.Errorf("unexpected output %q", got)
+	}
+}
+
+func TestSpan_RelativeUsesText(t *testing.T) {
+	s := domain.Span{Text: "1h", Time: time.Now(), Relative: true}
+	if got := (domain.Line{s}).Format(domain.PlainMarkup(utcFormatter)); got != "1h" {
+		t.Errorf("expected relative text, got %q", got)
+	}
+}
+
+func TestMessageStyle_Valid(t *testing.T) {
+	for _, s := range []domain.MessageStyle{"", domain.MessageStyleTemplate, domain.MessageStyleStructured} {
+		if !s.Valid() {
+			t.Errorf("expected %q to be valid", s)
+		}
+	}
+	if domain.MessageStyle("fancy").Valid() {
+		t.Error("expected unknown style to be invalid")
+	}
+}
+
+func TestStatusMessage_Fields(t *testing.T) {
+	c := testutil.CampaignWithActiveDefend()
+	m := domain.CatalogFor(domain.LocaleEnglish).StatusMessage(c, nil)
+	if m.Title != "War 159 — Status" {
+		t.Errorf("unexpected title %q", m.Title)
+	}
+	if len(m.Fields) != len(c.FactionsStatus)+1 {
+		t.Fatalf("expected one field per faction plus active events, got %d", len(m.Fields))
+	}
+	events, ok := findField(m, "Active events")
+	if !ok {
+		t.Fatal("expected an Active events field")
+	}
+	line := events.Value[0]
+	if !line[1].IsTime() || !line[1].Relative {
+		t.Errorf("expected relative end time span, got %+v", line)
+	}
+}
+
+func TestStatusMessage_MatchesFormatStatus(t *testing.T) {
+	c := testutil.CampaignWithNoDefend()
+	m := domain.CatalogFor(domain.LocaleEnglish).StatusMessage(c, nil)
+	text := domain.FormatStatus(c, nil)
+	for _, f := range m.Fields {
+		for _, l := range f.Value {
+			if s := l.Format(domain.PlainMarkup(utcFormatter)); !strings.Contains(text, s) {
+				t.Errorf("field line %q not in FormatStatus output", s)
+			}
+		}
+	}
+}
+
+func TestStatisticsMessage(t *testing.T) {
+	c := &domain.CampaignStatus{
+		FactionsStatus: []domain.FactionStatus{{Season: 7}},
+		Statistics:     []domain.Statistics{{Season: 7, Kills: 1234}},
+	}
+	m := domain.CatalogFor(domain.LocaleEnglish).StatisticsMessage(c)
+	kills, ok := findField(m, "Kills")
+	if !ok {
+		t.Fatalf("expected Kills field, got %+v", m.Fields)
+	}
+	if got := kills.Value[0].Format(domain.PlainMarkup(utcFormatter)); got != "1,234" {
+		t.Errorf("expected 1,234, got %q", got)
+	}
+
+	empty := domain.CatalogFor(domain.LocaleEnglish).StatisticsMessage(&domain.CampaignStatus{})
+	if len(empty.Fields) != 0 || len(empty.Body) != 1 {
+		t.Errorf("expected only a body line without statistics, got %+v", empty)
+	}
+}
+
+func TestBuildMessage_DefendRegionStarted(t *testing.T) {
+	e := testutil.DefendEventActive()
+	e.Enemy = domain.EnemyBug
+	e.Region = 3
+	cat := domain.CatalogFor(domain.LocaleEnglish)
+	m, err := cat.BuildMessage(cat.Titles, domain.EventMessage{
+		Kind: domain.EventKindDefend, Transition: domain.EventTransitionStarted, DefendEvent: e,
+	}, utcFormatter)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if m.Title != "⚔️ The Bugs are attacking Ross System!" {
+		t.Errorf("unexpected title %q", m.Title)
+	}
+	if m.Severity != domain.SeverityWarning {
+		t.Errorf("expected warning severity, got %q", m.Severity)
+	}
+	if m.Footer != "War 159" || !m.Timestamp.Equal(e.StartTime) {
+		t.Errorf("unexpected footer/timestamp: %q %v", m.Footer, m.Timestamp)
+	}
+	text := m.Format(domain.PlainMarkup(utcFormatter))
+	for _, want := range []string{"Region: Ross System (3/10)", "Capital: Tiberia", "Ends: " + utcFormatter(e.EndTime)} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in:\n%s", want, text)
+		}
+	}
+}
+
+func TestBuildMessage_FinishedShowsProgress(t *testing.T) {
+	cat := domain.CatalogFor(domain.LocaleEnglish)
+	m, err := cat.BuildMessage(cat.Titles, domain.EventMessage{
+		Kind: domain.EventKindDefend, Transition: domain.EventTransitionFailed, DefendEvent: testutil.DefendEventActive(),
+	}, utcFormatter)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if m.Severity != domain.SeverityCritical {
+		t.Errorf("expected critical severity, got %q", m.Severity)
+	}
+	if _, ok := findField(m, "Progress"); !ok {
+		t.Errorf("expected a Progress field, got %+v", m.Fields)
+	}
+	if _, ok := findField(m, "Region"); ok {
+		t.Error("expected no Region field for Super Earth")
+	}
+}
+
+func TestBuildMessage_UserTitle(t *testing.T) {
+	cat := domain.CatalogFor(domain.LocaleSpanish)
+	titles := domain.MergeTemplates(cat.Titles, domain.Templates{MilestoneReached: "¡{MILESTONE}!"})
+	m, err := cat.BuildMessage(titles, domain.EventMessage{
+		Kind:           domain.EventKindMilestone,
+		Transition:     domain.EventTransitionReached,
+		MilestoneEvent: &domain.MilestoneEvent{Season: 3, Counter: domain.MilestoneKills, Milestone: 1000000, Value: 1000412},
+	}, utcFormatter)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if m.Title != "¡1.000.000!" {
+		t.Errorf("unexpected title %q", m.Title)
+	}
+	total, ok := findField(m, "Total")
+	if !ok || total.Value[0][0].Text != "1.000.412" {
+		t.Errorf("unexpected Total field %+v", total)
+	}
+	if m.Footer != "Guerra 3" {
+		t.Errorf("unexpected footer %q", m.Footer)
+	}
+}
+
+func TestBuildMessage_UnhandledEvent(t *testing.T) {
+	cat := domain.CatalogFor(domain.LocaleEnglish)
+	if _, err := cat.BuildMessage(cat.Titles, domain.EventMessage{Kind: "unknown"}, utcFormatter); err == nil {
+		t.Error("expected error for unhandled event")
+	}
+}
+
+func TestCatalogTitles_Valid(t *testing.T) {
+	for _, l := range []domain.Locale{domain.LocaleEnglish, domain.LocaleSpanish} {
+		if problems := domain.ValidateTemplates(domain.CatalogFor(l).Titles); len(problems) != 0 {
+			t.Errorf("%s titles have problems: %v", l, problems)
+		}
+	}
+}
+
+func TestBuildMessage_AttackStarted(t *testing.T) {
+	e := testutil.AttackEventActive()
+	cat := domain.CatalogFor(domain.LocaleEnglish)
+	m, err := cat.BuildMessage(cat.Titles, domain.EventMessage{
+		Kind: domain.EventKindAttack, Transition: domain.EventTransitionStarted, AttackEvent: &e,
+	}, utcFormatter)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, name := range []string{"Region", "Capital", "Ends", "Progress"} {
+		if _, ok := findField(m, name); !ok {
+			t.Errorf("expected %s field, got %+v", name, m.Fields)
+		}
+	}
+}
+
+func TestRegionMessage_States(t *testing.T) {
+	cat := domain.CatalogFor(domain.LocaleEnglish)
+	// 4.5 sectors earned: regions 1–4 liberated, 5 is the front.
+	c := &domain.CampaignStatus{FactionsStatus: []domain.FactionStatus{
+		{Enemy: domain.EnemyBug, Season: 160, Points: 450, PointsMax: 1000, Status: domain.FactionStatusActive},
+	}}
+
+	state := func(m domain.Message) string {
+		f, ok := findField(m, "State")
+		if !ok {
+			t.Fatalf("expected a State field, got %+v", m.Fields)
+		}
+		return f.Value[0].Format(domain.PlainMarkup(nil))
+	}
+
+	m := cat.RegionMessage(c, domain.EnemyBug, 3)
+	if m.Title != "Ross System — The Bugs" || state(m) != "✅ Liberated" {
+		t.Errorf("unexpected region 3: %q %q", m.Title, state(m))
+	}
+	if f, _ := findField(m, "Capital"); len(f.Value) == 0 || f.Value[0].Format(domain.PlainMarkup(nil)) != "Tiberia" {
+		t.Errorf("unexpected capital %+v", f)
+	}
+
+	m = cat.RegionMessage(c, domain.EnemyBug, 5)
+	if state(m) != "🎯 Front line" {
+		t.Errorf("expected region 5 to be the front, got %q", state(m))
+	}
+	if f, ok := findField(m, "Progress"); !ok || !strings.Contains(f.Value[1].Format(domain.PlainMarkup(nil)), "50 / 100 pts") {
+		t.Errorf("unexpected progress %+v", f)
+	}
+
+	if m = cat.RegionMessage(c, domain.EnemyBug, 8); state(m) != "🛑 Enemy territory" {
+		t.Errorf("expected region 8 to be enemy territory, got %q", state(m))
+	}
+
+	c.DefendEvent = &domain.DefendEvent{Enemy: domain.EnemyBug, Region: 3, Status: domain.EventStatusActive, EndTime: time.Now().Add(time.Hour)}
+	m = cat.RegionMessage(c, domain.EnemyBug, 3)
+	if state(m) != "⚔️ Under attack" {
+		t.Errorf("expected region 3 to be under attack, got %q", state(m))
+	}
+	if _, ok := findField(m, "Ends"); !ok {
+		t.Errorf("expected an Ends field, got %+v", m.Fields)
+	}
+}
+
+func TestFindRegion(t *testing.T) {
+	for _, tc := range []struct {
+		query string
+		want  int
+		ok    bool
+	}{
+		{"3", 3, true},
+		{"ross system", 3, true},
+		{"Kepler Prime", 11, true},
+		{"0", 0, false},
+		{"12", 0, false},
+		{"Atlantis", 0, false},
+	} {
+		got, ok := domain.FindRegion(domain.EnemyBug, tc.query)
+		if got != tc.want || ok != tc.ok {
+			t.Errorf("FindRegion(%q) = %d, %v; want %d, %v", tc.query, got, ok, tc.want, tc.ok)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package domain
+
+import "sort"
+
+// MilestoneCounter names a cumulative Statistics counter that milestones track.
+type MilestoneCounter string
+
+const (
+	MilestoneKills            MilestoneCounter = "kills"
+	MilestoneDeaths           MilestoneCounter = "deaths"
+	MilestoneAccidentals      MilestoneCounter = "accidentals"
+	MilestoneMissions         MilestoneCounter = "missions"
+	MilestonePlanetsLiberated MilestoneCounter = "planets_liberated"
+)
+
+// Label returns the English name used in messages.
+func (m MilestoneCounter) Label() string {
+	return english.CounterLabel(m)
+}
+
+// MilestoneEvent is a one-time notification that a counter reached a milestone.
+type MilestoneEvent struct {
+	Season    int
+	Counter   MilestoneCounter
+	Milestone int
+	// Value is the counter's current total, which may exceed Milestone.
+	Value int
+}
+
+// MilestoneRules maps each counter to the milestones announced for it.
+type MilestoneRules map[MilestoneCounter][]int
+
+// Enabled reports whether any milestone is configured.
+func (r MilestoneRules) Enabled() bool {
+	for _, ms := range r {
+		if len(ms) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// CounterValue returns the counter summed across all factions.
+func CounterValue(c *CampaignStatus, counter MilestoneCounter) int {
+	total := 0
+	for _, s := range c.Statistics {
+		switch counter {
+		case MilestoneKills:
+			total += s.Kills
+		case MilestoneDeaths:
+			total += s.Deaths
+		case MilestoneAccidentals:
+			total += s.Accidentals
+		case MilestoneMissions:
+			total += s.Missions
+		case MilestonePlanetsLiberated:
+			total += s.CompletedPlanets
+		}
+	}
+	return total
+}
+
+// DetectMilestones returns the milestones crossed between previous and current.
+// Counters reset every season, so a previous snapshot from another season
+// counts as zero. Results are ordered by counter, then milestone.
+func DetectMilestones(rules MilestoneRules, previous, current *CampaignStatus) []MilestoneEvent {
+	season := current.Season()
+	samePrevSeason := previous != nil && previous.Season() == season
+
+	counters := make([]MilestoneCounter, 0, len(rules))
+	for counter := range rules {
+		counters = append(counters, counter)
+	}
+	sort.Slice(counters, func(i, j int) bool { return counters[i] < counters[j] })
+
+	var events []MilestoneEvent
+	for _, counter := range counters {
+		cur := CounterValue(current, counter)
+		prev := 0
+		if samePrevSeason {
+			prev = CounterValue(previous, counter)
+		}
+
+		milestones := append([]int(nil), rules[counter]...)
+		sort.Ints(milestones)
+		for _, m := range milestones {
+			if prev < m && cur >= m {
+				events = append(events, MilestoneEvent{
+					Season:    season,
+					Counter:   counter,
+					Milestone: m,
+					Value:     cur,
+				})
+			}
+		}
+	}
+	return events
+}
@@ -0,0 +1,99 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/ametis70/hellbot/internal/domain"
+)
+
+func campaignWithStats(season int, stats ...domain.Statistics) *domain.CampaignStatus {
+	return &domain.CampaignStatus{
+		FactionsStatus: []domain.FactionStatus{{Season: season}},
+		Statistics:     stats,
+	}
+}
+
+func TestCounterValue_SumsFactions(t *testing.T) {
+	c := campaignWithStats(159,
+		domain.Statistics{Kills: 10, Deaths: 1, Accidentals: 2, Missions: 3, CompletedPlanets: 4},
+		domain.Statistics{Kills: 20, Deaths: 1, Accidentals: 2, Missions: 3, CompletedPlanets: 4},
+	)
+	cases := map[domain.MilestoneCounter]int{
+		domain.MilestoneKills:            30,
+		domain.MilestoneDeaths:           2,
+		domain.MilestoneAccidentals:      4,
+		domain.MilestoneMissions:         6,
+		domain.MilestonePlanetsLiberated: 8,
+	}
+	for counter, want := range cases {
+		if got := domain.CounterValue(c, counter); got != want {
+			t.Errorf("%s: expected %d, got %d", counter, want, got)
+		}
+	}
+}
+
+func TestDetectMilestones_Crossing(t *testing.T) {
+	rules := domain.MilestoneRules{domain.MilestoneKills: {5000, 1000, 10000}}
+	previous := campaignWithStats(159, domain.Statistics{Kills: 900})
+	current := campaignWithStats(159, domain.Statistics{Kills: 6000})
+
+	events := domain.DetectMilestones(rules, previous, current)
+	if len(events) != 2 {
+		t.Fatalf("expected 2 milestones, got %d", len(events))
+	}
+	if events[0].Milestone != 1000 || events[1].Milestone != 5000 {
+		t.Errorf("expected milestones in ascending order, got %d and %d", events[0].Milestone, events[1].Milestone)
+	}
+	if events[0].Value != 6000 || events[0].Season != 159 {
+		t.Errorf("unexpected event: %+v", events[0])
+	}
+}
+
+func TestDetectMilestones_AlreadyPassed(t *testing.T) {
+	rules := domain.MilestoneRules{domain.MilestoneKills: {1000}}
+	previous := campaignWithStats(159, domain.Statistics{Kills: 1500})
+	current := campaignWithStats(159, domain.Statistics{Kills: 2000})
+	if events := domain.DetectMilestones(rules, previous, current); len(events) != 0 {
+		t.Errorf("expected no milestones, got %d", len(events))
+	}
+}
+
+func TestDetectMilestones_NewSeasonStartsFromZero(t *testing.T) {
+	rules := domain.MilestoneRules{domain.MilestoneMissions: {100}}
+	previous := campaignWithStats(159, domain.Statistics{Missions: 50000})
+	current := campaignWithStats(160, domain.Statistics{Missions: 150})
+
+	events := domain.DetectMilestones(rules, previous, current)
+	if len(events) != 1 || events[0].Season != 160 {
+		t.Fatalf("expected one season 160 milestone, got %+v", events)
+	}
+}
+
+func TestMilestoneRules_Enabled(t *testing.T) {
+	if (domain.MilestoneRules{domain.MilestoneKills: nil}).Enabled() {
+		t.Error("expected rules without milestones to be disabled")
+	}
+	if !(domain.MilestoneRules{domain.MilestoneKills: {1}}).Enabled() {
+		t.Error("expected rules with a milestone to be enabled")
+	}
+}
+
+func TestRenderEvent_MilestoneReached(t *testing.T) {
+	tmpl := domain.Templates{MilestoneReached: "{MILESTONE} {COUNTER} (now {VALUE}) in war {SEASON}"}
+	got, err := domain.RenderEvent(tmpl, domain.EventMessage{
+		Kind:       domain.EventKindMilestone,
+		Transition: domain.EventTransitionReached,
+		MilestoneEvent: &domain.MilestoneEvent{
+			Season:    159,
+			Counter:   domain.MilestonePlanetsLiberated,
+			Milestone: 1000,
+			Value:     1002,
+		},
+	}, timeFormatter)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "1,000 planets liberated (now 1,002) in war 159" {
+		t.Errorf("unexpected render: %q", got)
+	}
+}
@@ -0,0 +1,142 @@
+package domain
+
+import "time"
+
+// PlayersTrigger identifies which rule produced a player population alert.
+type PlayersTrigger string
+
+const (
+	// PlayersTriggerThreshold fires when the online count crosses an absolute threshold.
+	PlayersTriggerThreshold PlayersTrigger = "threshold"
+	// PlayersTriggerChange fires when the online count changes by at least a
+	// configured percentage within a time window.
+	PlayersTriggerChange PlayersTrigger = "change"
+)
+
+// PlayersEvent describes a player population alert.
+type PlayersEvent struct {
+	Season  int
+	Trigger PlayersTrigger
+	Players int
+	// Previous is the count the alert was measured against: the previous poll
+	// for threshold alerts, the oldest sample in the window for change alerts.
+	Previous  int
+	Threshold int
+	// ChangePercent is the signed change from Previous to Players.
+	ChangePercent int
+	Window        time.Duration
+}
+
+// PlayerAlertRules configures player population alerts.
+// Thresholds are absolute online counts; ChangePercent and Window describe a
+// relative surge or drop. A zero ChangePercent disables change alerts.
+// Hysteresis is the percentage below a threshold the count must fall to
+// before it counts as below again, so a count hovering around a threshold
+// does not alert on every poll.
+type PlayerAlertRules struct {
+	Thresholds    []int
+	ChangePercent int
+	Window        time.Duration
+	Hysteresis    int
+}
+
+// ThresholdSides records, per threshold, whether the online count was last
+// reported above it.
+type ThresholdSides map[int]bool
+
+// lowerBound is the count below which t counts as crossed downwards.
+func (r PlayerAlertRules) lowerBound(t int) int {
+	return t - t*r.Hysteresis/100
+}
+
+// Enabled reports whether any rule is configured.
+func (r PlayerAlertRules) Enabled() bool {
+	return len(r.Thresholds) > 0 || r.ChangePercent > 0
+}
+
+// PlayerSample is an online player count observed at a point in time.
+type PlayerSample struct {
+	Time    time.Time
+	Players int
+}
+
+// TotalPlayers returns the number of players online across all factions.
+func TotalPlayers(c *CampaignStatus) int {
+	total := 0
+	for _, s := range c.Statistics {
+		total += s.Players
+	}
+	return total
+}
+
+// DetectPlayerAlerts evaluates the rules for a new sample. history holds the
+// earlier samples, oldest first; its last entry is the previous poll. sides
+// holds the side of each threshold last reported and is updated as threshold
+// alerts fire; a threshold without an entry starts on the previous poll's side.
+func DetectPlayerAlerts(rules PlayerAlertRules, season int, history []PlayerSample, current PlayerSample, sides ThresholdSides) []PlayersEvent {
+	if len(history) == 0 {
+		return nil
+	}
+
+	var alerts []PlayersEvent
+
+	prev := history[len(history)-1].Players
+	for _, t := range rules.Thresholds {
+		above, ok := sides[t]
+		if !ok {
+			above = prev >= t
+		}
+		sides[t] = above
+		if above && current.Players >= rules.lowerBound(t) || !above && current.Players < t {
+			continue
+		}
+		sides[t] = !above
+		alerts = append(alerts, PlayersEvent{
+			Season:        season,
+			Trigger:       PlayersTriggerThreshold,
+			Players:       current.Players,
+			Previous:      prev,
+			Threshold:     t,
+			ChangePercent: pctChange(prev, current.Players),
+		})
+	}
+
+	if rules.ChangePercent > 0 {
+		since := current.Time.Add(-rules.Window)
+		for _, s := range history {
+			if s.Time.Before(since) {
+				continue
+			}
+			change := pctChange(s.Players, current.Players)
+			if s.Players > 0 && (change >= rules.ChangePercent || -change >= rules.ChangePercent) {
+				alerts = append(alerts, PlayersEvent{
+					Season:        season,
+					Trigger:       PlayersTriggerChange,
+					Players:       current.Players,
+					Previous:      s.Players,
+					ChangePercent: change,
+					Window:        rules.Window,
+				})
+			}
+			// Only the oldest sample inside the window is the baseline.
+			break
+		}
+	}
+
+	return alerts
+}
+
+// Transition returns rose when the count went up, fell otherwise.
+func (e *PlayersEvent) Transition() EventTransition {
+	if e.Players >= e.Previous {
+		return EventTransitionRose
+	}
+	return EventTransitionFell
+}
+
+func pctChange(from, to int) int {
+	if from == 0 {
+		return 0
+	}
+	return (to - from) * 100 / from
+}
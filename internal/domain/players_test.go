@@ -0,0 +1,169 @@
+package domain_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ametis70/hellbot/internal/domain"
+)
+
+var playersT0 = time.Unix(1784505880, 0).UTC()
+
+func sample(offset time.Duration, players int) domain.PlayerSample {
+	return domain.PlayerSample{Time: playersT0.Add(offset), Players: players}
+}
+
+func TestTotalPlayers(t *testing.T) {
+	c := &domain.CampaignStatus{Statistics: []domain.Statistics{{Players: 100}, {Players: 250}, {Players: 50}}}
+	if got := domain.TotalPlayers(c); got != 400 {
+		t.Errorf("expected 400, got %d", got)
+	}
+}
+
+func TestDetectPlayerAlerts_NoHistory(t *testing.T) {
+	rules := domain.PlayerAlertRules{Thresholds: []int{100}, ChangePercent: 10, Window: time.Hour}
+	if alerts := domain.DetectPlayerAlerts(rules, 159, nil, sample(0, 500), domain.ThresholdSides{}); len(alerts) != 0 {
+		t.Errorf("expected no alerts without history, got %d", len(alerts))
+	}
+}
+
+func TestDetectPlayerAlerts_ThresholdCrossings(t *testing.T) {
+	rules := domain.PlayerAlertRules{Thresholds: []int{1000, 5000}}
+	cases := []struct {
+		name      string
+		prev, cur int
+		want      []int
+		rose      bool
+	}{
+		{"below to above one", 900, 1200, []int{1000}, true},
+		{"below to above both", 900, 6000, []int{1000, 5000}, true},
+		{"exactly at threshold", 999, 1000, []int{1000}, true},
+		{"above to below", 5200, 4800, []int{5000}, false},
+		{"no crossing", 1200, 4000, nil, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			history := []domain.PlayerSample{sample(-time.Minute, c.prev)}
+			alerts := domain.DetectPlayerAlerts(rules, 159, history, sample(0, c.cur), domain.ThresholdSides{})
+			if len(alerts) != len(c.want) {
+				t.Fatalf("expected %d alerts, got %d", len(c.want), len(alerts))
+			}
+			for i, a := range alerts {
+				if a.Trigger != domain.PlayersTriggerThreshold || a.Threshold != c.want[i] {
+					t.Errorf("alert %d: got trigger=%s threshold=%d", i, a.Trigger, a.Threshold)
+				}
+				wantTransition := domain.EventTransitionFell
+				if c.rose {
+					wantTransition = domain.EventTransitionRose
+				}
+				if a.Transition() != wantTransition {
+					t.Errorf("alert %d: expected transition %s, got %s", i, wantTransition, a.Transition())
+				}
+			}
+		})
+	}
+}
+
+func TestDetectPlayerAlerts_ChangeUsesOldestSampleInWindow(t *testing.T) {
+	rules := domain.PlayerAlertRules{ChangePercent: 50, Window: time.Hour}
+	history := []domain.PlayerSample{
+		sample(-2*time.Hour, 100), // outside the window
+		sample(-50*time.Minute, 1000),
+		sample(-10*time.Minute, 1400),
+	}
+	alerts := domain.DetectPlayerAlerts(rules, 159, history, sample(0, 1600), domain.ThresholdSides{})
+	if len(alerts) != 1 {
+		t.Fatalf("expected 1 alert, got %d", len(alerts))
+	}
+	a := alerts[0]
+	if a.Trigger != domain.PlayersTriggerChange || a.Previous != 1000 || a.ChangePercent != 60 {
+		t.Errorf("unexpected alert: %+v", a)
+	}
+	if a.Window != time.Hour {
+		t.Errorf("expected window 1h, got %s", a.Window)
+	}
+}
+
+func TestDetectPlayerAlerts_Drop(t *testing.T) {
+	rules := domain.PlayerAlertRules{ChangePercent: 25, Window: time.Hour}
+	history := []domain.PlayerSample{sample(-30*time.Minute, 2000)}
+	alerts := domain.DetectPlayerAlerts(rules, 159, history, sample(0, 1000), domain.ThresholdSides{})
+	if len(alerts) != 1 {
+		t.Fatalf("expected 1 alert, got %d", len(alerts))
+	}
+	if alerts[0].ChangePercent != -50 || alerts[0].Transition() != domain.EventTransitionFell {
+		t.Errorf("unexpected alert: %+v", alerts[0])
+	}
+}
+
+func TestDetectPlayerAlerts_ChangeBelowPercent(t *testing.T) {
+	rules := domain.PlayerAlertRules{ChangePercent: 25, Window: time.Hour}
+	history := []domain.PlayerSample{sample(-30*time.Minute, 1000)}
+	if alerts := domain.DetectPlayerAlerts(rules, 159, history, sample(0, 1200), domain.ThresholdSides{}); len(alerts) != 0 {
+		t.Errorf("expected no alerts for a 20%% change, got %d", len(alerts))
+	}
+}
+
+func TestDetectPlayerAlerts_HysteresisIgnoresOscillation(t *testing.T) {
+	rules := domain.PlayerAlertRules{Thresholds: []int{1000}, Hysteresis: 5}
+	sides := domain.ThresholdSides{}
+	// Hovering around 1000 alerts once; only dropping below 950 counts as a
+	// fall, after which reaching 1000 alerts again.
+	counts := []int{990, 1010, 990, 1005, 960, 940, 1000}
+	var history []domain.PlayerSample
+	var got []domain.EventTransition
+	for i, c := range counts {
+		s := sample(time.Duration(i)*time.Minute, c)
+		for _, a := range domain.DetectPlayerAlerts(rules, 159, history, s, sides) {
+			got = append(got, a.Transition())
+		}
+		history = append(history, s)
+	}
+	want := []domain.EventTransition{domain.EventTransitionRose, domain.EventTransitionFell, domain.EventTransitionRose}
+	if !slices.Equal(got, want) {
+		t.Errorf("expected alerts %v, got %v", want, got)
+	}
+}
+
+func TestRenderEvent_PlayersTemplates(t *testing.T) {
+	tmpl := domain.Templates{
+		PlayersAboveThreshold: "above {THRESHOLD}: {PLAYERS}",
+		PlayersBelowThreshold: "below {THRESHOLD}: {PLAYERS}",
+		PlayersSurge:          "surge {CHANGE_PERCENT}% in {WINDOW} from {PREVIOUS_PLAYERS}",
+		PlayersDrop:           "drop {CHANGE_PERCENT}% in {WINDOW}",
+	}
+	cases := []struct {
+		name string
+		e    domain.PlayersEvent
+		want string
+	}{
+		{"above", domain.PlayersEvent{Trigger: domain.PlayersTriggerThreshold, Players: 1200, Previous: 900, Threshold: 1000}, "above 1000: 1200"},
+		{"below", domain.PlayersEvent{Trigger: domain.PlayersTriggerThreshold, Players: 800, Previous: 1100, Threshold: 1000}, "below 1000: 800"},
+		{"surge", domain.PlayersEvent{Trigger: domain.PlayersTriggerChange, Players: 1500, Previous: 1000, ChangePercent: 50, Window: 90 * time.Minute}, "surge 50% in 1h30m from 1000"},
+		{"drop", domain.PlayersEvent{Trigger: domain.PlayersTriggerChange, Players: 500, Previous: 1000, ChangePercent: -50, Window: time.Hour}, "drop 50% in 1h"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			e := c.e
+			got, err := domain.RenderEvent(tmpl, domain.EventMessage{
+				Kind:         domain.EventKindPlayers,
+				Transition:   e.Transition(),
+				PlayersEvent: &e,
+			}, timeFormatter)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != c.want {
+				t.Errorf("expected %q, got %q", c.want, got)
+			}
+		})
+	}
+}
+
+func TestRenderEvent_PlayersNilEvent(t *testing.T) {
+	_, err := domain.RenderEvent(domain.Templates{}, domain.EventMessage{Kind: domain.EventKindPlayers, Transition: domain.EventTransitionRose}, timeFormatter)
+	if err == nil {
+		t.Error("expected error for nil players event, got nil")
+	}
+}
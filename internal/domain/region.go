@@ -1,6 +1,10 @@
 package domain
 
-import "fmt"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
 
 // Region holds the name and capital of a galactic campaign region.
 type Region struct {
@@ -86,3 +90,22 @@ func IsHomeworld(region int) bool {
 
 // TotalRegions is the number of non-homeworld, non-Super-Earth regions per faction.
 const TotalRegions = 10
+
+// FindRegion returns the number of the faction's region, from 1 to the
+// homeworld, whose number, name or capital is s, ignoring case.
+func FindRegion(enemy Enemy, s string) (int, bool) {
+	s = strings.TrimSpace(s)
+	if n, err := strconv.Atoi(s); err == nil {
+		if _, ok := regionNames[enemy][n]; ok && n > SuperEarthRegion {
+			return n, true
+		}
+		return 0, false
+	}
+	for n := 1; n <= HomeWorldRegion; n++ {
+		r, ok := regionNames[enemy][n]
+		if ok && (strings.EqualFold(r.Name, s) || strings.EqualFold(r.Capital, s)) {
+			return n, true
+		}
+	}
+	return 0, false
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 	"time"
+	"unicode/utf8"
 )
 
 // ParseEnemy converts a faction name string (case-insensitive) to an Enemy value.
@@ -21,42 +22,55 @@ func ParseEnemy(s string) (Enemy, bool) {
 	}
 }
 
-// FormatStatus returns a human-readable war status string.
+// FormatStatus returns a human-readable war status string in English.
 // If filter is non-nil, only the matching faction is shown.
 func FormatStatus(c *CampaignStatus, filter *Enemy) string {
+	return english.FormatStatus(c, filter)
+}
+
+// FormatStatistics returns a human-readable statistics string in English with
+// all factions summed.
+func FormatStatistics(c *CampaignStatus) string {
+	return english.FormatStatistics(c)
+}
+
+// FormatStatus returns a human-readable war status string.
+// If filter is non-nil, only the matching faction is shown.
+func (cat *Catalog) FormatStatus(c *CampaignStatus, filter *Enemy) string {
 	var sb strings.Builder
 
-	// Determine season from first faction or fall back.
-	season := 0
-	if len(c.FactionsStatus) > 0 {
-		season = c.FactionsStatus[0].Season
-	}
-	fmt.Fprintf(&sb, "War %d — Status\n\n", season)
+	fmt.Fprintf(&sb, cat.StatusTitle+"\n\n", c.Season())
 
 	// Per-faction progress.
 	for _, f := range c.FactionsStatus {
 		if filter != nil && f.Enemy != *filter {
 			continue
 		}
-		sb.WriteString(formatFactionStatus(f, c))
+		sb.WriteString(cat.formatFactionStatus(f, c))
 	}
 
 	// Active events.
-	var events []string
+	if events := cat.activeEvents(c, filter); len(events) > 0 {
+		sb.WriteString("\n" + cat.ActiveEvents + "\n")
+		for _, ev := range events {
+			sb.WriteString("  " + ev.Format(PlainMarkup(nil)) + "\n")
+		}
+	}
+
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+// activeEvents returns one line per active event, with a relative end time.
+func (cat *Catalog) activeEvents(c *CampaignStatus, filter *Enemy) []Line {
+	var events []Line
 	if c.DefendEvent != nil && c.DefendEvent.Status == EventStatusActive {
 		e := c.DefendEvent
 		if filter == nil || e.Enemy == *filter {
 			if IsSuperEarth(e.Region) {
-				events = append(events, fmt.Sprintf(
-					"⚔️  The %s are attacking Super Earth — ends %s",
-					e.Enemy, formatRelativeTime(e.EndTime),
-				))
+				events = append(events, cat.lineWithTime(cat.EventSuperEarth, e.EndTime, cat.FactionName(e.Enemy)))
 			} else {
 				region := GetRegion(e.Enemy, e.Region)
-				events = append(events, fmt.Sprintf(
-					"⚔️  The %s are attacking sector %d: %s — ends %s",
-					e.Enemy, e.Region, region.Name, formatRelativeTime(e.EndTime),
-				))
+				events = append(events, cat.lineWithTime(cat.EventRegion, e.EndTime, cat.FactionName(e.Enemy), e.Region, region.Name))
 			}
 		}
 	}
@@ -67,30 +81,48 @@ func FormatStatus(c *CampaignStatus, filter *Enemy) string {
 		if filter != nil && e.Enemy != *filter {
 			continue
 		}
-		events = append(events, fmt.Sprintf(
-			"🚀 Attacking %s homeworld — ends %s",
-			e.Enemy, formatRelativeTime(e.EndTime),
-		))
+		events = append(events, cat.lineWithTime(cat.EventAttack, e.EndTime, cat.FactionName(e.Enemy)))
 	}
+	return events
+}
 
-	if len(events) > 0 {
-		sb.WriteString("\nActive events:\n")
-		for _, ev := range events {
-			sb.WriteString("  " + ev + "\n")
-		}
-	}
+// lineWithTime formats format with args followed by a relative time span for
+// end, which must be the last verb.
+func (cat *Catalog) lineWithTime(format string, end time.Time, args ...any) Line {
+	const mark = "\x00"
+	before, after, _ := strings.Cut(fmt.Sprintf(format, append(args, mark)...), mark)
+	return Line{Text(before), cat.relativeSpan(end), Text(after)}
+}
 
-	return strings.TrimRight(sb.String(), "\n")
+// relativeSpan returns a relative timestamp span for t.
+func (cat *Catalog) relativeSpan(t time.Time) Span {
+	return Span{Text: cat.relativeTime(t), Time: t, Relative: true}
 }
 
-func formatFactionStatus(f FactionStatus, c *CampaignStatus) string {
+func (cat *Catalog) formatFactionStatus(f FactionStatus, c *CampaignStatus) string {
+	name := fmt.Sprintf(cat.TheFaction, cat.FactionName(f.Enemy))
 	switch f.Status {
 	case FactionStatusDefeated:
-		return fmt.Sprintf("%-16s defeated\n", "The "+f.Enemy.String())
+		return fmt.Sprintf("%-16s %s\n", name, cat.FactionDefeated)
 	case FactionStatusHidden:
-		return fmt.Sprintf("%-16s hidden\n", "The "+f.Enemy.String())
+		return fmt.Sprintf("%-16s %s\n", name, cat.FactionHidden)
 	}
 
+	var sb strings.Builder
+	fmt.Fprintf(&sb, cat.FactionActive+"\n", name)
+	for i, l := range cat.factionProgress(f, c) {
+		sb.WriteString("  " + l.Format(PlainMarkup(nil)) + "\n")
+		// Blank line after the war progress and after the sector progress.
+		if i == 1 || i == 4 {
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
+
+// factionProgress returns five lines for an active faction: the overall
+// progress bar and points, then the current sector, its bar and its points.
+func (cat *Catalog) factionProgress(f FactionStatus, c *CampaignStatus) []Line {
 	// Overall war progress: points counts down from pointsMax as helldivers advance.
 	totalPct := 0
 	if f.PointsMax > 0 {
@@ -99,62 +131,44 @@ func formatFactionStatus(f FactionStatus, c *CampaignStatus) string {
 			totalPct = 100
 		}
 	}
-	totalBar := progressBar(totalPct, 10)
 
-	// Current sector: sectorsEarned = floor(points / pointsPerSector)
-	// displayed as sectorsEarned/TotalRegions (e.g. "6/10").
-	// sectorPoints = points - sectorsEarned * pointsPerSector (remainder within current sector).
-	sectorNum := 0
+	sectorNum, sectorPoints, sectorPointsMax := f.sectorProgress()
 	sectorPct := 0
-	sectorPoints := 0
-	sectorPointsMax := 0
-	if f.PointsMax > 0 {
-		pointsPerSector := f.PointsMax / TotalRegions
-		if pointsPerSector > 0 {
-			sectorsEarned := f.Points / pointsPerSector
-			sectorNum = sectorsEarned
-			if sectorNum > TotalRegions {
-				sectorNum = TotalRegions
-			}
-			sectorPointsMax = pointsPerSector
-			sectorPoints = f.Points - sectorsEarned*pointsPerSector
-			sectorPct = sectorPoints * 100 / pointsPerSector
-		}
+	if sectorPointsMax > 0 {
+		sectorPct = sectorPoints * 100 / sectorPointsMax
 	}
 
 	region := GetRegion(f.Enemy, sectorNum+1)
 
-	// Event annotation for the current sector.
-	eventNote := ""
+	return []Line{
+		{Code(fmt.Sprintf("%s %3d%%", progressBar(totalPct, 10), totalPct))},
+		{Text(fmt.Sprintf("%s / %s %s", cat.FormatInt(f.Points), cat.FormatInt(f.PointsMax), cat.Points))},
+		{Text(fmt.Sprintf("%s %d/11: %s%s", cat.Sector, sectorNum+1, region.Name, cat.eventNote(f, c)))},
+		{Code(fmt.Sprintf("%s %3d%%", progressBar(sectorPct, 10), sectorPct))},
+		{Text(fmt.Sprintf("%s / %s %s", cat.FormatInt(sectorPoints), cat.FormatInt(sectorPointsMax), cat.Points))},
+	}
+}
+
+// eventNote annotates the current sector with the faction's active event.
+func (cat *Catalog) eventNote(f FactionStatus, c *CampaignStatus) string {
+	note := ""
 	if c.DefendEvent != nil && c.DefendEvent.Enemy == f.Enemy && c.DefendEvent.Status == EventStatusActive {
 		e := c.DefendEvent
 		if IsSuperEarth(e.Region) {
-			eventNote = " ⚔️ defending Super Earth"
+			note = " " + cat.DefendingSuperEarth
 		} else {
 			defRegion := GetRegion(e.Enemy, e.Region)
-			eventNote = fmt.Sprintf(" ⚔️ defending %s", defRegion.Name)
+			note = " " + fmt.Sprintf(cat.DefendingRegion, defRegion.Name)
 		}
 	}
 	for _, e := range c.AttackEvents {
 		if e.Enemy == f.Enemy && e.Status == EventStatusActive {
-			eventNote = " 🚀 attacking homeworld"
+			note = " " + cat.AttackingHomeworld
 		}
 	}
-
-	var sb strings.Builder
-	fmt.Fprintf(&sb, "The %s (active)\n", f.Enemy.String())
-	fmt.Fprintf(&sb, "  %s %3d%%\n", totalBar, totalPct)
-	fmt.Fprintf(&sb, "  %s / %s pts\n", fmtInt(f.Points), fmtInt(f.PointsMax))
-	sb.WriteString("\n")
-	fmt.Fprintf(&sb, "  Sector %d/11: %s%s\n", sectorNum+1, region.Name, eventNote)
-	fmt.Fprintf(&sb, "  %s %3d%%\n", progressBar(sectorPct, 10), sectorPct)
-	fmt.Fprintf(&sb, "  %s / %s pts\n", fmtInt(sectorPoints), fmtInt(sectorPointsMax))
-	sb.WriteString("\n")
-	return sb.String()
+	return note
 }
 
-// activeSector is no longer used — sector logic is inlined in formatFactionStatus.
-
 func progressBar(pct, width int) string {
 	filled := pct * width / 100
 	if filled > width {
@@ -163,10 +177,11 @@ func progressBar(pct, width int) string {
 	return "[" + strings.Repeat("█", filled) + strings.Repeat("░", width-filled) + "]"
 }
 
-func formatRelativeTime(t time.Time) string {
+// relativeTime renders the time left until t, or the catalog's Ended string.
+func (cat *Catalog) relativeTime(t time.Time) string {
 	d := time.Until(t)
 	if d < 0 {
-		return "ended"
+		return cat.Ended
 	}
 	h := int(d.Hours())
 	m := int(d.Minutes()) % 60
@@ -177,70 +192,26 @@ func formatRelativeTime(t time.Time) string {
 }
 
 // FormatStatistics returns a human-readable statistics string with all factions summed.
-func FormatStatistics(c *CampaignStatus) string {
+func (cat *Catalog) FormatStatistics(c *CampaignStatus) string {
 	var sb strings.Builder
 
-	season := 0
-	if len(c.FactionsStatus) > 0 {
-		season = c.FactionsStatus[0].Season
-	}
-	fmt.Fprintf(&sb, "War %d — Statistics\n\n", season)
+	fmt.Fprintf(&sb, cat.StatisticsTitle+"\n\n", c.Season())
 
 	if len(c.Statistics) == 0 {
-		sb.WriteString("No statistics available.\n")
+		sb.WriteString(cat.NoStatistics + "\n")
 		return strings.TrimRight(sb.String(), "\n")
 	}
 
-	var (
-		players            int
-		totalUniquePlayers int
-		missions           int
-		successfulMissions int
-		completedPlanets   int
-		defendEvents       int
-		successfulDefend   int
-		attackEvents       int
-		successfulAttack   int
-		deaths             int
-		kills              int
-		accidentals        int
-		shots              int
-		hits               int
-	)
+	rows := cat.statisticsRows(c)
 
-	for _, s := range c.Statistics {
-		players += s.Players
-		totalUniquePlayers += s.TotalUniquePlayers
-		missions += s.Missions
-		successfulMissions += s.SuccessfulMissions
-		completedPlanets += s.CompletedPlanets
-		defendEvents += s.DefendEvents
-		successfulDefend += s.SuccessfulDefendEvents
-		attackEvents += s.AttackEvents
-		successfulAttack += s.SuccessfulAttackEvents
-		deaths += s.Deaths
-		kills += s.Kills
-		accidentals += s.Accidentals
-		shots += s.Shots
-		hits += s.Hits
+	// Align values two columns past the longest label.
+	width := 0
+	for _, r := range rows {
+		width = max(width, utf8.RuneCountInString(r[0]))
+	}
+	for _, r := range rows {
+		fmt.Fprintf(&sb, "%-*s%s\n", width+2, r[0], r[1])
 	}
-
-	missionPct := pct(successfulMissions, missions)
-	defendPct := pct(successfulDefend, defendEvents)
-	attackPct := pct(successfulAttack, attackEvents)
-	hitPct := pct(hits, shots)
-
-	fmt.Fprintf(&sb, "Players online:     %s\n", fmtInt(players))
-	fmt.Fprintf(&sb, "Total players:      %s\n", fmtInt(totalUniquePlayers))
-	fmt.Fprintf(&sb, "Kills:              %s\n", fmtInt(kills))
-	fmt.Fprintf(&sb, "Deaths:             %s\n", fmtInt(deaths))
-	fmt.Fprintf(&sb, "Accidentals:        %s\n", fmtInt(accidentals))
-	fmt.Fprintf(&sb, "Shots fired:        %s\n", fmtInt(shots))
-	fmt.Fprintf(&sb, "Accuracy:           %d%%\n", hitPct)
-	fmt.Fprintf(&sb, "Missions:           %s (%s successful, %d%%)\n", fmtInt(missions), fmtInt(successfulMissions), missionPct)
-	fmt.Fprintf(&sb, "Defend events:      %s (%s successful, %d%%)\n", fmtInt(defendEvents), fmtInt(successfulDefend), defendPct)
-	fmt.Fprintf(&sb, "Attack events:      %s (%s successful, %d%%)\n", fmtInt(attackEvents), fmtInt(successfulAttack), attackPct)
-	fmt.Fprintf(&sb, "Planets liberated:  %s\n", fmtInt(completedPlanets))
 
 	return strings.TrimRight(sb.String(), "\n")
 }
@@ -252,16 +223,23 @@ func pct(num, denom int) int {
 	return num * 100 / denom
 }
 
-func fmtInt(n int) string {
-	s := fmt.Sprintf("%d", n)
-	// Insert thousands separators.
-	out := make([]byte, 0, len(s)+len(s)/3)
-	for i, c := range s {
-		pos := len(s) - i
-		if i > 0 && pos%3 == 0 {
-			out = append(out, ',')
-		}
-		out = append(out, byte(c))
+// statisticsRows returns label/value pairs for the summed statistics.
+func (cat *Catalog) statisticsRows(c *CampaignStatus) [][2]string {
+	s := SumStatistics(c)
+	successful := func(total, ok int) string {
+		return fmt.Sprintf(cat.Successful, cat.FormatInt(total), cat.FormatInt(ok), pct(ok, total))
+	}
+	return [][2]string{
+		{cat.PlayersOnline, cat.FormatInt(s.Players)},
+		{cat.TotalPlayers, cat.FormatInt(s.TotalUniquePlayers)},
+		{cat.Kills, cat.FormatInt(s.Kills)},
+		{cat.Deaths, cat.FormatInt(s.Deaths)},
+		{cat.Accidentals, cat.FormatInt(s.Accidentals)},
+		{cat.ShotsFired, cat.FormatInt(s.Shots)},
+		{cat.Accuracy, fmt.Sprintf("%d%%", pct(s.Hits, s.Shots))},
+		{cat.Missions, successful(s.Missions, s.SuccessfulMissions)},
+		{cat.DefendEvents, successful(s.DefendEvents, s.SuccessfulDefendEvents)},
+		{cat.AttackEvents, successful(s.AttackEvents, s.SuccessfulAttackEvents)},
+		{cat.PlanetsLiberated, cat.FormatInt(s.CompletedPlanets)},
 	}
-	return string(out)
 }
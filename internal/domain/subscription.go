@@ -0,0 +1,26 @@
+package domain
+
+import "slices"
+
+// Subscription asks for matching events to be sent privately to one
+// recipient, such as a Discord user. Empty filters match any event.
+type Subscription struct {
+	ID       string      `json:"id"`
+	Factions []Enemy     `json:"factions,omitempty"`
+	Kinds    []EventKind `json:"kinds,omitempty"`
+	// ThreadID is the Telegram forum topic the subscription was made in, or 0.
+	ThreadID int `json:"thread_id,omitempty"`
+}
+
+// Matches reports whether msg passes the subscription's filters. A faction
+// filter only matches defend and attack events.
+func (s Subscription) Matches(msg EventMessage) bool {
+	if len(s.Kinds) > 0 && !slices.Contains(s.Kinds, msg.Kind) {
+		return false
+	}
+	if len(s.Factions) == 0 {
+		return true
+	}
+	e, ok := msg.Enemy()
+	return ok && slices.Contains(s.Factions, e)
+}
@@ -0,0 +1,32 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/ametis70/hellbot/internal/domain"
+	"github.com/ametis70/hellbot/internal/testutil"
+)
+
+func TestSubscription_Matches(t *testing.T) {
+	defend := domain.EventMessage{Kind: domain.EventKindDefend, DefendEvent: testutil.DefendEventActive()}
+	war := domain.EventMessage{Kind: domain.EventKindWar, WarEvent: &domain.WarEvent{}}
+
+	cases := []struct {
+		name string
+		sub  domain.Subscription
+		msg  domain.EventMessage
+		want bool
+	}{
+		{"no filters", domain.Subscription{}, war, true},
+		{"faction matches", domain.Subscription{Factions: []domain.Enemy{domain.EnemyIlluminate}}, defend, true},
+		{"other faction", domain.Subscription{Factions: []domain.Enemy{domain.EnemyBug}}, defend, false},
+		{"faction on war event", domain.Subscription{Factions: []domain.Enemy{domain.EnemyIlluminate}}, war, false},
+		{"kind matches", domain.Subscription{Kinds: []domain.EventKind{domain.EventKindWar}}, war, true},
+		{"other kind", domain.Subscription{Kinds: []domain.EventKind{domain.EventKindAttack}}, defend, false},
+	}
+	for _, c := range cases {
+		if got := c.sub.Matches(c.msg); got != c.want {
+			t.Errorf("%s: got %v, want %v", c.name, got, c.want)
+		}
+	}
+}
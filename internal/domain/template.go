@@ -9,7 +9,12 @@ import (
 // Templates holds message templates for each event kind and transition.
 // Each field is a string with {VARIABLE} placeholders.
 // Empty fields fall back to adapter-specific defaults.
+//
+// Engine selects how templates are rendered: TemplateEnginePlaceholder (the
+// default) or TemplateEngineGo for text/template actions and helpers.
 type Templates struct {
+	Engine string `yaml:"engine"`
+
 	DefendRegionStarted       string `yaml:"defend_region_started"`
 	DefendSuperEarthStarted   string `yaml:"defend_super_earth_started"`
 	DefendRegionSucceeded     string `yaml:"defend_region_succeeded"`
@@ -21,12 +26,20 @@ type Templates struct {
 	AttackFailed              string `yaml:"attack_failed"`
 	WarWon                    string `yaml:"war_won"`
 	WarLost                   string `yaml:"war_lost"`
+	PlayersAboveThreshold     string `yaml:"players_above_threshold"`
+	PlayersBelowThreshold     string `yaml:"players_below_threshold"`
+	PlayersSurge              string `yaml:"players_surge"`
+	PlayersDrop               string `yaml:"players_drop"`
+	MilestoneReached          string `yaml:"milestone_reached"`
 }
 
 // MergeTemplates merges user-provided templates over defaults.
 // Any non-empty field in user overrides the corresponding default field.
 func MergeTemplates(defaults, user Templates) Templates {
 	result := defaults
+	if user.Engine != "" {
+		result.Engine = user.Engine
+	}
 	if user.DefendRegionStarted != "" {
 		result.DefendRegionStarted = user.DefendRegionStarted
 	}
@@ -60,6 +73,21 @@ func MergeTemplates(defaults, user Templates) Templates {
 	if user.WarLost != "" {
 		result.WarLost = user.WarLost
 	}
+	if user.PlayersAboveThreshold != "" {
+		result.PlayersAboveThreshold = user.PlayersAboveThreshold
+	}
+	if user.PlayersBelowThreshold != "" {
+		result.PlayersBelowThreshold = user.PlayersBelowThreshold
+	}
+	if user.PlayersSurge != "" {
+		result.PlayersSurge = user.PlayersSurge
+	}
+	if user.PlayersDrop != "" {
+		result.PlayersDrop = user.PlayersDrop
+	}
+	if user.MilestoneReached != "" {
+		result.MilestoneReached = user.MilestoneReached
+	}
 	return result
 }
 
@@ -69,12 +97,27 @@ type TemplateVars struct {
 	Season             string
 	RegionName         string
 	RegionNumber       string
+	RegionCapital      string
 	TotalRegions       string
 	StartTimeFormatted string
 	EndTimeFormatted   string
 	StartTimeUnix      string
 	EndTimeUnix        string
+	Duration           string
+	TimeRemaining      string
+	EventID            string
+	MaxEventID         string
+	Points             string
+	PointsMax          string
+	Percent            string
 	Players            string
+	PreviousPlayers    string
+	Threshold          string
+	ChangePercent      string
+	Window             string
+	Counter            string
+	Milestone          string
+	Value              string
 }
 
 // Render substitutes all {VARIABLE} placeholders in a template string.
@@ -84,16 +127,34 @@ func Render(tmpl string, vars TemplateVars) string {
 		"{SEASON}", vars.Season,
 		"{REGION_NAME}", vars.RegionName,
 		"{REGION_NUMBER}", vars.RegionNumber,
+		"{REGION_CAPITAL}", vars.RegionCapital,
 		"{TOTAL_REGIONS}", vars.TotalRegions,
 		"{START_TIME_FORMATTED}", vars.StartTimeFormatted,
 		"{END_TIME_FORMATTED}", vars.EndTimeFormatted,
 		"{START_TIME_UNIX}", vars.StartTimeUnix,
 		"{END_TIME_UNIX}", vars.EndTimeUnix,
+		"{DURATION}", vars.Duration,
+		"{TIME_REMAINING}", vars.TimeRemaining,
+		"{EVENT_ID}", vars.EventID,
+		"{MAX_EVENT_ID}", vars.MaxEventID,
+		"{POINTS}", vars.Points,
+		"{POINTS_MAX}", vars.PointsMax,
+		"{PERCENT}", vars.Percent,
 		"{PLAYERS}", vars.Players,
+		"{PREVIOUS_PLAYERS}", vars.PreviousPlayers,
+		"{THRESHOLD}", vars.Threshold,
+		"{CHANGE_PERCENT}", vars.ChangePercent,
+		"{WINDOW}", vars.Window,
+		"{COUNTER}", vars.Counter,
+		"{MILESTONE}", vars.Milestone,
+		"{VALUE}", vars.Value,
 	)
 	return r.Replace(tmpl)
 }
 
+// now is the clock used for {TIME_REMAINING}. Tests replace it.
+var now = time.Now
+
 // BuildDefendVars builds template variables for a defend event.
 func BuildDefendVars(e *DefendEvent, formatTime func(time.Time) string) TemplateVars {
 	region := GetRegion(e.Enemy, e.Region)
@@ -101,36 +162,145 @@ func BuildDefendVars(e *DefendEvent, formatTime func(time.Time) string) Template
 		Faction:            e.Enemy.String(),
 		RegionName:         region.Name,
 		RegionNumber:       fmt.Sprintf("%d", e.Region),
+		RegionCapital:      region.Capital,
 		TotalRegions:       fmt.Sprintf("%d", TotalRegions),
 		StartTimeFormatted: formatTime(e.StartTime),
 		EndTimeFormatted:   formatTime(e.EndTime),
 		StartTimeUnix:      fmt.Sprintf("%d", e.StartTime.Unix()),
 		EndTimeUnix:        fmt.Sprintf("%d", e.EndTime.Unix()),
+		Duration:           formatDuration(e.EndTime.Sub(e.StartTime)),
+		TimeRemaining:      timeRemaining(e.EndTime),
+		EventID:            fmt.Sprintf("%d", e.ID),
+		Points:             fmt.Sprintf("%d", e.Points),
+		PointsMax:          fmt.Sprintf("%d", e.PointsMax),
+		Percent:            fmt.Sprintf("%d", pct(e.Points, e.PointsMax)),
 		Players:            fmt.Sprintf("%d", e.PlayersAtStart),
 	}
 }
 
 // BuildAttackVars builds template variables for an attack event.
+// Attacks always target the faction homeworld, so the region is HomeWorldRegion.
 func BuildAttackVars(e *AttackEvent, formatTime func(time.Time) string) TemplateVars {
+	region := GetRegion(e.Enemy, HomeWorldRegion)
 	return TemplateVars{
 		Faction:            e.Enemy.String(),
+		RegionName:         region.Name,
+		RegionNumber:       fmt.Sprintf("%d", HomeWorldRegion),
+		RegionCapital:      region.Capital,
 		StartTimeFormatted: formatTime(e.StartTime),
 		EndTimeFormatted:   formatTime(e.EndTime),
 		StartTimeUnix:      fmt.Sprintf("%d", e.StartTime.Unix()),
 		EndTimeUnix:        fmt.Sprintf("%d", e.EndTime.Unix()),
+		Duration:           formatDuration(e.EndTime.Sub(e.StartTime)),
+		TimeRemaining:      timeRemaining(e.EndTime),
+		EventID:            fmt.Sprintf("%d", e.ID),
+		MaxEventID:         fmt.Sprintf("%d", e.MaxEventID),
+		Points:             fmt.Sprintf("%d", e.Points),
+		PointsMax:          fmt.Sprintf("%d", e.PointsMax),
+		Percent:            fmt.Sprintf("%d", pct(e.Points, e.PointsMax)),
 		Players:            fmt.Sprintf("%d", e.PlayersAtStart),
 	}
 }
 
 // BuildWarVars builds template variables for a war event.
+// {DURATION} is empty when the war's length is unknown.
 func BuildWarVars(e *WarEvent) TemplateVars {
-	return TemplateVars{
+	vars := TemplateVars{
 		Season: fmt.Sprintf("%d", e.Season),
 	}
+	if e.Duration > 0 {
+		vars.Duration = formatDuration(e.Duration)
+	}
+	return vars
 }
 
-// RenderEvent picks the right template, builds vars, and renders the message.
+// BuildPlayersVars builds template variables for a player population alert.
+// {CHANGE_PERCENT} is rendered without a sign; the template conveys direction.
+func BuildPlayersVars(e *PlayersEvent) TemplateVars {
+	change := e.ChangePercent
+	if change < 0 {
+		change = -change
+	}
+	return TemplateVars{
+		Season:          fmt.Sprintf("%d", e.Season),
+		Players:         fmt.Sprintf("%d", e.Players),
+		PreviousPlayers: fmt.Sprintf("%d", e.Previous),
+		Threshold:       fmt.Sprintf("%d", e.Threshold),
+		ChangePercent:   fmt.Sprintf("%d", change),
+		Window:          formatDuration(e.Window),
+	}
+}
+
+// BuildMilestoneVars builds template variables for a milestone event.
+// Milestone and value use thousands separators.
+func BuildMilestoneVars(e *MilestoneEvent) TemplateVars {
+	return TemplateVars{
+		Season:    fmt.Sprintf("%d", e.Season),
+		Counter:   e.Counter.Label(),
+		Milestone: english.FormatInt(e.Milestone),
+		Value:     english.FormatInt(e.Value),
+	}
+}
+
+// localizeVars replaces the English names and numbers set by the Build*Vars
+// functions with the catalog's.
+func (cat *Catalog) localizeVars(vars TemplateVars, msg EventMessage) TemplateVars {
+	switch {
+	case msg.DefendEvent != nil:
+		vars.Faction = cat.FactionName(msg.DefendEvent.Enemy)
+	case msg.AttackEvent != nil:
+		vars.Faction = cat.FactionName(msg.AttackEvent.Enemy)
+	case msg.MilestoneEvent != nil:
+		vars.Counter = cat.CounterLabel(msg.MilestoneEvent.Counter)
+		vars.Milestone = cat.FormatInt(msg.MilestoneEvent.Milestone)
+		vars.Value = cat.FormatInt(msg.MilestoneEvent.Value)
+	}
+	return vars
+}
+
+// timeRemaining renders the time left until end, or "0m" once it has passed.
+func timeRemaining(end time.Time) string {
+	d := end.Sub(now())
+	if d < 0 {
+		d = 0
+	}
+	return formatDuration(d)
+}
+
+// formatDuration renders a duration as a compact "1h30m" style string.
+func formatDuration(d time.Duration) string {
+	h := int(d.Hours())
+	m := int(d.Minutes()) % 60
+	switch {
+	case h > 0 && m > 0:
+		return fmt.Sprintf("%dh%dm", h, m)
+	case h > 0:
+		return fmt.Sprintf("%dh", h)
+	default:
+		return fmt.Sprintf("%dm", m)
+	}
+}
+
+// RenderEvent picks the right template, builds vars, and renders the message
+// with English faction names and number formatting.
 func RenderEvent(templates Templates, msg EventMessage, formatTime func(time.Time) string) (string, error) {
+	return english.RenderEvent(templates, msg, formatTime)
+}
+
+// RenderEvent picks the right template, builds vars, and renders the message.
+// Faction names, counter labels and numbers in the vars use the catalog.
+func (cat *Catalog) RenderEvent(templates Templates, msg EventMessage, formatTime func(time.Time) string) (string, error) {
+	render := func(tmpl string, vars TemplateVars) (string, error) {
+		vars = cat.localizeVars(vars, msg)
+		switch templates.Engine {
+		case "", TemplateEnginePlaceholder:
+			return Render(tmpl, vars), nil
+		case TemplateEngineGo:
+			return renderGo(cat, tmpl, vars, msg, formatTime)
+		}
+		return "", fmt.Errorf("unknown template engine %q", templates.Engine)
+	}
+
 	switch msg.Kind {
 	case EventKindDefend:
 		if msg.DefendEvent == nil {
@@ -140,19 +310,19 @@ func RenderEvent(templates Templates, msg EventMessage, formatTime func(time.Tim
 		switch msg.Transition {
 		case EventTransitionStarted:
 			if IsSuperEarth(msg.DefendEvent.Region) {
-				return Render(templates.DefendSuperEarthStarted, vars), nil
+				return render(templates.DefendSuperEarthStarted, vars)
 			}
-			return Render(templates.DefendRegionStarted, vars), nil
+			return render(templates.DefendRegionStarted, vars)
 		case EventTransitionSucceeded:
 			if IsSuperEarth(msg.DefendEvent.Region) {
-				return Render(templates.DefendSuperEarthSucceeded, vars), nil
+				return render(templates.DefendSuperEarthSucceeded, vars)
 			}
-			return Render(templates.DefendRegionSucceeded, vars), nil
+			return render(templates.DefendRegionSucceeded, vars)
 		case EventTransitionFailed:
 			if IsSuperEarth(msg.DefendEvent.Region) {
-				return Render(templates.DefendSuperEarthFailed, vars), nil
+				return render(templates.DefendSuperEarthFailed, vars)
 			}
-			return Render(templates.DefendRegionFailed, vars), nil
+			return render(templates.DefendRegionFailed, vars)
 		}
 
 	case EventKindAttack:
@@ -162,11 +332,11 @@ func RenderEvent(templates Templates, msg EventMessage, formatTime func(time.Tim
 		vars := BuildAttackVars(msg.AttackEvent, formatTime)
 		switch msg.Transition {
 		case EventTransitionStarted:
-			return Render(templates.AttackHomeworldStarted, vars), nil
+			return render(templates.AttackHomeworldStarted, vars)
 		case EventTransitionSucceeded:
-			return Render(templates.AttackSucceeded, vars), nil
+			return render(templates.AttackSucceeded, vars)
 		case EventTransitionFailed:
-			return Render(templates.AttackFailed, vars), nil
+			return render(templates.AttackFailed, vars)
 		}
 
 	case EventKindWar:
@@ -176,9 +346,33 @@ func RenderEvent(templates Templates, msg EventMessage, formatTime func(time.Tim
 		vars := BuildWarVars(msg.WarEvent)
 		switch msg.Transition {
 		case EventTransitionSucceeded:
-			return Render(templates.WarWon, vars), nil
+			return render(templates.WarWon, vars)
 		case EventTransitionFailed:
-			return Render(templates.WarLost, vars), nil
+			return render(templates.WarLost, vars)
+		}
+
+	case EventKindPlayers:
+		if msg.PlayersEvent == nil {
+			return "", fmt.Errorf("players event is nil")
+		}
+		vars := BuildPlayersVars(msg.PlayersEvent)
+		switch {
+		case msg.PlayersEvent.Trigger == PlayersTriggerThreshold && msg.Transition == EventTransitionRose:
+			return render(templates.PlayersAboveThreshold, vars)
+		case msg.PlayersEvent.Trigger == PlayersTriggerThreshold && msg.Transition == EventTransitionFell:
+			return render(templates.PlayersBelowThreshold, vars)
+		case msg.PlayersEvent.Trigger == PlayersTriggerChange && msg.Transition == EventTransitionRose:
+			return render(templates.PlayersSurge, vars)
+		case msg.PlayersEvent.Trigger == PlayersTriggerChange && msg.Transition == EventTransitionFell:
+			return render(templates.PlayersDrop, vars)
+		}
+
+	case EventKindMilestone:
+		if msg.MilestoneEvent == nil {
+			return "", fmt.Errorf("milestone event is nil")
+		}
+		if msg.Transition == EventTransitionReached {
+			return render(templates.MilestoneReached, BuildMilestoneVars(msg.MilestoneEvent))
 		}
 	}
 
@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Template engines selectable via Templates.Engine.
+const (
+	// TemplateEnginePlaceholder substitutes {VARIABLE} placeholders only. Default.
+	TemplateEnginePlaceholder = "placeholder"
+	// TemplateEngineGo executes templates with text/template before placeholder
+	// substitution, so {VARIABLE} placeholders keep working alongside actions.
+	TemplateEngineGo = "go"
+)
+
+// TemplateData is the value passed to templates rendered by the "go" engine.
+// The event fields are promoted, so templates can use {{.DefendEvent.Region}},
+// {{.Transition}} and so on. Vars holds the same strings as the placeholders.
+type TemplateData struct {
+	EventMessage
+	Vars TemplateVars
+}
+
+// templateFuncs returns the helper functions available to "go" templates.
+// formatTime is the notifier's time formatter.
+func templateFuncs(cat *Catalog, formatTime func(time.Time) string) template.FuncMap {
+	return template.FuncMap{
+		"commas":       cat.FormatInt,
+		"percent":      percent,
+		"duration":     formatDuration,
+		"upper":        strings.ToUpper,
+		"lower":        strings.ToLower,
+		"relativeTime": cat.relativeTime,
+		"formatTime":   formatTime,
+	}
+}
+
+// percent renders part as a whole percentage of total, e.g. "42%".
+func percent(part, total int) string {
+	return fmt.Sprintf("%d%%", pct(part, total))
+}
+
+// renderGo executes tmpl as a text/template and then substitutes placeholders.
+func renderGo(cat *Catalog, tmpl string, vars TemplateVars, msg EventMessage, formatTime func(time.Time) string) (string, error) {
+	t, err := template.New("message").Funcs(templateFuncs(cat, formatTime)).Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("parse template: %w", err)
+	}
+	var sb strings.Builder
+	if err := t.Execute(&sb, TemplateData{EventMessage: msg, Vars: vars}); err != nil {
+		return "", fmt.Errorf("execute template: %w", err)
+	}
+	return Render(sb.String(), vars), nil
+}
@@ -0,0 +1,107 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRenderEvent_GoEngine_Conditionals(t *testing.T) {
+	tmpl := Templates{
+		Engine:              TemplateEngineGo,
+		DefendRegionStarted: `{{if gt .DefendEvent.PlayersAtStart 50}}Big{{else}}Small{{end}} push by the {FACTION} on {{upper .Vars.RegionName}}`,
+	}
+	msg := EventMessage{Kind: EventKindDefend, Transition: EventTransitionStarted, DefendEvent: fixedDefendEvent(5, EnemyBug)}
+
+	got, err := RenderEvent(tmpl, msg, identityFormatter)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "Big push by the Bugs on " + strings.ToUpper(GetRegion(EnemyBug, 5).Name)
+	if got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestRenderEvent_GoEngine_Helpers(t *testing.T) {
+	tmpl := Templates{
+		Engine:       TemplateEngineGo,
+		PlayersSurge: `{{commas .PlayersEvent.Players}} online, {{percent .PlayersEvent.Previous .PlayersEvent.Players}} of now, over {{duration .PlayersEvent.Window}}`,
+	}
+	msg := EventMessage{
+		Kind:       EventKindPlayers,
+		Transition: EventTransitionRose,
+		PlayersEvent: &PlayersEvent{
+			Trigger:  PlayersTriggerChange,
+			Players:  12000,
+			Previous: 6000,
+			Window:   90 * time.Minute,
+		},
+	}
+
+	got, err := RenderEvent(tmpl, msg, identityFormatter)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "12,000 online, 50% of now, over 1h30m"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestRenderEvent_GoEngine_FormatTime(t *testing.T) {
+	tmpl := Templates{
+		Engine:          TemplateEngineGo,
+		AttackSucceeded: `ended {{formatTime .AttackEvent.EndTime}}`,
+	}
+	e := fixedAttackEvent(EnemyCyborg)
+	msg := EventMessage{Kind: EventKindAttack, Transition: EventTransitionSucceeded, AttackEvent: e}
+
+	got, err := RenderEvent(tmpl, msg, identityFormatter)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "ended " + identityFormatter(e.EndTime); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestRenderEvent_GoEngine_Errors(t *testing.T) {
+	msg := EventMessage{Kind: EventKindWar, Transition: EventTransitionSucceeded, WarEvent: &WarEvent{Season: 159}}
+
+	if _, err := RenderEvent(Templates{Engine: TemplateEngineGo, WarWon: "{{if}}"}, msg, identityFormatter); err == nil {
+		t.Error("expected parse error, got nil")
+	}
+	if _, err := RenderEvent(Templates{Engine: TemplateEngineGo, WarWon: "{{.Nope}}"}, msg, identityFormatter); err == nil {
+		t.Error("expected execution error, got nil")
+	}
+	if _, err := RenderEvent(Templates{Engine: "jinja", WarWon: "x"}, msg, identityFormatter); err == nil {
+		t.Error("expected unknown engine error, got nil")
+	}
+}
+
+func TestRenderEvent_PlaceholderEngineIgnoresActions(t *testing.T) {
+	msg := EventMessage{Kind: EventKindWar, Transition: EventTransitionSucceeded, WarEvent: &WarEvent{Season: 159}}
+	got, err := RenderEvent(Templates{WarWon: "{{.Kind}} {SEASON}"}, msg, identityFormatter)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "{{.Kind}} 159" {
+		t.Errorf("expected actions to be left as-is, got %q", got)
+	}
+}
+
+func TestFormatInt_Negative(t *testing.T) {
+	if got := english.FormatInt(-100); got != "-100" {
+		t.Errorf("expected -100, got %q", got)
+	}
+	if got := english.FormatInt(-1234567); got != "-1,234,567" {
+		t.Errorf("expected -1,234,567, got %q", got)
+	}
+}
+
+func TestMergeTemplates_Engine(t *testing.T) {
+	got := MergeTemplates(Templates{WarWon: "x"}, Templates{Engine: TemplateEngineGo})
+	if got.Engine != TemplateEngineGo || got.WarWon != "x" {
+		t.Errorf("unexpected merge result: %+v", got)
+	}
+}
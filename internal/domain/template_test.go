@@ -294,3 +294,84 @@ func TestRenderEvent_UnixTimestampVariable(t *testing.T) {
 		t.Errorf("expected unix timestamp %s, got: %s", expected, result)
 	}
 }
+
+func withNow(t *testing.T, tm time.Time) {
+	t.Helper()
+	orig := now
+	now = func() time.Time { return tm }
+	t.Cleanup(func() { now = orig })
+}
+
+func TestBuildDefendVars_EventDetails(t *testing.T) {
+	withNow(t, fixedTime.Add(12*time.Hour+30*time.Minute))
+	vars := BuildDefendVars(fixedDefendEvent(5, EnemyIlluminate), identityFormatter)
+
+	want := TemplateVars{
+		RegionCapital: "New Alexandria",
+		EventID:       "5080",
+		Points:        "486",
+		PointsMax:     "31602",
+		Percent:       "1",
+		Duration:      "48h",
+		TimeRemaining: "35h30m",
+	}
+	got := TemplateVars{
+		RegionCapital: vars.RegionCapital,
+		EventID:       vars.EventID,
+		Points:        vars.Points,
+		PointsMax:     vars.PointsMax,
+		Percent:       vars.Percent,
+		Duration:      vars.Duration,
+		TimeRemaining: vars.TimeRemaining,
+	}
+	if got != want {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestBuildAttackVars_Homeworld(t *testing.T) {
+	withNow(t, fixedTime.Add(72*time.Hour))
+	e := fixedAttackEvent(EnemyCyborg)
+	e.MaxEventID = 925
+	e.Points = 15788
+	vars := BuildAttackVars(e, identityFormatter)
+
+	if vars.RegionName != "Cyberstan Region" || vars.RegionCapital != "Cyberstan" || vars.RegionNumber != "11" {
+		t.Errorf("expected homeworld region, got %q / %q / %q", vars.RegionName, vars.RegionCapital, vars.RegionNumber)
+	}
+	if vars.EventID != "924" || vars.MaxEventID != "925" {
+		t.Errorf("unexpected event IDs: %q / %q", vars.EventID, vars.MaxEventID)
+	}
+	if vars.Percent != "50" {
+		t.Errorf("expected 50 percent, got %q", vars.Percent)
+	}
+	if vars.TimeRemaining != "0m" {
+		t.Errorf("expected ended event to have 0m remaining, got %q", vars.TimeRemaining)
+	}
+}
+
+func TestBuildWarVars_Duration(t *testing.T) {
+	if vars := BuildWarVars(&WarEvent{Season: 159}); vars.Duration != "" {
+		t.Errorf("expected empty duration when unknown, got %q", vars.Duration)
+	}
+	if vars := BuildWarVars(&WarEvent{Season: 159, Duration: 30 * 24 * time.Hour}); vars.Duration != "720h" {
+		t.Errorf("expected 720h, got %q", vars.Duration)
+	}
+}
+
+func TestRender_EventDetailVars(t *testing.T) {
+	vars := TemplateVars{
+		RegionCapital: "Cyberstan",
+		EventID:       "924",
+		MaxEventID:    "925",
+		Points:        "10",
+		PointsMax:     "20",
+		Percent:       "50",
+		Duration:      "48h",
+		TimeRemaining: "2h5m",
+	}
+	got := Render("{REGION_CAPITAL} {EVENT_ID}/{MAX_EVENT_ID} {POINTS}/{POINTS_MAX} {PERCENT}% {DURATION} {TIME_REMAINING}", vars)
+	if want := "Cyberstan 924/925 10/20 50% 48h 2h5m"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,124 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"text/template"
+)
+
+// placeholderPattern matches {VARIABLE} placeholders. It does not match Go
+// template actions such as {{.Kind}}.
+var placeholderPattern = regexp.MustCompile(`\{([A-Z][A-Z0-9_]*)\}`)
+
+// Placeholder sets available to each family of templates.
+var (
+	eventVars = []string{
+		"FACTION", "REGION_NAME", "REGION_NUMBER", "REGION_CAPITAL", "PLAYERS",
+		"START_TIME_FORMATTED", "END_TIME_FORMATTED", "START_TIME_UNIX", "END_TIME_UNIX",
+		"DURATION", "TIME_REMAINING", "EVENT_ID", "POINTS", "POINTS_MAX", "PERCENT",
+	}
+	defendVars      = append([]string{"TOTAL_REGIONS"}, eventVars...)
+	attackVars      = append([]string{"MAX_EVENT_ID"}, eventVars...)
+	warVars         = []string{"SEASON", "DURATION"}
+	thresholdVars   = []string{"SEASON", "PLAYERS", "PREVIOUS_PLAYERS", "THRESHOLD"}
+	changeVars      = []string{"SEASON", "PLAYERS", "PREVIOUS_PLAYERS", "CHANGE_PERCENT", "WINDOW"}
+	milestoneVars   = []string{"SEASON", "COUNTER", "MILESTONE", "VALUE"}
+	presenceWarVars = []string{"FACTION", "SEASON", "PERCENT"}
+	knownVariables  = variableSet(defendVars, attackVars, warVars, thresholdVars, changeVars, milestoneVars)
+	templateEngines = []string{"", TemplateEnginePlaceholder, TemplateEngineGo}
+)
+
+// TemplateProblem describes an invalid template. Field is the YAML key.
+type TemplateProblem struct {
+	Field   string
+	Message string
+}
+
+func (p TemplateProblem) String() string {
+	return fmt.Sprintf("%s: %s", p.Field, p.Message)
+}
+
+type templateField struct {
+	key   string
+	value string
+	vars  []string
+}
+
+// fields lists every template with the placeholders it can use.
+func (t Templates) fields() []templateField {
+	return []templateField{
+		{"defend_region_started", t.DefendRegionStarted, defendVars},
+		{"defend_super_earth_started", t.DefendSuperEarthStarted, defendVars},
+		{"defend_region_succeeded", t.DefendRegionSucceeded, defendVars},
+		{"defend_super_earth_succeeded", t.DefendSuperEarthSucceeded, defendVars},
+		{"defend_region_failed", t.DefendRegionFailed, defendVars},
+		{"defend_super_earth_failed", t.DefendSuperEarthFailed, defendVars},
+		{"attack_homeworld_started", t.AttackHomeworldStarted, attackVars},
+		{"attack_succeeded", t.AttackSucceeded, attackVars},
+		{"attack_failed", t.AttackFailed, attackVars},
+		{"war_won", t.WarWon, warVars},
+		{"war_lost", t.WarLost, warVars},
+		{"players_above_threshold", t.PlayersAboveThreshold, thresholdVars},
+		{"players_below_threshold", t.PlayersBelowThreshold, thresholdVars},
+		{"players_surge", t.PlayersSurge, changeVars},
+		{"players_drop", t.PlayersDrop, changeVars},
+		{"milestone_reached", t.MilestoneReached, milestoneVars},
+	}
+}
+
+// ValidateTemplates checks every non-empty template for unknown placeholders,
+// placeholders that are not available in that template, and — with the "go"
+// engine — syntax errors. Problems are returned in field order.
+func ValidateTemplates(t Templates) []TemplateProblem {
+	var problems []TemplateProblem
+	if !slices.Contains(templateEngines, t.Engine) {
+		problems = append(problems, TemplateProblem{"engine", fmt.Sprintf("unknown engine %q", t.Engine)})
+	}
+	return append(problems, validateFields(t.fields(), t.Engine)...)
+}
+
+// ValidatePresence checks the bot status formats shown during a defend, an
+// attack and otherwise. They always use {VARIABLE} placeholders; the war
+// format only has the leading faction, the season and its progress.
+func ValidatePresence(defend, attack, war string) []TemplateProblem {
+	return validateFields([]templateField{
+		{"defend", defend, defendVars},
+		{"attack", attack, attackVars},
+		{"war", war, presenceWarVars},
+	}, TemplateEnginePlaceholder)
+}
+
+func validateFields(fields []templateField, engine string) []TemplateProblem {
+	var problems []TemplateProblem
+	for _, f := range fields {
+		if f.value == "" {
+			continue
+		}
+		if engine == TemplateEngineGo {
+			if _, err := template.New(f.key).Funcs(templateFuncs(english, nil)).Parse(f.value); err != nil {
+				problems = append(problems, TemplateProblem{f.key, err.Error()})
+			}
+		}
+		for _, m := range placeholderPattern.FindAllStringSubmatch(f.value, -1) {
+			name := m[1]
+			switch {
+			case !knownVariables[name]:
+				problems = append(problems, TemplateProblem{f.key, fmt.Sprintf("unknown placeholder %s", m[0])})
+			case !slices.Contains(f.vars, name):
+				problems = append(problems, TemplateProblem{f.key, fmt.Sprintf("placeholder %s is not available here", m[0])})
+			}
+		}
+	}
+	return problems
+}
+
+func variableSet(groups ...[]string) map[string]bool {
+	set := make(map[string]bool)
+	for _, g := range groups {
+		for _, v := range g {
+			set[v] = true
+		}
+	}
+	return set
+}
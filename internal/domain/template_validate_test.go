@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateTemplates_Valid(t *testing.T) {
+	tmpl := Templates{
+		DefendRegionStarted: "{FACTION} on {REGION_NAME} until <t:{END_TIME_UNIX}:R>",
+		WarWon:              "War {SEASON} won",
+		PlayersSurge:        "{PLAYERS} (+{CHANGE_PERCENT}% in {WINDOW})",
+		MilestoneReached:    "{MILESTONE} {COUNTER}",
+	}
+	if problems := ValidateTemplates(tmpl); len(problems) != 0 {
+		t.Errorf("expected no problems, got %v", problems)
+	}
+}
+
+func TestValidateTemplates_UnknownPlaceholder(t *testing.T) {
+	problems := ValidateTemplates(Templates{DefendRegionStarted: "{REGON_NAME}"})
+	if len(problems) != 1 {
+		t.Fatalf("expected 1 problem, got %v", problems)
+	}
+	if problems[0].Field != "defend_region_started" || !strings.Contains(problems[0].Message, "unknown placeholder {REGON_NAME}") {
+		t.Errorf("unexpected problem: %v", problems[0])
+	}
+}
+
+func TestValidateTemplates_PlaceholderNotAvailable(t *testing.T) {
+	problems := ValidateTemplates(Templates{WarWon: "{SEASON} {REGION_NAME}", AttackFailed: "{THRESHOLD}"})
+	if len(problems) != 2 {
+		t.Fatalf("expected 2 problems, got %v", problems)
+	}
+	if problems[0].Field != "attack_failed" || problems[1].Field != "war_won" {
+		t.Errorf("expected problems in field order, got %v", problems)
+	}
+	if !strings.Contains(problems[1].Message, "{REGION_NAME} is not available") {
+		t.Errorf("unexpected message: %s", problems[1].Message)
+	}
+}
+
+func TestValidateTemplates_GoEngine(t *testing.T) {
+	problems := ValidateTemplates(Templates{
+		Engine:       TemplateEngineGo,
+		WarWon:       "{{if}}",
+		WarLost:      "{{upper .Vars.Season}} {SEASON}",
+		PlayersSurge: "{{.Missing}}",
+	})
+	if len(problems) != 1 || problems[0].Field != "war_won" {
+		t.Errorf("expected only a war_won syntax error, got %v", problems)
+	}
+}
+
+func TestValidateTemplates_PlaceholderEngineIgnoresActions(t *testing.T) {
+	if problems := ValidateTemplates(Templates{WarWon: "{{if}}"}); len(problems) != 0 {
+		t.Errorf("expected actions to be ignored, got %v", problems)
+	}
+}
+
+func TestValidateTemplates_UnknownEngine(t *testing.T) {
+	problems := ValidateTemplates(Templates{Engine: "jinja"})
+	if len(problems) != 1 || problems[0].Field != "engine" {
+		t.Errorf("expected engine problem, got %v", problems)
+	}
+}
+
+func TestValidatePresence(t *testing.T) {
+	problems := ValidatePresence("Defending {REGION_NAME}", "{FACTON} homeworld", "War {SEASON}: {REGION_NAME}")
+	if len(problems) != 2 {
+		t.Fatalf("expected 2 problems, got %v", problems)
+	}
+	if problems[0].Field != "attack" || problems[0].Message != "unknown placeholder {FACTON}" {
+		t.Errorf("unexpected problem: %s", problems[0])
+	}
+	if problems[1].Field != "war" || problems[1].Message != "placeholder {REGION_NAME} is not available here" {
+		t.Errorf("unexpected problem: %s", problems[1])
+	}
+}
@@ -0,0 +1,11 @@
+package port
+
+import "net/http"
+
+// HTTPRouter is implemented by notifiers that receive requests on hellbot's
+// HTTP server, e.g. Telegram webhook updates.
+type HTTPRouter interface {
+	// HTTPRoute returns a net/http ServeMux pattern and its handler. An empty
+	// pattern means the notifier has nothing to serve.
+	HTTPRoute() (pattern string, handler http.Handler)
+}
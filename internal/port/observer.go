@@ -0,0 +1,10 @@
+package port
+
+import "github.com/ametis70/hellbot/internal/domain"
+
+// CampaignObserver is implemented by notifiers that react to every successful
+// poll rather than to events, e.g. to keep a live status message up to date.
+// ObserveCampaign is called synchronously and must handle its own errors.
+type CampaignObserver interface {
+	ObserveCampaign(c *domain.CampaignStatus)
+}
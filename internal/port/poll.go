@@ -0,0 +1,13 @@
+package port
+
+// PollTrigger runs a poll outside the regular interval. TriggerPoll does not
+// wait for the poll; a request made while one is pending is dropped.
+type PollTrigger interface {
+	TriggerPoll()
+}
+
+// PollTriggerUser is implemented by notifiers whose commands can force a
+// poll, e.g. Discord admin commands.
+type PollTriggerUser interface {
+	UsePollTrigger(t PollTrigger)
+}
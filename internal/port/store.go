@@ -13,3 +13,27 @@ type EventStore interface {
 	GetOngoingEvent(id int, kind domain.EventKind) (*domain.OngoingEvent, error)
 	ListOngoingEvents(kind domain.EventKind) ([]*domain.OngoingEvent, error)
 }
+
+// MilestoneStore remembers which milestones were already announced so each
+// one is sent only once per season.
+type MilestoneStore interface {
+	SaveMilestone(season int, counter domain.MilestoneCounter, milestone int) error
+	HasMilestone(season int, counter domain.MilestoneCounter, milestone int) (bool, error)
+}
+
+// MessageRefStore remembers the IDs of messages a notifier keeps editing, such
+// as a live status board, so they survive restarts. GetMessageRef returns ""
+// when nothing is stored for key.
+type MessageRefStore interface {
+	SaveMessageRef(key, id string) error
+	GetMessageRef(key string) (string, error)
+}
+
+// SubscriptionStore persists per-recipient subscriptions. scope separates
+// platforms, e.g. "discord", so recipient IDs never collide. Saving replaces
+// any subscription with the same ID; removing an unknown ID is not an error.
+type SubscriptionStore interface {
+	SaveSubscription(scope string, s domain.Subscription) error
+	RemoveSubscription(scope, id string) error
+	ListSubscriptions(scope string) ([]domain.Subscription, error)
+}
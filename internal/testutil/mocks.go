@@ -70,8 +70,8 @@ func (m *MockNotifier) Last() *domain.EventMessage {
 	return &m.Messages[len(m.Messages)-1]
 }
 
-// ErrorStore implements port.CampaignStore and port.EventStore, returning
-// errors for every operation. Used to test error-handling paths in the poller.
+// ErrorStore implements port.CampaignStore, port.EventStore and port.MilestoneStore,
+// returning errors for every operation. Used to test error-handling paths in the poller.
 type ErrorStore struct{}
 
 func (e *ErrorStore) SaveCampaign(_ *domain.CampaignStatus) error {
@@ -97,3 +97,11 @@ func (e *ErrorStore) GetOngoingEvent(_ int, _ domain.EventKind) (*domain.Ongoing
 func (e *ErrorStore) ListOngoingEvents(_ domain.EventKind) ([]*domain.OngoingEvent, error) {
 	return nil, errors.New("store error")
 }
+
+func (e *ErrorStore) SaveMilestone(_ int, _ domain.MilestoneCounter, _ int) error {
+	return errors.New("store error")
+}
+
+func (e *ErrorStore) HasMilestone(_ int, _ domain.MilestoneCounter, _ int) (bool, error) {
+	return false, errors.New("store error")
+}